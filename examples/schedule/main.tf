@@ -41,3 +41,15 @@ resource "ilert_schedule" "example_static" {
     end   = formatdate("YYYY-MM-DD'T'hh:mm:ss", timeadd(timestamp(), "754h"))
   }
 }
+
+# example for a temporary override of the recurring schedule
+resource "ilert_schedule_override" "example" {
+  schedule_id = ilert_schedule.example_recurring.id
+  user        = ilert_user.example.id
+  start       = formatdate("YYYY-MM-DD'T'hh:mm", timeadd(timestamp(), "754h"))
+  end         = formatdate("YYYY-MM-DD'T'hh:mm", timeadd(timestamp(), "778h"))
+
+  lifecycle {
+    ignore_changes = [start, end]
+  }
+}
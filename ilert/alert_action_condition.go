@@ -0,0 +1,193 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// alertActionConditionDepth bounds how deep condition groups nest.
+var alertActionConditionDepth = 3
+
+// resourceAlertActionConditionGroup is a condition block: predicates and
+// nested groups joined by AND or OR, compiled into an ICL expression over the
+// alert, see compileAlertActionCondition.
+func resourceAlertActionConditionGroup(depth int) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"operator": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "AND",
+			ValidateFunc: validation.StringInSlice(ilert.AlertFilterOperatorAll, false),
+		},
+		"predicate": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"field": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice(ilert.AlertFilterPredicateFieldsAll, false),
+					},
+					"criteria": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice(ilert.AlertFilterPredicateCriteriaAll, false),
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+				},
+			},
+		},
+	}
+	if depth > 0 {
+		s["group"] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     resourceAlertActionConditionGroup(depth - 1),
+		}
+	}
+	return &schema.Resource{Schema: s}
+}
+
+// compileAlertActionCondition compiles a condition block into the ICL
+// expression the API expects in conditions, e.g.
+// alert.priority == "HIGH" && (alert.summary contains "db" || ...).
+func compileAlertActionCondition(group map[string]any) (string, error) {
+	terms, join, err := compileAlertActionConditionTerms(group)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(terms, join), nil
+}
+
+func compileAlertActionConditionTerms(group map[string]any) ([]string, string, error) {
+	join := " && "
+	if operator, _ := group["operator"].(string); operator == "OR" {
+		join = " || "
+	}
+
+	terms := make([]string, 0)
+	pL, _ := group["predicate"].([]any)
+	for i, it := range pL {
+		predicate, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		term, err := compileAlertActionPredicate(predicate)
+		if err != nil {
+			return nil, "", fmt.Errorf("predicate %d: %s", i, err.Error())
+		}
+		terms = append(terms, term)
+	}
+	gL, _ := group["group"].([]any)
+	for i, it := range gL {
+		nested, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		nestedTerms, nestedJoin, err := compileAlertActionConditionTerms(nested)
+		if err != nil {
+			return nil, "", fmt.Errorf("group %d: %s", i, err.Error())
+		}
+		switch len(nestedTerms) {
+		case 0:
+		case 1:
+			terms = append(terms, nestedTerms[0])
+		default:
+			terms = append(terms, "("+strings.Join(nestedTerms, nestedJoin)+")")
+		}
+	}
+	return terms, join, nil
+}
+
+func compileAlertActionPredicate(predicate map[string]any) (string, error) {
+	field, _ := predicate["field"].(string)
+	criteria, _ := predicate["criteria"].(string)
+	value, _ := predicate["value"].(string)
+	path := alertActionConditionFieldPath(field)
+
+	switch criteria {
+	case "CONTAINS_ANY_WORDS", "CONTAINS_NOT_WORDS":
+		words := make([]string, 0)
+		for _, word := range strings.Split(value, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				words = append(words, quoteICL(word))
+			}
+		}
+		term := fmt.Sprintf("%s contains_any [%s]", path, strings.Join(words, ", "))
+		if criteria == "CONTAINS_NOT_WORDS" {
+			return "!(" + term + ")", nil
+		}
+		return term, nil
+	case "CONTAINS_STRING":
+		return fmt.Sprintf("%s contains %s", path, quoteICL(value)), nil
+	case "CONTAINS_NOT_STRING":
+		return fmt.Sprintf("!(%s contains %s)", path, quoteICL(value)), nil
+	case "IS_STRING":
+		return fmt.Sprintf("%s == %s", path, quoteICL(value)), nil
+	case "IS_NOT_STRING":
+		return fmt.Sprintf("%s != %s", path, quoteICL(value)), nil
+	case "MATCHES_REGEX":
+		return fmt.Sprintf("%s matches %s", path, quoteICL(value)), nil
+	case "MATCHES_NOT_REGEX":
+		return fmt.Sprintf("!(%s matches %s)", path, quoteICL(value)), nil
+	}
+	return "", fmt.Errorf("unsupported criteria %q", criteria)
+}
+
+// alertActionConditionFieldPath maps a predicate field to its path in the
+// alert, e.g. ALERT_SUMMARY to alert.summary and ESCALATION_POLICY to
+// alert.escalationPolicy.
+func alertActionConditionFieldPath(field string) string {
+	words := strings.Split(strings.ToLower(strings.TrimPrefix(field, "ALERT_")), "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return "alert." + strings.Join(words, "")
+}
+
+// alertActionConditionExpression compiles the first block of a condition list,
+// as read from the configuration.
+func alertActionConditionExpression(v any) (string, error) {
+	cL, _ := v.([]any)
+	if len(cL) == 0 || cL[0] == nil {
+		return "", nil
+	}
+	return compileAlertActionCondition(cL[0].(map[string]any))
+}
+
+// customizeAlertActionConditions compiles the condition block into conditions
+// at plan time, so the plan shows the expression that is sent to the API.
+func customizeAlertActionConditions(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diffValuesKnown(diff, "condition") {
+		return diff.SetNewComputed("conditions")
+	}
+	if cL, _ := diff.Get("condition").([]any); len(cL) == 0 {
+		// conditions is computed for the condition block, so removing it from
+		// the configuration has to clear it explicitly.
+		config := diff.GetRawConfig()
+		if config.IsKnown() && !config.IsNull() && config.GetAttr("conditions").IsNull() && diff.Get("conditions").(string) != "" {
+			return diff.SetNew("conditions", "")
+		}
+		return nil
+	}
+
+	expression, err := alertActionConditionExpression(diff.Get("condition"))
+	if err != nil {
+		return fmt.Errorf("[ERROR] Could not compile condition: %s", err.Error())
+	}
+	if old, _ := diff.GetChange("conditions"); suppressEquivalentICL("conditions", old.(string), expression, nil) {
+		return nil
+	}
+	return diff.SetNew("conditions", expression)
+}
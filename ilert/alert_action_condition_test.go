@@ -0,0 +1,93 @@
+package ilert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var testAlertActionCondition = map[string]any{
+	"operator": "AND",
+	"predicate": []any{
+		map[string]any{"field": "ALERT_PRIORITY", "criteria": "IS_STRING", "value": "HIGH"},
+	},
+	"group": []any{
+		map[string]any{
+			"operator": "OR",
+			"predicate": []any{
+				map[string]any{"field": "ALERT_SUMMARY", "criteria": "CONTAINS_ANY_WORDS", "value": "database, disk"},
+				map[string]any{"field": "ALERT_DETAILS", "criteria": "MATCHES_REGEX", "value": `host-\d+`},
+			},
+		},
+		map[string]any{
+			"predicate": []any{
+				map[string]any{"field": "ALERT_SUMMARY", "criteria": "CONTAINS_NOT_STRING", "value": `say "test"`},
+			},
+		},
+	},
+}
+
+func TestCompileAlertActionCondition(t *testing.T) {
+	got, err := compileAlertActionCondition(testAlertActionCondition)
+	if err != nil {
+		t.Fatalf("unexpected error compiling the condition: %v", err)
+	}
+	want := `alert.priority == "HIGH" && (alert.summary contains_any ["database", "disk"] || alert.details matches "host-\\d+") && !(alert.summary contains "say \"test\"")`
+	if got != want {
+		t.Fatalf("unexpected expression\n got: %s\nwant: %s", got, want)
+	}
+	if _, err := parseICL(got); err != nil {
+		t.Fatalf("compiled expression does not parse: %v", err)
+	}
+}
+
+func TestAlertActionConditionFieldPath(t *testing.T) {
+	cases := map[string]string{
+		"ALERT_SUMMARY":     "alert.summary",
+		"ALERT_PRIORITY":    "alert.priority",
+		"ESCALATION_POLICY": "alert.escalationPolicy",
+	}
+	for field, want := range cases {
+		if got := alertActionConditionFieldPath(field); got != want {
+			t.Errorf("alertActionConditionFieldPath(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestDataSourceAlertActionConditionRead(t *testing.T) {
+	cases := []struct {
+		alert string
+		want  bool
+	}{
+		{`{"priority": "HIGH", "summary": "disk full on db-1", "details": ""}`, true},
+		{`{"priority": "HIGH", "summary": "CPU load", "details": "seen on host-12"}`, true},
+		{`{"priority": "LOW", "summary": "disk full on db-1"}`, false},
+		{`{"priority": "HIGH", "summary": "disk full, say \"test\""}`, false},
+	}
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceAlertActionCondition().Schema, map[string]any{
+			"condition": []any{testAlertActionCondition},
+			"alert":     tc.alert,
+		})
+		if diags := dataSourceAlertActionConditionRead(context.Background(), d, nil); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if got := d.Get("matches").(bool); got != tc.want {
+			t.Errorf("matches for %s = %t, want %t", tc.alert, got, tc.want)
+		}
+	}
+}
+
+func TestDataSourceAlertActionConditionRead_Conditions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceAlertActionCondition().Schema, map[string]any{
+		"conditions": `alert.labels.env in ["prod", "staging"]`,
+		"alert":      `{"labels": {"env": "prod"}}`,
+	})
+	if diags := dataSourceAlertActionConditionRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !d.Get("matches").(bool) || d.Get("expression").(string) != `alert.labels.env in ["prod", "staging"]` {
+		t.Fatalf("unexpected result: matches=%v expression=%v", d.Get("matches"), d.Get("expression"))
+	}
+}
@@ -0,0 +1,155 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func callFlowDefinitionSchema() flowDefinitionSchema {
+	return flowDefinitionSchema{
+		node:   resourceCallFlowNodeNoBranches().Schema,
+		branch: resourceCallFlowBranch(0).Schema,
+	}
+}
+
+// callFlowTargetTypeNumber is the type of call targets that are phone numbers.
+const callFlowTargetTypeNumber = "NUMBER"
+
+// parseCallFlowDefinition reads a definition into the shape of a root_node
+// block, validating it against the call flow node schema on the way.
+func parseCallFlowDefinition(definition string) (map[string]any, error) {
+	node, err := parseFlowDefinition(definition, callFlowDefinitionSchema())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCallFlowPhoneTargets(node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// renderCallFlowDefinition renders a root_node tree as a call flow definition
+// in its canonical form.
+func renderCallFlowDefinition(node map[string]any) (string, error) {
+	return renderFlowDefinition(node, callFlowDefinitionSchema())
+}
+
+// canonicalCallFlowDefinition parses a definition and renders it back in its
+// canonical form, with phone number targets in E.164 format.
+func canonicalCallFlowDefinition(definition string) (string, error) {
+	node, err := parseCallFlowDefinition(definition)
+	if err != nil {
+		return "", err
+	}
+	walkCallFlowTargets(node, func(target map[string]any) error {
+		if t, ok := target["type"].(string); ok && t == callFlowTargetTypeNumber {
+			target["target"], _ = normalizePhoneNumber("", target["target"].(string))
+		}
+		return nil
+	})
+	return renderCallFlowDefinition(node)
+}
+
+// validateCallFlowPhoneTargets checks that the targets of type NUMBER in a
+// root_node tree are phone numbers in E.164 format.
+func validateCallFlowPhoneTargets(node any) error {
+	return walkCallFlowTargets(node, func(target map[string]any) error {
+		if t, ok := target["type"].(string); !ok || t != callFlowTargetTypeNumber {
+			return nil
+		}
+		number, _ := target["target"].(string)
+		if _, err := normalizePhoneNumber("", number); err != nil {
+			return fmt.Errorf("invalid call target of type %s: %s", callFlowTargetTypeNumber, err.Error())
+		}
+		return nil
+	})
+}
+
+// walkCallFlowTargets calls f for every call target in a root_node tree, in a
+// stable order, and stops at the first error.
+func walkCallFlowTargets(v any, f func(target map[string]any) error) error {
+	switch v := v.(type) {
+	case []any:
+		for _, it := range v {
+			if err := walkCallFlowTargets(it, f); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if k != "targets" {
+				if err := walkCallFlowTargets(v[k], f); err != nil {
+					return err
+				}
+				continue
+			}
+			targets, _ := v[k].([]any)
+			for _, it := range targets {
+				if target, ok := it.(map[string]any); ok {
+					if err := f(target); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateCallFlowDefinition(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, []error{fmt.Errorf("expected %s to be a JSON or YAML document, got an empty string", k)}
+	}
+	if _, err := parseCallFlowDefinition(value); err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %s", k, err.Error())}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentCallFlowDefinition hides the diff between definitions that
+// only differ in format, key order, ids or the formatting of phone numbers.
+func suppressEquivalentCallFlowDefinition(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	o, err := canonicalCallFlowDefinition(old)
+	if err != nil {
+		return false
+	}
+	n, err := canonicalCallFlowDefinition(new)
+	if err != nil {
+		return false
+	}
+	return o == n
+}
+
+// suppressEquivalentCallFlowTarget hides the diff between call targets of type
+// NUMBER that are the same phone number.
+func suppressEquivalentCallFlowTarget(k, old, new string, d *schema.ResourceData) bool {
+	if t, _ := d.Get(strings.TrimSuffix(k, "target") + "type").(string); t != callFlowTargetTypeNumber {
+		return false
+	}
+	return equivalentPhoneNumbers("", old, new)
+}
+
+// customizeCallFlowPhoneTargets checks the targets of type NUMBER of a
+// root_node block at plan time; a definition is checked by its ValidateFunc.
+func customizeCallFlowPhoneTargets(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diffValuesKnown(diff, "root_node") {
+		return nil
+	}
+	return validateCallFlowPhoneTargets(diff.Get("root_node"))
+}
@@ -0,0 +1,180 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+const testCallFlowDefinitionJSON = `{
+  "node_type": "ROOT",
+  "branches": [
+    {
+      "branch_type": "ANSWERED",
+      "target": {
+        "node_type": "ROUTE_CALL",
+        "name": "On-call engineer",
+        "metadata": {
+          "call_style": "ORDERED",
+          "retries": 2,
+          "targets": [{"target": "1", "type": "USER"}]
+        }
+      }
+    }
+  ]
+}`
+
+const testCallFlowDefinitionYAML = `
+node_type: ROOT
+branches:
+  - id: 42
+    branch_type: ANSWERED
+    target:
+      id: 7
+      metadata:
+        targets:
+          - type: USER
+            target: 1
+        retries: 2
+        call_style: ORDERED
+      name: On-call engineer
+      node_type: ROUTE_CALL
+`
+
+func TestCanonicalCallFlowDefinition_IgnoresFormatKeyOrderAndIDs(t *testing.T) {
+	fromJSON, err := canonicalCallFlowDefinition(testCallFlowDefinitionJSON)
+	if err != nil {
+		t.Fatalf("unexpected error parsing JSON definition: %v", err)
+	}
+	fromYAML, err := canonicalCallFlowDefinition(testCallFlowDefinitionYAML)
+	if err != nil {
+		t.Fatalf("unexpected error parsing YAML definition: %v", err)
+	}
+	if fromJSON != fromYAML {
+		t.Fatalf("expected equal canonical definitions, got\n%s\nand\n%s", fromJSON, fromYAML)
+	}
+	if !suppressEquivalentCallFlowDefinition("definition", testCallFlowDefinitionJSON, testCallFlowDefinitionYAML, nil) {
+		t.Fatalf("expected the diff between equivalent definitions to be suppressed")
+	}
+
+	formatted := `{"node_type": "ROUTE_CALL", "metadata": {"targets": [{"target": "+49 170 1234567", "type": "NUMBER"}]}}`
+	if !suppressEquivalentCallFlowDefinition("definition", strings.Replace(formatted, "+49 170 1234567", "+491701234567", 1), formatted, nil) {
+		t.Fatalf("expected the diff between phone number formats to be suppressed")
+	}
+
+	changed := strings.Replace(testCallFlowDefinitionYAML, "retries: 2", "retries: 3", 1)
+	if suppressEquivalentCallFlowDefinition("definition", testCallFlowDefinitionJSON, changed, nil) {
+		t.Fatalf("expected the diff between different definitions to be shown")
+	}
+}
+
+func TestParseCallFlowDefinition_Errors(t *testing.T) {
+	cases := map[string]struct {
+		definition string
+		wantErr    string
+	}{
+		"not a document":     {`node_type: [ROOT`, "neither valid JSON nor YAML"},
+		"not an object":      {`["ROOT"]`, "expected an object"},
+		"unknown node type":  {`{"node_type": "ROOT", "branches": [{"branch_type": "ANSWERED", "target": {"node_type": "HANG_UP"}}]}`, "root_node.branches.0.target.node_type"},
+		"missing node type":  {`{"name": "root"}`, `missing required attribute "node_type"`},
+		"unknown attribute":  {`{"node_type": "ROOT", "metadata": {"text": "hello"}}`, `root_node.metadata.0: unsupported attribute "text"`},
+		"wrong type":         {`{"node_type": "ROOT", "metadata": {"retries": "twice"}}`, "root_node.metadata.0.retries: expected an integer"},
+		"too many targets":   {`{"node_type": "ROOT", "metadata": [{}, {}]}`, "expected at most 1 items"},
+		"unknown call style": {`{"node_type": "ROUTE_CALL", "metadata": {"call_style": "ALL"}}`, "root_node.metadata.0.call_style"},
+		"national number":    {`{"node_type": "ROUTE_CALL", "metadata": {"targets": [{"target": "0170 1234567", "type": "NUMBER"}]}}`, "invalid call target of type NUMBER"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCallFlowDefinition(tc.definition)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildCallFlow_FromDefinition(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCallFlow().Schema, map[string]any{
+		"name":       "test-call-flow",
+		"language":   "en",
+		"definition": testCallFlowDefinitionYAML,
+	})
+
+	cf, err := buildCallFlow(d)
+	if err != nil {
+		t.Fatalf("unexpected error building call flow: %v", err)
+	}
+	if cf.RootNode == nil || cf.RootNode.NodeType != "ROOT" || len(cf.RootNode.Branches) != 1 {
+		t.Fatalf("expected a root node with one branch, got %+v", cf.RootNode)
+	}
+	target := cf.RootNode.Branches[0].Target
+	if target.ID != 0 || cf.RootNode.Branches[0].ID != 0 {
+		t.Fatalf("expected ids in the definition to be ignored, got node %d and branch %d", target.ID, cf.RootNode.Branches[0].ID)
+	}
+	md, ok := target.Metadata.(*ilert.CallFlowNodeMetadata)
+	if !ok {
+		t.Fatalf("expected *ilert.CallFlowNodeMetadata, got %T", target.Metadata)
+	}
+	if md.CallStyle != "ORDERED" || md.Retries != 2 || len(md.Targets) != 1 || md.Targets[0].Target != "1" {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+func TestBuildCallFlow_DefinitionRequiresCallStyleForRouteCallNode(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCallFlow().Schema, map[string]any{
+		"name":       "test-call-flow",
+		"language":   "en",
+		"definition": strings.Replace(testCallFlowDefinitionYAML, "call_style: ORDERED", "", 1),
+	})
+
+	_, err := buildCallFlow(d)
+	if err == nil || !strings.Contains(err.Error(), "requires 'call_style'") {
+		t.Fatalf("expected metadata.call_style validation error, got: %v", err)
+	}
+}
+
+func TestRenderCallFlowDefinition_MatchesFlattenedState(t *testing.T) {
+	// The shape flattenCallFlowNodeOutput stores for root_node.
+	rootNode := map[string]any{
+		"id":        1,
+		"node_type": "ROOT",
+		"metadata":  []any{},
+		"branches": []any{
+			map[string]any{
+				"id":          42,
+				"branch_type": "ANSWERED",
+				"target": []any{
+					map[string]any{
+						"id":        7,
+						"node_type": "ROUTE_CALL",
+						"name":      "On-call engineer",
+						"metadata": []any{
+							map[string]any{
+								"call_style": "ORDERED",
+								"retries":    2,
+								"targets":    []any{map[string]any{"target": "1", "type": "USER"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := renderCallFlowDefinition(rootNode)
+	if err != nil {
+		t.Fatalf("unexpected error rendering definition: %v", err)
+	}
+	want, err := canonicalCallFlowDefinition(testCallFlowDefinitionJSON)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected the rendered state to match the configured definition, got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,168 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// contactVerificationPollInterval is how often the status of a contact is read
+// while waiting for it to be verified. Verification needs the user to act, so
+// there is no point in polling faster.
+const contactVerificationPollInterval = 10 * time.Second
+
+// contactStatusPlanTimeout bounds the contact lookups made at plan time, where
+// no resource timeout applies.
+const contactStatusPlanTimeout = 1 * time.Minute
+
+func sendVerificationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+func waitForVerificationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateDuration,
+	}
+}
+
+func validateDuration(v any, k string) (ws []string, errors []error) {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration like \"10m\", got %q", k, v))
+		return
+	}
+	if d <= 0 {
+		errors = append(errors, fmt.Errorf("%q must be positive, got %q", k, v))
+	}
+	return
+}
+
+// verifyContact sends a verification to a contact when send_verification is
+// set and the contact is new, its target changed or send_verification was
+// just turned on. With wait_for_verification set it then waits until the
+// contact is verified, see waitForContactVerification. label names the
+// contact in messages, timeout bounds sending the verification.
+func verifyContact(ctx context.Context, d *schema.ResourceData, label string, timeout time.Duration, send func() error, status func() (string, error)) diag.Diagnostics {
+	if d.Get("send_verification").(bool) && (d.IsNewResource() || d.HasChange("target") || d.HasChange("send_verification")) {
+		log.Printf("[INFO] Sending a verification to %s", label)
+		err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+			if err := send(); err != nil {
+				if _, ok := err.(*ilert.RetryableAPIError); ok {
+					time.Sleep(2 * time.Second)
+					return resource.RetryableError(fmt.Errorf("waiting for the verification of %s to be sent, error: %s", label, err.Error()))
+				}
+				return resource.NonRetryableError(fmt.Errorf("could not send a verification to %s, error: %s", label, err.Error()))
+			}
+			return nil
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	wait := d.Get("wait_for_verification").(string)
+	if wait == "" {
+		return nil
+	}
+	waitTimeout, err := time.ParseDuration(wait)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return waitForContactVerification(ctx, label, waitTimeout, status)
+}
+
+// waitForContactVerification reads the status of a contact until it is OK or
+// the timeout passes. A contact that is still not verified by then is only
+// reported as a warning: it exists and its status is set, failing the apply
+// would taint it and replace it on every apply.
+func waitForContactVerification(ctx context.Context, label string, timeout time.Duration, status func() (string, error)) diag.Diagnostics {
+	log.Printf("[INFO] Waiting up to %s for %s to be verified", timeout, label)
+	last := ""
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		s, err := status()
+		if err != nil {
+			last = ""
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for %s to be read, error: %s", label, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read %s, error: %s", label, err.Error()))
+		}
+		last = s
+		if s != contactStatusOK {
+			time.Sleep(contactVerificationPollInterval)
+			return resource.RetryableError(fmt.Errorf("%s is not verified yet, its status is %s", label, s))
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	if last == "" {
+		return diag.Errorf("%s was not verified within %s: %s", label, timeout, err.Error())
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s was not verified within %s", label, timeout),
+			Detail:   fmt.Sprintf("Its status is %s. Notifications to it are not delivered until it is verified; the contact is kept and its status is read again on every refresh.", last),
+		},
+	}
+}
+
+// userContactStatus returns the status of a phone number contact of a user
+// when phone is set, or else of an email contact. found is false when the user
+// has no such contact.
+func userContactStatus(ctx context.Context, client *ilert.Client, userID, contactID int64, phone bool, timeout time.Duration) (string, bool, error) {
+	var status string
+	found := false
+	what := fmt.Sprintf("read the contacts of user %d", userID)
+	err := retryNotificationProfile(ctx, timeout, what, func() error {
+		if phone {
+			phones, err := client.GetUserPhoneNumberContacts(&ilert.GetUserPhoneNumberContactsInput{UserID: ilert.Int64(userID)})
+			if err != nil {
+				return err
+			}
+			for _, c := range phones.UserPhoneNumberContacts {
+				if c.ID == contactID {
+					status, found = c.Status, true
+					return nil
+				}
+			}
+			return nil
+		}
+		emails, err := client.GetUserEmailContacts(&ilert.GetUserEmailContactsInput{UserID: ilert.Int64(userID)})
+		if err != nil {
+			return err
+		}
+		for _, c := range emails.UserEmailContacts {
+			if c.ID == contactID {
+				status, found = c.Status, true
+				return nil
+			}
+		}
+		return nil
+	})
+	return status, found, err
+}
+
+// unverifiedContactWarning describes a preference that notifies a contact
+// which is not verified, or returns an empty string when the status is OK.
+func unverifiedContactWarning(preference string, contact string, status string) string {
+	if status == "" || status == contactStatusOK {
+		return ""
+	}
+	return fmt.Sprintf("%s notifies the contact %s, which is not verified (status %s). Notifications to it are not delivered until it is verified.", preference, contact, status)
+}
@@ -0,0 +1,73 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateDuration(t *testing.T) {
+	for v, ok := range map[string]bool{"10m": true, "1h30m": true, "0s": false, "-5m": false, "ten minutes": false} {
+		if _, errs := validateDuration(v, "wait_for_verification"); (len(errs) == 0) != ok {
+			t.Errorf("validateDuration(%q) = %v, want ok %t", v, errs, ok)
+		}
+	}
+}
+
+func TestWaitForContactVerification(t *testing.T) {
+	if diags := waitForContactVerification(context.Background(), "contact 1", time.Minute, func() (string, error) {
+		return contactStatusOK, nil
+	}); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	diags := waitForContactVerification(context.Background(), "contact 1", time.Minute, func() (string, error) {
+		return "", fmt.Errorf("forbidden")
+	})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "contact 1 was not verified within 1m0s") {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+}
+
+func TestNotificationProfileContactWarnings(t *testing.T) {
+	preferences := []notificationPreference{
+		{kind: notificationPreferenceAlert, method: "SMS", contact: "+491701234567", preferenceType: "HIGH_PRIORITY", minutes: 5},
+		{kind: notificationPreferenceAlert, method: "EMAIL", contact: "jane@example.com", preferenceType: "LOW_PRIORITY"},
+		{kind: notificationPreferenceSubscription, method: "SMS", contact: "+491701234567"},
+		{kind: notificationPreferenceAlert, method: "PUSH", preferenceType: "HIGH_PRIORITY"},
+	}
+	status := map[string]any{"+491701234567": "LOCKED", "Jane@example.com": contactStatusOK}
+
+	warnings := notificationProfileContactWarnings(preferences, status)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "The alert preference SMS HIGH_PRIORITY to +491701234567 after 5 min notifies the contact +491701234567, which is not verified (status LOCKED)") {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	if !notificationProfileContactStatusKnown(preferences, status) {
+		t.Fatalf("expected the status of every notified contact to be known")
+	}
+	delete(status, "Jane@example.com")
+	if notificationProfileContactStatusKnown(preferences, status) {
+		t.Fatalf("expected a new contact to leave the warnings unknown")
+	}
+}
+
+func TestFlattenUnverifiedContacts(t *testing.T) {
+	profiles := map[int64]notificationProfile{
+		1: {contacts: []notificationContact{
+			{id: 3, phone: true, target: "+491701234567", status: "LOCKED"},
+			{id: 2, target: "jane@example.com", status: contactStatusOK},
+			{id: 1, target: "old@example.com", status: "BLACKLISTED"},
+		}},
+		2: {contacts: []notificationContact{{id: 4, target: "john@example.com", status: contactStatusOK}}},
+	}
+	contacts := flattenUnverifiedContacts([]int64{1, 2}, profiles)
+	if len(contacts) != 2 {
+		t.Fatalf("unexpected contacts %v", contacts)
+	}
+	first, second := contacts[0].(map[string]any), contacts[1].(map[string]any)
+	if first["id"] != 1 || first["type"] != "email" || second["id"] != 3 || second["type"] != "phone_number" || second["user"] != "1" {
+		t.Fatalf("unexpected contacts %v", contacts)
+	}
+}
@@ -30,7 +30,7 @@ func dataSourceAlertAction() *schema.Resource {
 }
 
 func dataSourceAlertActionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert alert action")
 
@@ -0,0 +1,78 @@
+package ilert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAlertActionCondition() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAlertActionConditionRead,
+
+		Schema: map[string]*schema.Schema{
+			"conditions": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"conditions", "condition"},
+				ValidateDiagFunc: validateICLExpression,
+			},
+			"condition": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     resourceAlertActionConditionGroup(alertActionConditionDepth),
+			},
+			"alert": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"expression": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"matches": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAlertActionConditionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	expression := d.Get("conditions").(string)
+	if val, ok := d.GetOk("condition"); ok {
+		compiled, err := alertActionConditionExpression(val)
+		if err != nil {
+			return diag.Errorf("[ERROR] Could not compile condition: %s", err.Error())
+		}
+		expression = compiled
+	}
+
+	var alert map[string]any
+	if err := json.Unmarshal([]byte(d.Get("alert").(string)), &alert); err != nil {
+		return diag.Errorf("[ERROR] alert must be a JSON object: %s", err.Error())
+	}
+
+	log.Printf("[DEBUG] Evaluating alert action condition %q", expression)
+	matches, err := evaluateICLCondition(expression, map[string]any{"alert": alert})
+	if err != nil {
+		return diag.Errorf("[ERROR] Could not evaluate condition %q: %s", expression, err.Error())
+	}
+
+	d.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%s|%s", expression, d.Get("alert")))))
+	if err := d.Set("expression", expression); err != nil {
+		return diag.Errorf("[ERROR] Error setting expression: %s", err.Error())
+	}
+	if err := d.Set("matches", matches); err != nil {
+		return diag.Errorf("[ERROR] Error setting matches: %s", err.Error())
+	}
+	return nil
+}
@@ -41,7 +41,7 @@ func dataSourceAlertSource() *schema.Resource {
 }
 
 func dataSourceAlertSourceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert alert source")
 
@@ -59,7 +59,7 @@ func dataSourceCallFlow() *schema.Resource {
 }
 
 func dataSourceCallFlowRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert call flow")
 
@@ -32,7 +32,7 @@ func dataSourceConnection() *schema.Resource {
 }
 
 func dataSourceConnectionRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert connection")
 
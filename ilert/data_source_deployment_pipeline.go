@@ -27,7 +27,7 @@ func dataSourceDeploymentPipeline() *schema.Resource {
 }
 
 func dataSourceDeploymentPipelineRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert deployment pipeline")
 
@@ -27,7 +27,7 @@ func dataSourceEscalationPolicy() *schema.Resource {
 }
 
 func dataSourceEscalationPolicyRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert escalation policy")
 
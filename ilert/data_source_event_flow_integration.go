@@ -43,7 +43,7 @@ func dataSourceEventFlowIntegration() *schema.Resource {
 }
 
 func dataSourceEventFlowIntegrationRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	eventFlowID := int64(d.Get("event_flow_id").(int))
 	integrationType := d.Get("integration_type").(string)
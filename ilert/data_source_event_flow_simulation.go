@@ -0,0 +1,279 @@
+package ilert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func dataSourceEventFlowSimulation() *schema.Resource {
+	supportHour := resourceSupportHour().Schema
+	return &schema.Resource{
+		ReadContext: dataSourceEventFlowSimulationRead,
+
+		Schema: map[string]*schema.Schema{
+			"event_flow_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"event_flow_id", "definition"},
+			},
+			"definition": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateEventFlowDefinition,
+			},
+			"event": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"timestamp": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"support_hour": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"timezone":     supportHour["timezone"],
+						"support_days": supportHour["support_days"],
+						"exception":    supportHour["exception"],
+					},
+				},
+			},
+			"path": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"node_path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"node_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"branch_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"condition": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"routed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"alert_source_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"escalation_policy_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"priority": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"result_event": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ended_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceEventFlowSimulationRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := providerClient(meta)
+
+	var root map[string]any
+	if val, ok := d.GetOk("definition"); ok {
+		rn, err := parseEventFlowDefinition(val.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		root = rn
+	} else {
+		rn, err := readEventFlowRootNode(ctx, d, client, d.Get("event_flow_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		root = rn
+	}
+
+	event := make(map[string]any)
+	if err := json.Unmarshal([]byte(d.Get("event").(string)), &event); err != nil {
+		return diag.Errorf("event must be a JSON object: %s", err.Error())
+	}
+
+	at := time.Now()
+	if val, ok := d.GetOk("timestamp"); ok {
+		t, err := time.Parse(time.RFC3339, val.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		at = t
+	}
+
+	supportHours := make(map[int64]*ilert.SupportHour)
+	for _, it := range d.Get("support_hour").([]any) {
+		if it == nil {
+			continue
+		}
+		v := it.(map[string]any)
+		supportHours[int64(v["id"].(int))] = &ilert.SupportHour{
+			Timezone:    v["timezone"].(string),
+			SupportDays: buildSupportDays(v["support_days"].([]any)),
+			Exceptions:  buildSupportHourExceptions(v["exception"].([]any)),
+		}
+	}
+	lookup := func(id int64) (*ilert.SupportHour, error) {
+		if supportHour, ok := supportHours[id]; ok {
+			return supportHour, nil
+		}
+		supportHour, err := readSupportHour(ctx, d, client, id)
+		if err != nil {
+			return nil, err
+		}
+		supportHours[id] = supportHour
+		return supportHour, nil
+	}
+
+	log.Printf("[DEBUG] Simulating ilert event flow at %s", at.Format(time.RFC3339))
+
+	result, err := simulateEventFlow(root, event, at, lookup)
+	if err != nil {
+		return diag.Errorf("could not simulate the event flow: %s", err.Error())
+	}
+
+	steps := make([]any, 0, len(result.Steps))
+	for _, step := range result.Steps {
+		steps = append(steps, map[string]any{
+			"node_path":   step.Path,
+			"name":        step.Name,
+			"node_type":   step.NodeType,
+			"branch_type": step.BranchType,
+			"condition":   step.Condition,
+			"at":          step.At.Format(time.RFC3339),
+		})
+	}
+	resultEvent, err := json.Marshal(result.Event)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%s|%s|%s|%s", d.Get("event_flow_id"), d.Get("definition"), d.Get("event"), at.Format(time.RFC3339)))))
+	if err := d.Set("path", steps); err != nil {
+		return diag.Errorf("[ERROR] Error setting path: %s", err.Error())
+	}
+	d.Set("routed", result.Routed)
+	d.Set("alert_source_id", int(result.AlertSourceID))
+	d.Set("escalation_policy_id", int(result.EscalationPolicyID))
+	d.Set("priority", result.Priority)
+	d.Set("result_event", string(resultEvent))
+	d.Set("ended_at", result.At.Format(time.RFC3339))
+
+	return nil
+}
+
+// readEventFlowRootNode reads an event flow from the API, in the shape of its
+// root_node block.
+func readEventFlowRootNode(ctx context.Context, d *schema.ResourceData, client *ilert.Client, id string) (map[string]any, error) {
+	eventFlowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, unconvertibleIDErr(id, err)
+	}
+	if client == nil {
+		return nil, fmt.Errorf("reading event flow %d requires a configured provider", eventFlowID)
+	}
+
+	result := &ilert.GetEventFlowOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetEventFlow(&ilert.GetEventFlowInput{EventFlowID: ilert.Int64(eventFlowID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for event flow with id '%d' to be read, error: %s", eventFlowID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read an event flow with ID %d, error: %s", eventFlowID, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.EventFlow == nil {
+		return nil, fmt.Errorf("event flow response is empty")
+	}
+
+	rootNode, err := flattenEventFlowNodeOutput(result.EventFlow.RootNode)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Error flattening root node: %s", err.Error())
+	}
+	if len(rootNode) == 0 || rootNode[0] == nil {
+		return nil, fmt.Errorf("event flow %d has no root node", eventFlowID)
+	}
+	return rootNode[0].(map[string]any), nil
+}
+
+// readSupportHour reads support hours a simulation needs but was not given.
+func readSupportHour(ctx context.Context, d *schema.ResourceData, client *ilert.Client, id int64) (*ilert.SupportHour, error) {
+	if client == nil {
+		return nil, fmt.Errorf("support hours %d are not known; add a support_hour block for them", id)
+	}
+
+	result := &ilert.GetSupportHourOutput{}
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetSupportHour(&ilert.GetSupportHourInput{SupportHourID: ilert.Int64(id)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for support hour with id '%d' to be read, error: %s", id, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a support hour with ID %d, error: %s", id, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.SupportHour == nil {
+		return nil, fmt.Errorf("support hour response is empty")
+	}
+	return result.SupportHour, nil
+}
@@ -0,0 +1,106 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func dataSourceFlowDiagram() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFlowDiagramRead,
+
+		Schema: map[string]*schema.Schema{
+			"call_flow_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"call_flow_id", "event_flow_id"},
+			},
+			"event_flow_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"diagram_mermaid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"diagram_dot": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceFlowDiagramRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	if id, ok := d.GetOk("event_flow_id"); ok {
+		log.Printf("[DEBUG] Reading ilert event flow %s for its diagram", id.(string))
+
+		root, err := readEventFlowRootNode(ctx, d, client, id.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId("event_flow/" + id.(string))
+		if err := setFlowDiagrams(d, []any{root}, eventFlowDefinitionSchema(), "event_flow"); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+
+	id := d.Get("call_flow_id").(string)
+	log.Printf("[DEBUG] Reading ilert call flow %s for its diagram", id)
+
+	rootNode, err := readCallFlowRootNode(ctx, d, client, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId("call_flow/" + id)
+	if err := setFlowDiagrams(d, rootNode, callFlowDefinitionSchema(), "call_flow"); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// readCallFlowRootNode reads a call flow from the API, in the shape of its
+// root_node block.
+func readCallFlowRootNode(ctx context.Context, d *schema.ResourceData, client *ilert.Client, id string) ([]any, error) {
+	callFlowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, unconvertibleIDErr(id, err)
+	}
+
+	result := &ilert.GetCallFlowOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetCallFlow(&ilert.GetCallFlowInput{CallFlowID: ilert.Int64(callFlowID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for call flow with id '%d' to be read, error: %s", callFlowID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a call flow with ID %d, error: %s", callFlowID, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.CallFlow == nil {
+		return nil, fmt.Errorf("call flow response is empty")
+	}
+
+	rootNode, err := flattenCallFlowNodeOutput(result.CallFlow.RootNode)
+	if err != nil {
+		return nil, fmt.Errorf("[ERROR] Error flattening root node: %s", err.Error())
+	}
+	return rootNode, nil
+}
@@ -39,7 +39,7 @@ func dataSourceIncidentTemplate() *schema.Resource {
 }
 
 func dataSourceIncidentTemplateRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert incident template")
 
@@ -35,7 +35,7 @@ func dataSourceMetric() *schema.Resource {
 }
 
 func dataSourceMetricRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert metric")
 
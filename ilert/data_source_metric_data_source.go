@@ -31,7 +31,7 @@ func dataSourceMetricDataSource() *schema.Resource {
 }
 
 func dataSourceMetricDataSourceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert metric data source")
 
@@ -0,0 +1,320 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func dataSourceOnCall() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOnCallRead,
+
+		Schema: map[string]*schema.Schema{
+			"schedule_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"schedule_id", "escalation_policy_id"},
+			},
+			"escalation_policy_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"at": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"from", "until"},
+			},
+			"from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				RequiredWith: []string{"until"},
+			},
+			"until": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				RequiredWith: []string{"from"},
+			},
+			"timezone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimezone,
+			},
+			"on_call": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"escalation_level": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"user": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"first_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"schedule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"start": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"escalation_level": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"level": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"users": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOnCallRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	log.Printf("[DEBUG] Reading ilert on-calls")
+
+	input := &ilert.GetOnCallsInput{
+		Expand: []*string{ilert.String("user")},
+	}
+	target := ""
+	if val, ok := d.GetOk("schedule_id"); ok {
+		scheduleID, err := strconv.ParseInt(val.(string), 10, 64)
+		if err != nil {
+			log.Printf("[ERROR] Could not parse schedule id %s", err.Error())
+			return diag.FromErr(unconvertibleIDErr(val.(string), err))
+		}
+		input.Schedules = []*int64{ilert.Int64(scheduleID)}
+		target = fmt.Sprintf("schedule/%d", scheduleID)
+	}
+	if val, ok := d.GetOk("escalation_policy_id"); ok {
+		escalationPolicyID, err := strconv.ParseInt(val.(string), 10, 64)
+		if err != nil {
+			log.Printf("[ERROR] Could not parse escalation policy id %s", err.Error())
+			return diag.FromErr(unconvertibleIDErr(val.(string), err))
+		}
+		input.Policies = []*int64{ilert.Int64(escalationPolicyID)}
+		target = fmt.Sprintf("escalation-policy/%d", escalationPolicyID)
+	}
+
+	from, until := onCallWindow(d.Get("at").(string), d.Get("from").(string), d.Get("until").(string))
+	if from != "" {
+		input.From = ilert.String(from)
+		input.Until = ilert.String(until)
+	}
+	if val, ok := d.GetOk("timezone"); ok {
+		input.Timezone = ilert.String(val.(string))
+	}
+
+	result := &ilert.GetOnCallsOutput{}
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetOnCalls(input)
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for on-calls of %s to be read, error: %s", target, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read the on-calls of %s, error: %s", target, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	at := d.Get("at").(string)
+	onCalls := make([]onCallEntry, 0)
+	for _, o := range result.OnCalls {
+		if o == nil {
+			continue
+		}
+		entry := onCallEntry{
+			Level:     int(o.EscalationLevel),
+			UserID:    strconv.FormatInt(o.User.ID, 10),
+			FirstName: o.User.FirstName,
+			LastName:  o.User.LastName,
+			Email:     o.User.Email,
+			Start:     o.Start,
+			End:       o.End,
+		}
+		if o.Schedule != nil {
+			entry.ScheduleID = strconv.FormatInt(o.Schedule.ID, 10)
+		}
+		if !onCallCovers(entry, at) {
+			continue
+		}
+		onCalls = append(onCalls, entry)
+	}
+	flattened, levels := flattenOnCalls(onCalls)
+
+	d.SetId(strings.Join([]string{target, from, until}, "/"))
+	if err := d.Set("on_call", flattened); err != nil {
+		return diag.Errorf("error setting on_call: %s", err.Error())
+	}
+	if err := d.Set("escalation_level", levels); err != nil {
+		return diag.Errorf("error setting escalation_level: %s", err.Error())
+	}
+
+	return nil
+}
+
+// onCallEntry is a single resolved on-call, decoupled from the API struct so the
+// grouping below can be tested without a client.
+type onCallEntry struct {
+	Level      int
+	UserID     string
+	FirstName  string
+	LastName   string
+	Email      string
+	ScheduleID string
+	Start      string
+	End        string
+}
+
+// onCallPointWindow is the length of the window queried for a single point in
+// time, as the endpoint does not answer for an empty window.
+const onCallPointWindow = time.Minute
+
+// onCallWindow turns the data source's time arguments into the from/until pair
+// the on-call endpoint expects. A single point in time is the short window
+// starting at that instant; no arguments at all leave the window to the API,
+// which answers with who is on call right now.
+func onCallWindow(at, from, until string) (string, string) {
+	if at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return at, at
+		}
+		return at, t.Add(onCallPointWindow).Format(time.RFC3339)
+	}
+	if from != "" && until != "" {
+		return from, until
+	}
+	return "", ""
+}
+
+// onCallCovers reports whether the on-call is on duty at the given point in
+// time, dropping the shifts that only start within the queried window. Without
+// a point in time, or with unparsable times, every on-call is kept.
+func onCallCovers(entry onCallEntry, at string) bool {
+	if at == "" || entry.Start == "" {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return true
+	}
+	start, err := time.Parse(time.RFC3339, entry.Start)
+	if err != nil {
+		return true
+	}
+	return !start.After(t)
+}
+
+// onCallStartsBefore orders two shift starts by time, falling back to the
+// strings when one of them does not parse.
+func onCallStartsBefore(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
+
+// flattenOnCalls orders the on-calls by escalation level, then start, then user,
+// and groups the distinct users of every level, in that order.
+func flattenOnCalls(onCalls []onCallEntry) ([]any, []any) {
+	sort.SliceStable(onCalls, func(i, j int) bool {
+		if onCalls[i].Level != onCalls[j].Level {
+			return onCalls[i].Level < onCalls[j].Level
+		}
+		if onCallStartsBefore(onCalls[i].Start, onCalls[j].Start) {
+			return true
+		}
+		if onCallStartsBefore(onCalls[j].Start, onCalls[i].Start) {
+			return false
+		}
+		return onCalls[i].UserID < onCalls[j].UserID
+	})
+
+	results := make([]any, 0, len(onCalls))
+	levels := make([]any, 0)
+	var current map[string]any
+	seen := make(map[string]bool)
+	for _, item := range onCalls {
+		results = append(results, map[string]any{
+			"escalation_level": item.Level,
+			"user":             item.UserID,
+			"first_name":       item.FirstName,
+			"last_name":        item.LastName,
+			"email":            item.Email,
+			"schedule_id":      item.ScheduleID,
+			"start":            item.Start,
+			"end":              item.End,
+		})
+
+		if current == nil || current["level"].(int) != item.Level {
+			current = map[string]any{
+				"level": item.Level,
+				"users": make([]any, 0),
+			}
+			levels = append(levels, current)
+			seen = make(map[string]bool)
+		}
+		if !seen[item.UserID] {
+			seen[item.UserID] = true
+			current["users"] = append(current["users"].([]any), item.UserID)
+		}
+	}
+
+	return results, levels
+}
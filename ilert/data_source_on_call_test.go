@@ -0,0 +1,99 @@
+package ilert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnCallWindow(t *testing.T) {
+	cases := []struct {
+		name                string
+		at, from, until     string
+		wantFrom, wantUntil string
+	}{
+		{"now", "", "", "", "", ""},
+		{"point in time", "2026-12-24T12:00:00+01:00", "", "", "2026-12-24T12:00:00+01:00", "2026-12-24T12:01:00+01:00"},
+		{"range", "", "2026-12-24T00:00:00Z", "2026-12-31T00:00:00Z", "2026-12-24T00:00:00Z", "2026-12-31T00:00:00Z"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotFrom, gotUntil := onCallWindow(tc.at, tc.from, tc.until)
+			if gotFrom != tc.wantFrom || gotUntil != tc.wantUntil {
+				t.Fatalf("onCallWindow() = (%q, %q), want (%q, %q)", gotFrom, gotUntil, tc.wantFrom, tc.wantUntil)
+			}
+		})
+	}
+}
+
+func TestFlattenOnCalls_GroupsUsersPerEscalationLevel(t *testing.T) {
+	onCalls := []onCallEntry{
+		{Level: 2, UserID: "30", Start: "2026-12-24T00:00:00Z", End: "2026-12-25T00:00:00Z"},
+		{Level: 1, UserID: "20", Start: "2026-12-25T00:00:00Z", End: "2026-12-26T00:00:00Z", ScheduleID: "5"},
+		{Level: 1, UserID: "10", Start: "2026-12-24T00:00:00Z", End: "2026-12-25T00:00:00Z", ScheduleID: "5"},
+		{Level: 1, UserID: "10", Start: "2026-12-26T00:00:00Z", End: "2026-12-27T00:00:00Z", ScheduleID: "5"},
+	}
+
+	flattened, levels := flattenOnCalls(onCalls)
+
+	gotOrder := make([]string, 0, len(flattened))
+	for _, item := range flattened {
+		gotOrder = append(gotOrder, item.(map[string]any)["user"].(string))
+	}
+	if want := []string{"10", "20", "10", "30"}; !reflect.DeepEqual(gotOrder, want) {
+		t.Fatalf("expected on-calls in order %v, got %v", want, gotOrder)
+	}
+
+	want := []any{
+		map[string]any{"level": 1, "users": []any{"10", "20"}},
+		map[string]any{"level": 2, "users": []any{"30"}},
+	}
+	if !reflect.DeepEqual(levels, want) {
+		t.Fatalf("expected escalation levels %v, got %v", want, levels)
+	}
+}
+
+func TestFlattenOnCalls_OrdersByStartTime(t *testing.T) {
+	onCalls := []onCallEntry{
+		{Level: 1, UserID: "10", Start: "2026-12-24T10:00:00Z"},
+		{Level: 1, UserID: "20", Start: "2026-12-24T10:30:00+01:00"},
+		{Level: 1, UserID: "30", Start: "2026-12-24T11:30:00+02:00"},
+	}
+
+	flattened, _ := flattenOnCalls(onCalls)
+
+	gotOrder := make([]string, 0, len(flattened))
+	for _, item := range flattened {
+		gotOrder = append(gotOrder, item.(map[string]any)["user"].(string))
+	}
+	if want := []string{"20", "30", "10"}; !reflect.DeepEqual(gotOrder, want) {
+		t.Fatalf("expected on-calls in order %v, got %v", want, gotOrder)
+	}
+}
+
+func TestOnCallCovers(t *testing.T) {
+	at := "2026-12-24T12:00:00+01:00"
+	cases := []struct {
+		start string
+		want  bool
+	}{
+		{"2026-12-24T08:00:00Z", true},
+		{"2026-12-24T11:00:00Z", true},
+		{"2026-12-24T11:00:30Z", false},
+		{"", true},
+	}
+	for _, tc := range cases {
+		if got := onCallCovers(onCallEntry{Start: tc.start}, at); got != tc.want {
+			t.Errorf("onCallCovers(%q, %q) = %t, want %t", tc.start, at, got, tc.want)
+		}
+	}
+	if !onCallCovers(onCallEntry{Start: "2026-12-24T11:00:30Z"}, "") {
+		t.Errorf("expected every on-call to be kept without a point in time")
+	}
+}
+
+func TestFlattenOnCalls_NobodyOnCall(t *testing.T) {
+	flattened, levels := flattenOnCalls(nil)
+	if len(flattened) != 0 || len(levels) != 0 {
+		t.Fatalf("expected no on-calls, got %v and %v", flattened, levels)
+	}
+}
@@ -0,0 +1,159 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func dataSourceScheduleICS() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceScheduleICSRead,
+
+		Schema: map[string]*schema.Schema{
+			"schedule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateScheduleDateTime,
+			},
+			"horizon": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultSchedulePreviewHorizon,
+				ValidateFunc: validateISODuration,
+			},
+			"timezone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceScheduleICSRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	scheduleID, err := strconv.ParseInt(d.Get("schedule_id").(string), 10, 64)
+	if err != nil {
+		log.Printf("[ERROR] Could not parse schedule id %s", err.Error())
+		return diag.FromErr(unconvertibleIDErr(d.Get("schedule_id").(string), err))
+	}
+
+	log.Printf("[DEBUG] Reading ilert schedule %d for its iCalendar export", scheduleID)
+
+	result := &ilert.GetScheduleOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		includes := []*string{ilert.String("scheduleLayers"), ilert.String("shifts")}
+		r, err := client.GetSchedule(&ilert.GetScheduleInput{ScheduleID: ilert.Int64(scheduleID), Include: includes})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for schedule with id '%d' to be read, error: %s", scheduleID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a schedule with ID %d, error: %s", scheduleID, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if result == nil || result.Schedule == nil {
+		log.Printf("[ERROR] Reading ilert schedule error: empty response")
+		return diag.Errorf("schedule response is empty")
+	}
+	schedule := result.Schedule
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return diag.Errorf("schedule %d has a timezone %q that is not a known IANA time zone: %s", scheduleID, schedule.Timezone, err.Error())
+	}
+
+	from := d.Get("from").(string)
+	if from == "" {
+		now := time.Now().In(loc)
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).Format(scheduleDateTimeLayouts[1])
+	}
+	start, end, err := schedulePreviewWindow(nil, loc, from, d.Get("horizon").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	shifts, userNames, err := scheduleICSShifts(schedule, loc, start, end)
+	if err != nil {
+		return diag.Errorf("could not expand the shifts of schedule %d: %s", scheduleID, err.Error())
+	}
+
+	d.SetId(strconv.FormatInt(scheduleID, 10))
+	d.Set("timezone", schedule.Timezone)
+	d.Set("content", renderScheduleICS(d.Id(), schedule.Name, shifts, userNames, start))
+
+	return nil
+}
+
+// scheduleICSShifts returns the shifts of a schedule between from and until,
+// along with the names of the users on call. The layers of a recurring schedule
+// are expanded the way the preview of ilert_schedule expands them; the shifts of
+// a static schedule are taken as they are, as long as they overlap the window.
+func scheduleICSShifts(schedule *ilert.Schedule, loc *time.Location, from, until time.Time) ([]scheduleShift, map[string]string, error) {
+	userNames := make(map[string]string)
+	addUser := func(u ilert.User) {
+		if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+			userNames[strconv.FormatInt(u.ID, 10)] = name
+		}
+	}
+
+	if schedule.Type == ilert.ScheduleType.Recurring {
+		for _, layer := range schedule.ScheduleLayers {
+			for _, u := range layer.Users {
+				addUser(u)
+			}
+		}
+		shifts, expandedUntil, err := expandScheduleLayers(schedule.ScheduleLayers, loc.String(), from, until)
+		if err != nil {
+			return nil, nil, err
+		}
+		if expandedUntil.Before(until) {
+			log.Printf("[WARN] The layers of schedule %d were only expanded until %s", schedule.ID, expandedUntil.Format(time.RFC3339))
+		}
+		return shifts, userNames, nil
+	}
+
+	shifts := make([]scheduleShift, 0, len(schedule.Shifts))
+	for _, s := range schedule.Shifts {
+		start, err := parseScheduleDateTime(s.Start, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("shift start %q is not a date time", s.Start)
+		}
+		end, err := parseScheduleDateTime(s.End, loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("shift end %q is not a date time", s.End)
+		}
+		if !end.After(from) || !start.Before(until) {
+			continue
+		}
+		addUser(s.User)
+		shifts = append(shifts, scheduleShift{
+			UserID: strconv.FormatInt(s.User.ID, 10),
+			Start:  start,
+			End:    end,
+		})
+	}
+	return shifts, userNames, nil
+}
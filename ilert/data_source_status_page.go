@@ -31,7 +31,7 @@ func dataSourceStatusPage() *schema.Resource {
 }
 
 func dataSourceStatusPageRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert status page")
 
@@ -41,7 +41,7 @@ func dataSourceStatusPageGroup() *schema.Resource {
 }
 
 func dataSourceStatusPageGroupRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert status page group")
 
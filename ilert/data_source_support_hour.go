@@ -27,7 +27,7 @@ func dataSourceSupportHour() *schema.Resource {
 }
 
 func dataSourceSupportHourRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert support hour")
 
@@ -40,7 +40,7 @@ func dataSourceUptimeMonitor() *schema.Resource {
 }
 
 func dataSourceUptimeMonitorRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert uptime monitor")
 
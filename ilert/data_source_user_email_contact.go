@@ -45,7 +45,7 @@ func dataSourceUserEmailContact() *schema.Resource {
 }
 
 func dataSourceUserEmailContactRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert user email contact")
 
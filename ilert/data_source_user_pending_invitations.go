@@ -0,0 +1,103 @@
+package ilert
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func dataSourceUserPendingInvitations() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserPendingInvitationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"first_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUserPendingInvitationsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+	log.Printf("[DEBUG] Reading users with a pending invitation")
+
+	users := make([]*ilert.User, 0)
+	err := listUserReferences(ctx, d.Timeout(schema.TimeoutRead), "users", func(start int) (int, error) {
+		r, err := client.GetUsers(&ilert.GetUsersInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(userReferencesPageSize)})
+		if err != nil || r == nil {
+			return 0, err
+		}
+		users = append(users, r.Users...)
+		return len(r.Users), nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pending := flattenPendingInvitations(users)
+	ids := make([]string, 0, len(pending))
+	for _, it := range pending {
+		ids = append(ids, it.(map[string]any)["id"].(string))
+	}
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ","))))
+	if err := d.Set("users", pending); err != nil {
+		return diag.Errorf("error setting users: %s", err.Error())
+	}
+
+	return nil
+}
+
+// flattenPendingInvitations lists the users whose invitation is pending, by id.
+func flattenPendingInvitations(users []*ilert.User) []any {
+	pending := make([]*ilert.User, 0)
+	for _, user := range users {
+		if user != nil && user.InvitationStatus == userInvitationStatusPending {
+			pending = append(pending, user)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+
+	result := make([]any, 0, len(pending))
+	for _, user := range pending {
+		result = append(result, map[string]any{
+			"id":         strconv.FormatInt(user.ID, 10),
+			"email":      user.Email,
+			"first_name": user.FirstName,
+			"last_name":  user.LastName,
+			"role":       user.Role,
+		})
+	}
+	return result
+}
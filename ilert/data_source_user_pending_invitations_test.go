@@ -0,0 +1,27 @@
+package ilert
+
+import (
+	"testing"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestFlattenPendingInvitations(t *testing.T) {
+	users := []*ilert.User{
+		{ID: 3, Email: "c@example.com", Role: "RESPONDER", InvitationStatus: userInvitationStatusPending},
+		{ID: 1, Email: "a@example.com", Role: "USER", InvitationStatus: "ACCEPTED"},
+		nil,
+		{ID: 2, Email: "b@example.com", FirstName: "B", Role: "STAKEHOLDER", InvitationStatus: userInvitationStatusPending},
+	}
+	got := flattenPendingInvitations(users)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 pending invitations, got %v", got)
+	}
+	first := got[0].(map[string]any)
+	if first["id"] != "2" || first["email"] != "b@example.com" || first["first_name"] != "B" || first["role"] != "STAKEHOLDER" {
+		t.Errorf("unexpected first invitation %v", first)
+	}
+	if got[1].(map[string]any)["id"] != "3" {
+		t.Errorf("expected invitations ordered by id, got %v", got)
+	}
+}
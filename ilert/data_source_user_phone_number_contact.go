@@ -49,7 +49,7 @@ func dataSourceUserPhoneNumberContact() *schema.Resource {
 }
 
 func dataSourceUserPhoneNumberContactRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	client := meta.(*ilert.Client)
+	client := meta.(*providerMeta).client
 
 	log.Printf("[DEBUG] Reading ilert user phone number contact")
 
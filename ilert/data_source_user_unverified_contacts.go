@@ -0,0 +1,120 @@
+package ilert
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceUserUnverifiedContacts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserUnverifiedContactsRead,
+
+		Schema: map[string]*schema.Schema{
+			"users": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringMatch(
+						regexp.MustCompile(`^[0-9]+$`),
+						"must be a numeric user id",
+					),
+				},
+			},
+			"contacts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"target": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUserUnverifiedContactsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*providerMeta).client
+
+	userIDs, err := expandNotificationPolicyUsers(d.Get("users"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[DEBUG] Reading unverified contacts of %d user(s)", len(userIDs))
+
+	profiles := make(map[int64]notificationProfile, len(userIDs))
+	for _, userID := range userIDs {
+		profile, err := readUserNotificationProfile(ctx, client, userID, d.Timeout(schema.TimeoutRead))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		profiles[userID] = profile
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		ids = append(ids, strconv.FormatInt(userID, 10))
+	}
+	d.SetId(strings.Join(ids, ","))
+	if err := d.Set("contacts", flattenUnverifiedContacts(userIDs, profiles)); err != nil {
+		return diag.Errorf("error setting contacts: %s", err.Error())
+	}
+
+	return nil
+}
+
+// flattenUnverifiedContacts lists the contacts of the users whose status is
+// not OK, by user and contact id.
+func flattenUnverifiedContacts(userIDs []int64, profiles map[int64]notificationProfile) []any {
+	result := make([]any, 0)
+	for _, userID := range userIDs {
+		contacts := append([]notificationContact{}, profiles[userID].contacts...)
+		sort.Slice(contacts, func(i, j int) bool { return contacts[i].id < contacts[j].id })
+		for _, contact := range contacts {
+			if contact.status == contactStatusOK {
+				continue
+			}
+			contactType := "email"
+			if contact.phone {
+				contactType = "phone_number"
+			}
+			result = append(result, map[string]any{
+				"user":   strconv.FormatInt(userID, 10),
+				"id":     int(contact.id),
+				"type":   contactType,
+				"target": contact.target,
+				"status": contact.status,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,320 @@
+package ilert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// dependentsPageSize is the page size used to list the objects that may
+// reference an object about to be deleted.
+const dependentsPageSize = 100
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// dependentReferences describes how other objects reference an object of a
+// kind in their API representation: objects lists the keys that hold the
+// referenced object or a list of them, ids the keys that hold its id and
+// targetTypes the call flow target types that name it.
+type dependentReferences struct {
+	objects     []string
+	ids         []string
+	targetTypes []string
+}
+
+var dependentReferenceKeys = map[string]dependentReferences{
+	"escalation_policy": {
+		objects: []string{"escalationPolicy"},
+		ids:     []string{"escalationPolicyId"},
+	},
+	"schedule": {
+		objects:     []string{"schedule", "schedules"},
+		ids:         []string{"scheduleId"},
+		targetTypes: []string{"ON_CALL_SCHEDULE"},
+	},
+	"support_hour": {
+		objects: []string{"supportHours"},
+		ids:     []string{"supportHoursId", "waitStartSupportHoursId", "waitEndSupportHoursId"},
+	},
+	"team": {
+		objects: []string{"teams"},
+	},
+}
+
+// dependentObject is an object that references the object about to be deleted.
+type dependentObject struct {
+	kind string
+	id   int64
+	name string
+}
+
+// dependentLister lists one page of the objects of a kind that may reference
+// others.
+type dependentLister struct {
+	kind string
+	list func(client *ilert.Client, start int) ([]any, error)
+}
+
+var dependentListers = []dependentLister{
+	{
+		kind: "alert source",
+		list: func(client *ilert.Client, start int) ([]any, error) {
+			r, err := client.GetAlertSources(&ilert.GetAlertSourcesInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(dependentsPageSize)})
+			if err != nil || r == nil {
+				return nil, err
+			}
+			items := make([]any, 0, len(r.AlertSources))
+			for _, it := range r.AlertSources {
+				items = append(items, it)
+			}
+			return items, nil
+		},
+	},
+	{
+		kind: "event flow",
+		list: func(client *ilert.Client, start int) ([]any, error) {
+			r, err := client.GetEventFlows(&ilert.GetEventFlowsInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(dependentsPageSize)})
+			if err != nil || r == nil {
+				return nil, err
+			}
+			items := make([]any, 0, len(r.EventFlows))
+			for _, it := range r.EventFlows {
+				items = append(items, it)
+			}
+			return items, nil
+		},
+	},
+	{
+		kind: "call flow",
+		list: func(client *ilert.Client, start int) ([]any, error) {
+			r, err := client.GetCallFlows(&ilert.GetCallFlowsInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(dependentsPageSize)})
+			if err != nil || r == nil {
+				return nil, err
+			}
+			items := make([]any, 0, len(r.CallFlows))
+			for _, it := range r.CallFlows {
+				items = append(items, it)
+			}
+			return items, nil
+		},
+	},
+	{
+		kind: "status page",
+		list: func(client *ilert.Client, start int) ([]any, error) {
+			r, err := client.GetStatusPages(&ilert.GetStatusPagesInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(dependentsPageSize)})
+			if err != nil || r == nil {
+				return nil, err
+			}
+			items := make([]any, 0, len(r.StatusPages))
+			for _, it := range r.StatusPages {
+				items = append(items, it)
+			}
+			return items, nil
+		},
+	},
+}
+
+// findDependents lists the alert sources, event flows, call flows and status
+// pages that reference the object of the given kind and id.
+func findDependents(ctx context.Context, client *ilert.Client, kind string, id int64, timeout time.Duration) ([]dependentObject, error) {
+	dependents := make([]dependentObject, 0)
+	for _, lister := range dependentListers {
+		for start := 0; ; start += dependentsPageSize {
+			var items []any
+			err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+				r, err := lister.list(client, start)
+				if err != nil {
+					if _, ok := err.(*ilert.RetryableAPIError); ok {
+						time.Sleep(2 * time.Second)
+						return resource.RetryableError(fmt.Errorf("waiting for %ss to be listed, error: %s", lister.kind, err.Error()))
+					}
+					return resource.NonRetryableError(fmt.Errorf("could not list %ss, error: %s", lister.kind, err.Error()))
+				}
+				items = r
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				dependent, ok, err := dependentOf(lister.kind, item, kind, id)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					dependents = append(dependents, dependent)
+				}
+			}
+			if len(items) < dependentsPageSize {
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// dependentOf reports whether an API object of the given dependent kind
+// references the object of kind and id.
+func dependentOf(dependentKind string, item any, kind string, id int64) (dependentObject, bool, error) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return dependentObject{}, false, err
+	}
+	var v map[string]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return dependentObject{}, false, err
+	}
+	if !referencesObject(v, dependentReferenceKeys[kind], id) {
+		return dependentObject{}, false, nil
+	}
+	dependent := dependentObject{kind: dependentKind}
+	dependent.id, _ = referenceID(v["id"])
+	dependent.name, _ = v["name"].(string)
+	return dependent, true, nil
+}
+
+// referencesObject walks an API object decoded from JSON and reports whether it
+// references the given id by one of the keys in refs. Referenced objects of
+// any kind are not walked into: an alert source references the schedules of
+// its escalation policy only through that policy.
+func referencesObject(v any, refs dependentReferences, id int64) bool {
+	switch t := v.(type) {
+	case map[string]any:
+		if targetType, _ := t["type"].(string); slices.Contains(refs.targetTypes, targetType) {
+			if target, ok := referenceID(t["target"]); ok && target == id {
+				return true
+			}
+		}
+		for k, it := range t {
+			if slices.Contains(refs.ids, k) {
+				if ref, ok := referenceID(it); ok && ref == id {
+					return true
+				}
+				continue
+			}
+			if slices.Contains(refs.objects, k) {
+				if referencedObjectHasID(it, id) {
+					return true
+				}
+				continue
+			}
+			if isReferenceKey(k) {
+				continue
+			}
+			if referencesObject(it, refs, id) {
+				return true
+			}
+		}
+	case []any:
+		for _, it := range t {
+			if referencesObject(it, refs, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func referencedObjectHasID(v any, id int64) bool {
+	switch t := v.(type) {
+	case map[string]any:
+		ref, ok := referenceID(t["id"])
+		return ok && ref == id
+	case []any:
+		for _, it := range t {
+			if referencedObjectHasID(it, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isReferenceKey(k string) bool {
+	for _, refs := range dependentReferenceKeys {
+		if slices.Contains(refs.objects, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func referenceID(v any) (int64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return int64(t), t > 0
+	case string:
+		id, err := strconv.ParseInt(t, 10, 64)
+		return id, err == nil && id > 0
+	}
+	return 0, false
+}
+
+// checkDeletion is called by the Delete of a resource with deletion_protection
+// before the object is deleted, and fails while deletion protection is enabled.
+func checkDeletion(d *schema.ResourceData, kind string) diag.Diagnostics {
+	if !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+	label := strings.ReplaceAll(kind, "_", " ")
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Cannot delete %s %q while deletion_protection is enabled", label, d.Get("name").(string)),
+			Detail:   fmt.Sprintf("Set deletion_protection = false and apply before destroying the %s.", label),
+		},
+	}
+}
+
+// deletionError explains a delete the API rejected with a bad request by
+// listing the objects that still reference the object, so that references of
+// objects not managed here can be removed first. The objects are only looked
+// up once the API rejected the delete, as that lists every alert source, flow
+// and status page of the account. err is returned as it is when apiErr is
+// another error or no references are found.
+func deletionError(ctx context.Context, d *schema.ResourceData, client *ilert.Client, kind string, id int64, apiErr, err error) diag.Diagnostics {
+	if _, ok := apiErr.(*ilert.BadRequestAPIError); !ok {
+		return diag.FromErr(err)
+	}
+	label := strings.ReplaceAll(kind, "_", " ")
+	dependents, lookupErr := findDependents(ctx, client, kind, id, d.Timeout(schema.TimeoutDelete))
+	if lookupErr != nil {
+		log.Printf("[WARN] Could not look up the objects that reference %s %d: %s", label, id, lookupErr.Error())
+		return diag.FromErr(err)
+	}
+	if len(dependents) == 0 {
+		return diag.FromErr(err)
+	}
+	diagnostic := dependentsDiagnostic(label, d.Get("name").(string), dependents)
+	diagnostic.Detail += "\n\n" + err.Error()
+	return diag.Diagnostics{diagnostic}
+}
+
+func dependentsDiagnostic(label, name string, dependents []dependentObject) diag.Diagnostic {
+	lines := make([]string, 0, len(dependents))
+	for _, dependent := range dependents {
+		lines = append(lines, fmt.Sprintf("- %s %q (id %d)", dependent.kind, dependent.name, dependent.id))
+	}
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Cannot delete %s %q: it is referenced by %d object(s)", label, name, len(dependents)),
+		Detail: fmt.Sprintf("Remove the references to the %s from the following objects first:\n%s",
+			label, strings.Join(lines, "\n")),
+	}
+}
@@ -0,0 +1,124 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDependentOf(t *testing.T) {
+	alertSource := map[string]any{
+		"id":   float64(10),
+		"name": "database",
+		"escalationPolicy": map[string]any{
+			"id":              float64(1),
+			"escalationRules": []any{map[string]any{"schedule": map[string]any{"id": float64(2)}}},
+		},
+		"supportHours": map[string]any{"id": float64(3)},
+		"teams":        []any{map[string]any{"id": float64(4), "name": "platform"}},
+	}
+	callFlow := map[string]any{
+		"id":   float64(11),
+		"name": "hotline",
+		"rootNode": map[string]any{
+			"nodeType": "ROOT",
+			"branches": []any{
+				map[string]any{
+					"branchType": "ANSWERED",
+					"target": map[string]any{
+						"nodeType": "ROUTE_CALL",
+						"metadata": map[string]any{
+							"targets": []any{map[string]any{"type": "ON_CALL_SCHEDULE", "target": "2"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	eventFlow := map[string]any{
+		"id":   float64(12),
+		"name": "routing",
+		"rootNode": map[string]any{
+			"branches": []any{
+				map[string]any{"target": map[string]any{"metadata": map[string]any{"escalationPolicyId": float64(1), "waitStartSupportHoursId": float64(3)}}},
+			},
+		},
+	}
+
+	cases := []struct {
+		item any
+		kind string
+		id   int64
+		want bool
+	}{
+		{alertSource, "escalation_policy", 1, true},
+		{alertSource, "escalation_policy", 5, false},
+		{alertSource, "support_hour", 3, true},
+		{alertSource, "team", 4, true},
+		// the schedule is referenced by the escalation policy, not by the
+		// alert source
+		{alertSource, "schedule", 2, false},
+		{callFlow, "schedule", 2, true},
+		{callFlow, "team", 2, false},
+		{eventFlow, "escalation_policy", 1, true},
+		{eventFlow, "support_hour", 3, true},
+	}
+	for _, tc := range cases {
+		dependent, ok, err := dependentOf("object", tc.item, tc.kind, tc.id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok != tc.want {
+			t.Errorf("dependentOf(%v, %s, %d) = %t, want %t", tc.item.(map[string]any)["name"], tc.kind, tc.id, ok, tc.want)
+		}
+		if ok && (dependent.id != int64(tc.item.(map[string]any)["id"].(float64)) || dependent.name != tc.item.(map[string]any)["name"]) {
+			t.Errorf("unexpected dependent %+v", dependent)
+		}
+	}
+}
+
+func TestCheckDeletion_Protected(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"name":                {Type: schema.TypeString, Required: true},
+		"deletion_protection": deletionProtectionSchema(),
+	}, map[string]any{"name": "platform", "deletion_protection": true})
+
+	diags := checkDeletion(d, "team")
+	if !diags.HasError() || diags[0].Summary != `Cannot delete team "platform" while deletion_protection is enabled` {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+
+	d.Set("deletion_protection", false)
+	if diags := checkDeletion(d, "team"); diags != nil {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestDeletionError_NotABadRequest(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"name": {Type: schema.TypeString, Required: true},
+	}, map[string]any{"name": "platform"})
+
+	// only a rejected delete is explained, so no client is needed otherwise
+	err := fmt.Errorf("could not delete a team with ID 1, error: forbidden")
+	diags := deletionError(context.Background(), d, nil, "team", 1, fmt.Errorf("forbidden"), err)
+	if !diags.HasError() || diags[0].Summary != err.Error() {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestDependentsDiagnostic(t *testing.T) {
+	diagnostic := dependentsDiagnostic("escalation policy", "default", []dependentObject{
+		{kind: "alert source", id: 10, name: "database"},
+		{kind: "event flow", id: 12, name: "routing"},
+	})
+	if diagnostic.Summary != `Cannot delete escalation policy "default": it is referenced by 2 object(s)` {
+		t.Errorf("unexpected summary %q", diagnostic.Summary)
+	}
+	if !strings.HasSuffix(diagnostic.Detail, "\n- alert source \"database\" (id 10)\n- event flow \"routing\" (id 12)") {
+		t.Errorf("unexpected detail %q", diagnostic.Detail)
+	}
+}
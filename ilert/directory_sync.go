@@ -0,0 +1,289 @@
+package ilert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+// Actions of a directory sync change, in the order they are applied: users are
+// created before they are added to teams, and removed users are deactivated or
+// deleted last. Changes are listed by user, see sortDirectorySyncChanges.
+const (
+	directorySyncCreate         = "create"
+	directorySyncUpdate         = "update"
+	directorySyncAddToTeam      = "add_to_team"
+	directorySyncUpdateTeamRole = "update_team_role"
+	directorySyncRemoveFromTeam = "remove_from_team"
+	directorySyncDeactivate     = "deactivate"
+	directorySyncDelete         = "delete"
+)
+
+var directorySyncActionOrder = []string{
+	directorySyncCreate,
+	directorySyncUpdate,
+	directorySyncAddToTeam,
+	directorySyncUpdateTeamRole,
+	directorySyncRemoveFromTeam,
+	directorySyncDeactivate,
+	directorySyncDelete,
+}
+
+// directorySyncDeactivatedRole is the role removed users are given unless
+// delete_removed_users is set, the one with the fewest permissions.
+const directorySyncDeactivatedRole = "GUEST"
+
+// directorySyncUser is a user as listed by the directory, with the role it has
+// in each of its teams by team id.
+type directorySyncUser struct {
+	email     string
+	firstName string
+	lastName  string
+	role      string
+	teams     map[int64]string
+}
+
+// directorySyncChange is a single change a directory sync makes to the account.
+type directorySyncChange struct {
+	action string
+	user   directorySyncUser
+	userID int64
+	teamID int64
+	role   string
+	from   string
+	// removed is set on the changes of a user that is no longer listed
+	removed bool
+}
+
+func (c directorySyncChange) String() string {
+	switch c.action {
+	case directorySyncCreate:
+		return fmt.Sprintf("create user %s (%s %s, %s)", c.user.email, c.user.firstName, c.user.lastName, c.user.role)
+	case directorySyncUpdate:
+		return fmt.Sprintf("update user %s: %s", c.user.email, c.from)
+	case directorySyncAddToTeam:
+		return fmt.Sprintf("add %s to team %d as %s", c.user.email, c.teamID, c.role)
+	case directorySyncUpdateTeamRole:
+		return fmt.Sprintf("change the role of %s in team %d from %s to %s", c.user.email, c.teamID, c.from, c.role)
+	case directorySyncRemoveFromTeam:
+		return fmt.Sprintf("remove %s from team %d", c.user.email, c.teamID)
+	case directorySyncDeactivate:
+		return fmt.Sprintf("deactivate user %s: role %s -> %s", c.user.email, c.from, c.role)
+	case directorySyncDelete:
+		return fmt.Sprintf("delete user %s", c.user.email)
+	}
+	return fmt.Sprintf("%s %s", c.action, c.user.email)
+}
+
+func expandDirectorySyncUsers(v any) ([]directorySyncUser, error) {
+	users := make([]directorySyncUser, 0)
+	seen := make(map[string]bool)
+	uL, _ := v.([]any)
+	for i, it := range uL {
+		u, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		user := directorySyncUser{
+			email:     strings.ToLower(strings.TrimSpace(u["email"].(string))),
+			firstName: u["first_name"].(string),
+			lastName:  u["last_name"].(string),
+			role:      u["role"].(string),
+			teams:     make(map[int64]string),
+		}
+		if seen[user.email] {
+			return nil, fmt.Errorf("user %d: the email %s is listed more than once", i, user.email)
+		}
+		seen[user.email] = true
+
+		tL, _ := u["team"].([]any)
+		for _, t := range tL {
+			team, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			id := team["id"].(string)
+			teamID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return nil, unconvertibleIDErr(id, err)
+			}
+			user.teams[teamID] = team["role"].(string)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// directorySyncTeamIDs returns the ids of the teams the desired or the
+// previously synced users are members of, in ascending order.
+func directorySyncTeamIDs(desired, previous []directorySyncUser) []int64 {
+	seen := make(map[int64]bool)
+	ids := make([]int64, 0)
+	for _, users := range [][]directorySyncUser{desired, previous} {
+		for _, user := range users {
+			for id := range user.teams {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// flattenDirectorySyncUsers is the inverse of expandDirectorySyncUsers, with
+// the teams of each user ordered by id.
+func flattenDirectorySyncUsers(users []directorySyncUser) []any {
+	result := make([]any, 0, len(users))
+	for _, user := range users {
+		ids := make([]int64, 0, len(user.teams))
+		for id := range user.teams {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		teams := make([]any, 0, len(ids))
+		for _, id := range ids {
+			teams = append(teams, map[string]any{
+				"id":   strconv.FormatInt(id, 10),
+				"role": user.teams[id],
+			})
+		}
+		result = append(result, map[string]any{
+			"email":      user.email,
+			"first_name": user.firstName,
+			"last_name":  user.lastName,
+			"role":       user.role,
+			"team":       teams,
+		})
+	}
+	return result
+}
+
+// planDirectorySync compares the desired users with the account and returns the
+// changes that make the account match. accounts holds the account user of each
+// desired and previous email, nil for those without one, and teams the teams
+// returned by directorySyncTeamIDs. Only users and team memberships listed by
+// the previous sync are removed; everything else in the account is left alone.
+// Removed users are deleted with deleteRemoved, and otherwise removed from
+// their synced teams and given directorySyncDeactivatedRole.
+func planDirectorySync(desired, previous []directorySyncUser, accounts map[string]*ilert.User, teams map[int64]*ilert.Team, deleteRemoved bool) []directorySyncChange {
+	changes := make([]directorySyncChange, 0)
+	desiredEmails := make(map[string]bool, len(desired))
+
+	for _, user := range desired {
+		desiredEmails[user.email] = true
+		account := accounts[user.email]
+		if account == nil {
+			changes = append(changes, directorySyncChange{action: directorySyncCreate, user: user})
+			for teamID, role := range user.teams {
+				changes = append(changes, directorySyncChange{action: directorySyncAddToTeam, user: user, teamID: teamID, role: role})
+			}
+			continue
+		}
+
+		if diff := directorySyncUserDiff(user, account); diff != "" {
+			changes = append(changes, directorySyncChange{action: directorySyncUpdate, user: user, userID: account.ID, from: diff})
+		}
+		for teamID, role := range user.teams {
+			team := teams[teamID]
+			if team == nil {
+				continue
+			}
+			member := findTeamMember(team.Members, account.ID)
+			switch {
+			case member == nil:
+				changes = append(changes, directorySyncChange{action: directorySyncAddToTeam, user: user, userID: account.ID, teamID: teamID, role: role})
+			case member.Role != role:
+				changes = append(changes, directorySyncChange{action: directorySyncUpdateTeamRole, user: user, userID: account.ID, teamID: teamID, role: role, from: member.Role})
+			}
+		}
+	}
+
+	desiredTeams := make(map[string]map[int64]string, len(desired))
+	for _, user := range desired {
+		desiredTeams[user.email] = user.teams
+	}
+	for _, user := range previous {
+		account := accounts[user.email]
+		if account == nil {
+			continue
+		}
+		removed := !desiredEmails[user.email]
+		if removed && deleteRemoved {
+			changes = append(changes, directorySyncChange{action: directorySyncDelete, user: user, userID: account.ID, removed: true})
+			continue
+		}
+		if removed && account.Role != directorySyncDeactivatedRole {
+			changes = append(changes, directorySyncChange{action: directorySyncDeactivate, user: user, userID: account.ID, role: directorySyncDeactivatedRole, from: account.Role, removed: true})
+		}
+		for teamID := range user.teams {
+			if _, ok := desiredTeams[user.email][teamID]; ok {
+				continue
+			}
+			if team := teams[teamID]; team != nil && findTeamMember(team.Members, account.ID) != nil {
+				changes = append(changes, directorySyncChange{action: directorySyncRemoveFromTeam, user: user, userID: account.ID, teamID: teamID, removed: removed})
+			}
+		}
+	}
+
+	sortDirectorySyncChanges(changes)
+	return changes
+}
+
+// directorySyncUserDiff describes how the account user differs from the
+// directory, or returns an empty string when it does not.
+func directorySyncUserDiff(user directorySyncUser, account *ilert.User) string {
+	diffs := make([]string, 0)
+	if account.FirstName != user.firstName {
+		diffs = append(diffs, fmt.Sprintf("first_name %q -> %q", account.FirstName, user.firstName))
+	}
+	if account.LastName != user.lastName {
+		diffs = append(diffs, fmt.Sprintf("last_name %q -> %q", account.LastName, user.lastName))
+	}
+	if account.Role != user.role {
+		diffs = append(diffs, fmt.Sprintf("role %s -> %s", account.Role, user.role))
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func sortDirectorySyncChanges(changes []directorySyncChange) {
+	order := make(map[string]int, len(directorySyncActionOrder))
+	for i, action := range directorySyncActionOrder {
+		order[action] = i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.user.email != b.user.email {
+			return a.user.email < b.user.email
+		}
+		if order[a.action] != order[b.action] {
+			return order[a.action] < order[b.action]
+		}
+		return a.teamID < b.teamID
+	})
+}
+
+// countDirectorySyncRemovals counts the users that are no longer listed and
+// would be deleted, deactivated or removed from their teams.
+func countDirectorySyncRemovals(changes []directorySyncChange) int {
+	users := make(map[string]bool)
+	for _, change := range changes {
+		if change.removed {
+			users[change.user.email] = true
+		}
+	}
+	return len(users)
+}
+
+func flattenDirectorySyncChanges(changes []directorySyncChange) []any {
+	result := make([]any, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	return result
+}
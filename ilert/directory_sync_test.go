@@ -0,0 +1,176 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestExpandDirectorySyncUsers(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDirectorySync().Schema, map[string]any{
+		"user": []any{
+			map[string]any{
+				"email":      " Jane.Doe@Example.com",
+				"first_name": "Jane",
+				"last_name":  "Doe",
+				"team":       []any{map[string]any{"id": "10"}, map[string]any{"id": "11", "role": "ADMIN"}},
+			},
+		},
+	})
+	users, err := expandDirectorySyncUsers(d.Get("user"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 1 || users[0].email != "jane.doe@example.com" || users[0].role != "USER" {
+		t.Fatalf("unexpected users %+v", users)
+	}
+	if users[0].teams[10] != ilert.TeamMemberRoles.Responder || users[0].teams[11] != ilert.TeamMemberRoles.Admin {
+		t.Fatalf("unexpected teams %v", users[0].teams)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceDirectorySync().Schema, map[string]any{
+		"user": []any{
+			map[string]any{"email": "jane@example.com", "first_name": "Jane", "last_name": "Doe"},
+			map[string]any{"email": "JANE@example.com", "first_name": "Jane", "last_name": "Doe"},
+		},
+	})
+	if _, err := expandDirectorySyncUsers(d.Get("user")); err == nil || !strings.Contains(err.Error(), "listed more than once") {
+		t.Fatalf("expected a duplicate email error, got %v", err)
+	}
+}
+
+func TestPlanDirectorySync(t *testing.T) {
+	desired := []directorySyncUser{
+		{email: "new@example.com", firstName: "New", lastName: "User", role: "USER", teams: map[int64]string{10: "RESPONDER"}},
+		{email: "renamed@example.com", firstName: "Renamed", lastName: "User", role: "ADMIN", teams: map[int64]string{10: "ADMIN"}},
+		{email: "same@example.com", firstName: "Same", lastName: "User", role: "USER", teams: map[int64]string{}},
+	}
+	previous := []directorySyncUser{
+		{email: "renamed@example.com", firstName: "Old", lastName: "User", role: "USER", teams: map[int64]string{10: "USER"}},
+		{email: "same@example.com", firstName: "Same", lastName: "User", role: "USER", teams: map[int64]string{11: "USER"}},
+		{email: "gone@example.com", firstName: "Gone", lastName: "User", role: "USER", teams: map[int64]string{11: "USER"}},
+		{email: "never-created@example.com", firstName: "Never", lastName: "Created", role: "USER", teams: map[int64]string{}},
+	}
+	accounts := map[string]*ilert.User{
+		"new@example.com":           nil,
+		"renamed@example.com":       {ID: 2, FirstName: "Old", LastName: "User", Role: "USER"},
+		"same@example.com":          {ID: 3, FirstName: "Same", LastName: "User", Role: "USER"},
+		"gone@example.com":          {ID: 4, FirstName: "Gone", LastName: "User", Role: "USER"},
+		"never-created@example.com": nil,
+	}
+	teams := map[int64]*ilert.Team{
+		10: {ID: 10, Members: []ilert.TeamMember{newTeamMember(2, "USER"), newTeamMember(99, "ADMIN")}},
+		11: {ID: 11, Members: []ilert.TeamMember{newTeamMember(3, "USER"), newTeamMember(4, "USER")}},
+	}
+	if got := directorySyncTeamIDs(desired, previous); len(got) != 2 || got[0] != 10 || got[1] != 11 {
+		t.Fatalf("unexpected team ids %v", got)
+	}
+
+	changes := planDirectorySync(desired, previous, accounts, teams, false)
+	want := []string{
+		"remove gone@example.com from team 11",
+		"deactivate user gone@example.com: role USER -> GUEST",
+		"create user new@example.com (New User, USER)",
+		"add new@example.com to team 10 as RESPONDER",
+		`update user renamed@example.com: first_name "Old" -> "Renamed", role USER -> ADMIN`,
+		"change the role of renamed@example.com in team 10 from USER to ADMIN",
+		"remove same@example.com from team 11",
+	}
+	assertDirectorySyncChanges(t, changes, want)
+	// gone@example.com is removed, same@example.com only leaves a team
+	if err := checkDirectorySyncDeletions(changes, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkDirectorySyncDeletions(changes, 0); err == nil {
+		t.Fatalf("expected max_deletions to count deactivations")
+	}
+
+	changes = planDirectorySync(desired, previous, accounts, teams, true)
+	want = append([]string{"delete user gone@example.com"}, want[2:]...)
+	assertDirectorySyncChanges(t, changes, want)
+	if err := checkDirectorySyncDeletions(changes, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkDirectorySyncDeletions(changes, 0); err == nil {
+		t.Fatalf("expected max_deletions to be enforced")
+	}
+}
+
+func TestPlanDirectorySync_EmptyList(t *testing.T) {
+	previous := []directorySyncUser{
+		{email: "a@example.com", firstName: "A", lastName: "User", role: "USER", teams: map[int64]string{10: "USER"}},
+		{email: "b@example.com", firstName: "B", lastName: "User", role: "USER", teams: map[int64]string{}},
+		{email: "c@example.com", firstName: "C", lastName: "User", role: directorySyncDeactivatedRole, teams: map[int64]string{10: "USER"}},
+	}
+	accounts := map[string]*ilert.User{
+		"a@example.com": {ID: 1, FirstName: "A", LastName: "User", Role: "USER"},
+		"b@example.com": {ID: 2, FirstName: "B", LastName: "User", Role: "USER"},
+		"c@example.com": {ID: 3, FirstName: "C", LastName: "User", Role: directorySyncDeactivatedRole},
+	}
+	teams := map[int64]*ilert.Team{
+		10: {ID: 10, Members: []ilert.TeamMember{newTeamMember(1, "USER"), newTeamMember(3, "USER")}},
+	}
+
+	// an empty export must not demote or remove everyone below the limit
+	changes := planDirectorySync(nil, previous, accounts, teams, false)
+	if n := countDirectorySyncRemovals(changes); n != 3 {
+		t.Fatalf("expected 3 removed users, got %d: %v", n, flattenDirectorySyncChanges(changes))
+	}
+	if err := checkDirectorySyncDeletions(changes, 2); err == nil {
+		t.Fatalf("expected max_deletions to be enforced")
+	}
+	if err := checkDirectorySyncDeletions(changes, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPlanDirectorySync_AlreadyDeactivated(t *testing.T) {
+	previous := []directorySyncUser{
+		{email: "gone@example.com", firstName: "Gone", lastName: "User", role: "USER", teams: map[int64]string{}},
+	}
+	accounts := map[string]*ilert.User{
+		"gone@example.com": {ID: 4, FirstName: "Gone", LastName: "User", Role: directorySyncDeactivatedRole},
+	}
+	if changes := planDirectorySync(nil, previous, accounts, map[int64]*ilert.Team{}, false); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", flattenDirectorySyncChanges(changes))
+	}
+}
+
+func TestDirectorySyncPlannedChanges_NoPlan(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDirectorySync().Schema, map[string]any{})
+	if planned, ok := directorySyncPlannedChanges(d); ok {
+		t.Fatalf("expected no planned changes without a plan, got %v", planned)
+	}
+}
+
+func TestFlattenDirectorySyncUsers(t *testing.T) {
+	users := []directorySyncUser{
+		{email: "jane@example.com", firstName: "Jane", lastName: "Doe", role: "ADMIN", teams: map[int64]string{11: "ADMIN", 10: "RESPONDER"}},
+	}
+	flattened := flattenDirectorySyncUsers(users)
+	teams := flattened[0].(map[string]any)["team"].([]any)
+	if len(teams) != 2 || teams[0].(map[string]any)["id"] != "10" || teams[1].(map[string]any)["role"] != "ADMIN" {
+		t.Fatalf("unexpected teams %v", teams)
+	}
+	expanded, err := expandDirectorySyncUsers(flattened)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expanded) != 1 || expanded[0].email != "jane@example.com" || expanded[0].role != "ADMIN" || len(expanded[0].teams) != 2 || expanded[0].teams[11] != "ADMIN" {
+		t.Fatalf("users do not round-trip: %+v", expanded)
+	}
+}
+
+func assertDirectorySyncChanges(t *testing.T, changes []directorySyncChange, want []string) {
+	t.Helper()
+	got := flattenDirectorySyncChanges(changes)
+	gotL := make([]string, 0, len(got))
+	for _, change := range got {
+		gotL = append(gotL, change.(string))
+	}
+	if strings.Join(gotL, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes\n got: %s\nwant: %s", strings.Join(gotL, "\n      "), strings.Join(want, "\n      "))
+	}
+}
@@ -0,0 +1,253 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// escalationPolicyLintRules are the rules of the escalation policy linter, set
+// in the escalation_policy block of the provider's lint block.
+var escalationPolicyLintRules = []string{
+	"first_rule_without_target",
+	"repeating_without_fallback",
+	"schedule_without_layers",
+	"duplicate_routing_key",
+	"stakeholder_target",
+}
+
+// escalationPolicyLintPageSize is the page size used to list escalation
+// policies when looking for duplicate routing keys.
+const escalationPolicyLintPageSize = 100
+
+// lintEscalationPolicy checks an escalation policy for configurations that
+// cause missed pages. get reads the planned or applied attributes. The rules
+// that look up schedules and other escalation policies are skipped without a
+// client.
+func lintEscalationPolicy(ctx context.Context, get func(string) any, id string, client *ilert.Client) []lintFinding {
+	name := get("name").(string)
+	rules, _ := get("escalation_rule").([]any)
+
+	findings := lintEscalationPolicyRules(name, rules, get("repeating").(bool), get("frequency").(int))
+	if client == nil {
+		return findings
+	}
+	ctx, cancel := context.WithTimeout(ctx, lintTimeout)
+	defer cancel()
+	findings = append(findings, lintEscalationPolicySchedules(ctx, name, rules, client)...)
+	findings = append(findings, lintEscalationPolicyStakeholders(name, rules, newStakeholderLookup(ctx, client))...)
+	if routingKey := get("routing_key").(string); routingKey != "" {
+		findings = append(findings, lintEscalationPolicyRoutingKey(ctx, name, id, routingKey, client)...)
+	}
+	return findings
+}
+
+// lintEscalationPolicyRules runs the rules that only need the configuration.
+func lintEscalationPolicyRules(name string, rules []any, repeating bool, frequency int) []lintFinding {
+	findings := make([]lintFinding, 0)
+	if len(rules) == 0 {
+		return findings
+	}
+
+	if first, ok := rules[0].(map[string]any); ok && first["escalation_timeout"].(int) == 0 && !escalationRuleHasTarget(first) {
+		findings = append(findings, lintFinding{
+			rule:    "escalation_policy.first_rule_without_target",
+			summary: fmt.Sprintf("Escalation policy %q does not page anybody on the first level", name),
+			detail:  "The first escalation rule has an escalation_timeout of 0 and no user, schedule, users, schedules or teams, so new alerts skip it without notifying anybody.",
+		})
+	}
+
+	if last, ok := rules[len(rules)-1].(map[string]any); ok && repeating && !escalationRuleHasFallback(last) {
+		findings = append(findings, lintFinding{
+			rule:    "escalation_policy.repeating_without_fallback",
+			summary: fmt.Sprintf("Escalation policy %q repeats without a fallback on the last level", name),
+			detail: fmt.Sprintf("The policy repeats %d time(s), but its last escalation rule only targets individual users. Once they are unavailable the alert loops through the policy without reaching anybody; target a schedule or a team on the last level.",
+				frequency),
+		})
+	}
+	return findings
+}
+
+func escalationRuleHasTarget(rule map[string]any) bool {
+	if user, _ := rule["user"].(string); user != "" {
+		return true
+	}
+	if uL, _ := rule["users"].([]any); len(uL) > 0 {
+		return true
+	}
+	return escalationRuleHasFallback(rule)
+}
+
+// escalationRuleHasFallback reports whether a rule targets a schedule or a team
+// rather than individual users.
+func escalationRuleHasFallback(rule map[string]any) bool {
+	if schedule, _ := rule["schedule"].(string); schedule != "" {
+		return true
+	}
+	if sL, _ := rule["schedules"].([]any); len(sL) > 0 {
+		return true
+	}
+	tL, _ := rule["teams"].([]any)
+	return len(tL) > 0
+}
+
+// escalationRuleScheduleIDs returns the ids of the schedules the rules target,
+// in order and without duplicates.
+func escalationRuleScheduleIDs(rules []any) []string {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, it := range rules {
+		rule, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		if schedule, _ := rule["schedule"].(string); schedule != "" {
+			add(schedule)
+		}
+		sL, _ := rule["schedules"].([]any)
+		for _, s := range sL {
+			if v, ok := s.(map[string]any); ok {
+				id, _ := v["id"].(string)
+				add(id)
+			}
+		}
+	}
+	return ids
+}
+
+func lintEscalationPolicySchedules(ctx context.Context, name string, rules []any, client *ilert.Client) []lintFinding {
+	findings := make([]lintFinding, 0)
+	for _, id := range escalationRuleScheduleIDs(rules) {
+		scheduleID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		var r *ilert.GetScheduleOutput
+		err = lintRead(ctx, func() error {
+			var err error
+			r, err = client.GetSchedule(&ilert.GetScheduleInput{ScheduleID: ilert.Int64(scheduleID), Include: []*string{ilert.String("scheduleLayers")}})
+			return err
+		})
+		if err != nil {
+			log.Printf("[WARN] Could not read schedule %s to lint escalation policy %q: %s", id, name, err.Error())
+			continue
+		}
+		if r == nil || r.Schedule == nil || r.Schedule.Type != ilert.ScheduleType.Recurring || len(r.Schedule.ScheduleLayers) > 0 {
+			continue
+		}
+		findings = append(findings, lintFinding{
+			rule:    "escalation_policy.schedule_without_layers",
+			summary: fmt.Sprintf("Escalation policy %q targets schedule %q, which has no layers", name, r.Schedule.Name),
+			detail:  fmt.Sprintf("The recurring schedule %s has no schedule_layer, so nobody is on call in it and its escalation level pages nobody.", id),
+		})
+	}
+	return findings
+}
+
+// lintEscalationPolicyStakeholders reports the users with the STAKEHOLDER role
+// that an escalation rule pages directly. They are notified but cannot accept
+// the alert, so the policy escalates past them.
+func lintEscalationPolicyStakeholders(name string, rules []any, stakeholder stakeholderLookup) []lintFinding {
+	findings := make([]lintFinding, 0)
+	for i, it := range rules {
+		rule, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		ids := make([]string, 0)
+		if user, _ := rule["user"].(string); user != "" {
+			ids = append(ids, user)
+		}
+		uL, _ := rule["users"].([]any)
+		for _, u := range uL {
+			if v, ok := u.(map[string]any); ok {
+				id, _ := v["id"].(string)
+				ids = append(ids, id)
+			}
+		}
+		for _, id := range ids {
+			user := stakeholder(id)
+			if user == nil {
+				continue
+			}
+			findings = append(findings, lintFinding{
+				rule:    "escalation_policy.stakeholder_target",
+				summary: fmt.Sprintf("Escalation policy %q pages the stakeholder %s", name, user.Email),
+				detail:  fmt.Sprintf("Escalation rule %d targets user %s, who has the STAKEHOLDER role and cannot respond to alerts.", i+1, id),
+			})
+		}
+	}
+	return findings
+}
+
+func lintEscalationPolicyRoutingKey(ctx context.Context, name, id, routingKey string, client *ilert.Client) []lintFinding {
+	duplicates := make([]string, 0)
+	for start := 0; ; start += escalationPolicyLintPageSize {
+		var r *ilert.GetEscalationPoliciesOutput
+		err := lintRead(ctx, func() error {
+			var err error
+			r, err = client.GetEscalationPolicies(&ilert.GetEscalationPoliciesInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(escalationPolicyLintPageSize)})
+			return err
+		})
+		if err != nil {
+			log.Printf("[WARN] Could not list escalation policies to lint escalation policy %q: %s", name, err.Error())
+			return nil
+		}
+		if r == nil {
+			break
+		}
+		for _, policy := range r.EscalationPolicies {
+			if policy != nil && policy.RoutingKey == routingKey && strconv.FormatInt(policy.ID, 10) != id {
+				duplicates = append(duplicates, fmt.Sprintf("%q (%d)", policy.Name, policy.ID))
+			}
+		}
+		if len(r.EscalationPolicies) < escalationPolicyLintPageSize {
+			break
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	sort.Strings(duplicates)
+	return []lintFinding{
+		{
+			rule:    "escalation_policy.duplicate_routing_key",
+			summary: fmt.Sprintf("Escalation policy %q shares its routing_key %q", name, routingKey),
+			detail:  fmt.Sprintf("The routing key is also used by %s. Alerts sent with it are routed to only one of these policies.", strings.Join(duplicates, ", ")),
+		},
+	}
+}
+
+// customizeEscalationPolicyLint lints the planned escalation policy. It only
+// runs when the linted attributes change and once every rule is known, so
+// referenced schedules created in the same apply are linted after the apply,
+// and lint_findings is unknown until then.
+func customizeEscalationPolicyLint(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if diff.Id() != "" && !diff.HasChanges("name", "escalation_rule", "repeating", "frequency", "routing_key") {
+		return nil
+	}
+	if !diffValuesKnown(diff, "escalation_rule") || !diff.NewValueKnown("routing_key") {
+		return diff.SetNewComputed("lint_findings")
+	}
+	client := providerClient(m)
+	return customizeLintFindings(diff, lintConfigFor(m), lintEscalationPolicy(ctx, diff.Get, diff.Id(), client))
+}
+
+// escalationPolicyLintWarnings lints the applied escalation policy.
+func escalationPolicyLintWarnings(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := providerClient(m)
+	return lintWarnings(d, lintConfigFor(m), lintEscalationPolicy(ctx, d.Get, d.Id(), client))
+}
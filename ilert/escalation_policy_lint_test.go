@@ -0,0 +1,170 @@
+package ilert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestLintEscalationPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]any
+		want   []string
+	}{
+		{
+			name: "clean",
+			config: map[string]any{
+				"escalation_rule": []any{
+					map[string]any{"escalation_timeout": 0, "user": "1"},
+					map[string]any{"escalation_timeout": 15, "schedule": "2"},
+				},
+				"repeating": true,
+			},
+		},
+		{
+			name: "first rule without target",
+			config: map[string]any{
+				"escalation_rule": []any{
+					map[string]any{"escalation_timeout": 0},
+					map[string]any{"escalation_timeout": 15, "teams": []any{map[string]any{"id": "3"}}},
+				},
+			},
+			want: []string{"escalation_policy.first_rule_without_target"},
+		},
+		{
+			name: "delayed first rule without target",
+			config: map[string]any{
+				"escalation_rule": []any{
+					map[string]any{"escalation_timeout": 5},
+				},
+			},
+		},
+		{
+			name: "repeating without fallback",
+			config: map[string]any{
+				"escalation_rule": []any{
+					map[string]any{"escalation_timeout": 0, "schedule": "2"},
+					map[string]any{"escalation_timeout": 15, "users": []any{map[string]any{"id": "1"}, map[string]any{"id": "4"}}},
+				},
+				"repeating": true,
+				"frequency": 3,
+			},
+			want: []string{"escalation_policy.repeating_without_fallback"},
+		},
+		{
+			name: "not repeating without fallback",
+			config: map[string]any{
+				"escalation_rule": []any{
+					map[string]any{"escalation_timeout": 0, "user": "1"},
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc.config["name"] = "test"
+		d := schema.TestResourceDataRaw(t, resourceEscalationPolicy().Schema, tc.config)
+		findings := lintEscalationPolicy(context.Background(), d.Get, "", nil)
+		got := make([]string, 0)
+		for _, finding := range findings {
+			got = append(got, finding.rule)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: got findings %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLintEscalationPolicyStakeholders(t *testing.T) {
+	stakeholder := func(id string) *ilert.User {
+		if id == "2" {
+			return &ilert.User{ID: 2, Email: "viewer@example.com", Role: userRoleStakeholder}
+		}
+		return nil
+	}
+	rules := []any{
+		map[string]any{"escalation_timeout": 0, "user": "1"},
+		map[string]any{"escalation_timeout": 15, "users": []any{map[string]any{"id": "3"}, map[string]any{"id": "2"}}},
+	}
+	findings := lintEscalationPolicyStakeholders("test", rules, stakeholder)
+	if len(findings) != 1 || findings[0].rule != "escalation_policy.stakeholder_target" || !strings.Contains(findings[0].detail, "Escalation rule 2 targets user 2") {
+		t.Fatalf("unexpected findings %+v", findings)
+	}
+}
+
+func TestEscalationRuleScheduleIDs(t *testing.T) {
+	rules := []any{
+		map[string]any{"schedule": "2"},
+		map[string]any{"schedules": []any{map[string]any{"id": "3"}, map[string]any{"id": "2"}, map[string]any{"id": ""}}},
+		map[string]any{"user": "1"},
+	}
+	if got := escalationRuleScheduleIDs(rules); strings.Join(got, ",") != "2,3" {
+		t.Fatalf("unexpected schedule ids %v", got)
+	}
+}
+
+func TestBuildLintConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]any{
+		"lint": []any{
+			map[string]any{
+				"escalation_policy": []any{
+					map[string]any{"first_rule_without_target": "error", "duplicate_routing_key": "off"},
+				},
+			},
+		},
+	})
+	config := buildLintConfig(d.Get("lint"))
+	want := map[string]string{
+		"escalation_policy.first_rule_without_target":  lintSeverityError,
+		"escalation_policy.repeating_without_fallback": lintSeverityWarning,
+		"escalation_policy.schedule_without_layers":    lintSeverityWarning,
+		"escalation_policy.duplicate_routing_key":      lintSeverityOff,
+	}
+	for rule, severity := range want {
+		if got := config.severity(rule); got != severity {
+			t.Errorf("severity of %s = %q, want %q", rule, got, severity)
+		}
+	}
+	if got := buildLintConfig(nil).severity("escalation_policy.duplicate_routing_key"); got != lintSeverityWarning {
+		t.Errorf("default severity = %q, want %q", got, lintSeverityWarning)
+	}
+}
+
+func TestLintSeverities(t *testing.T) {
+	findings := []lintFinding{
+		{rule: "escalation_policy.first_rule_without_target", summary: "first", detail: "first detail"},
+		{rule: "escalation_policy.repeating_without_fallback", summary: "repeating", detail: "repeating detail"},
+		{rule: "escalation_policy.duplicate_routing_key", summary: "duplicate", detail: "duplicate detail"},
+	}
+	config := lintConfig{
+		"escalation_policy.first_rule_without_target": lintSeverityError,
+		"escalation_policy.duplicate_routing_key":     lintSeverityOff,
+	}
+
+	err := lintPlanError(config, findings)
+	if err == nil || err.Error() != "[ERROR] first: first detail (lint rule escalation_policy.first_rule_without_target)" {
+		t.Fatalf("unexpected plan error %v", err)
+	}
+	if err := lintPlanError(lintConfig{}, findings); err != nil {
+		t.Fatalf("warnings must not fail the plan, got %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceEscalationPolicy().Schema, map[string]any{})
+	diags := lintWarnings(d, config, findings)
+	if len(diags) != 2 || diags[0].Summary != "first" || diags[1].Summary != "repeating" {
+		t.Fatalf("unexpected warnings %v", diags)
+	}
+	lintFindings := d.Get("lint_findings").([]any)
+	if len(lintFindings) != 2 || lintFindings[1] != "repeating: repeating detail (lint rule escalation_policy.repeating_without_fallback)" {
+		t.Fatalf("unexpected lint_findings %v", lintFindings)
+	}
+	for _, w := range diags {
+		if w.Severity != diag.Warning {
+			t.Errorf("expected a warning, got %v", w)
+		}
+	}
+}
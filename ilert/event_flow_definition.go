@@ -0,0 +1,243 @@
+package ilert
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// eventFlowNodeMetadataAttributes lists the metadata attributes each node type
+// reads, following the node type notes of the event flow documentation.
+var eventFlowNodeMetadataAttributes = map[string][]string{
+	ilert.EventFlowNodeType.Plain:          {"var_key", "var_value"},
+	ilert.EventFlowNodeType.SupportHours:   {"support_hours_id"},
+	ilert.EventFlowNodeType.RouteEvent:     {"alert_source_id", "overwrite_priority", "escalation_policy_id"},
+	ilert.EventFlowNodeType.DefineBranches: {"definitions"},
+	ilert.EventFlowNodeType.Wait:           {"wait_for_duration", "wait_start_support_hours_id", "wait_end_support_hours_id"},
+	ilert.EventFlowNodeType.Transform:      {"condition", "rules"},
+}
+
+// eventFlowRuleAttributes lists the operands each transform operator requires.
+// Operands an operator does not use are rejected; default goes with any
+// operator.
+var eventFlowRuleAttributes = map[string][]string{
+	ilert.EventFlowNodeRuleOperator.Set:         {"value"},
+	ilert.EventFlowNodeRuleOperator.Template:    {"value"},
+	ilert.EventFlowNodeRuleOperator.Copy:        {"source"},
+	ilert.EventFlowNodeRuleOperator.Map:         {"source", "mapping"},
+	ilert.EventFlowNodeRuleOperator.Merge:       {"properties"},
+	ilert.EventFlowNodeRuleOperator.AppendArray: {"items"},
+}
+
+var eventFlowRuleOperands = []string{"value", "source", "mapping", "properties", "items"}
+
+func eventFlowDefinitionSchema() flowDefinitionSchema {
+	return flowDefinitionSchema{
+		node:   resourceEventFlowNodeNoBranches().Schema,
+		branch: resourceEventFlowBranch(0).Schema,
+	}
+}
+
+// parseEventFlowDefinition reads a definition into the shape of a root_node
+// block and checks that it describes a consistent graph.
+func parseEventFlowDefinition(definition string) (map[string]any, error) {
+	node, err := parseFlowDefinition(definition, eventFlowDefinitionSchema())
+	if err != nil {
+		return nil, err
+	}
+	if errs := validateEventFlowGraph(node); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return node, nil
+}
+
+// validateEventFlowGraph checks the parts of an event flow the schema cannot
+// express and returns one error per problem, each prefixed with the path of
+// the attribute at fault.
+func validateEventFlowGraph(root map[string]any) []error {
+	errs := make([]error, 0)
+	if nodeType, _ := root["node_type"].(string); nodeType != ilert.EventFlowNodeType.Root {
+		errs = append(errs, fmt.Errorf("root_node.node_type: the root node must be of type %s, got %q", ilert.EventFlowNodeType.Root, nodeType))
+	}
+	return validateEventFlowNode(root, "root_node", errs)
+}
+
+func validateEventFlowNode(node map[string]any, path string, errs []error) []error {
+	nodeType, _ := node["node_type"].(string)
+	if nodeType == ilert.EventFlowNodeType.Root && path != "root_node" {
+		errs = append(errs, fmt.Errorf("%s.node_type: only the root node can be of type %s", path, nodeType))
+	}
+
+	md := map[string]any{}
+	if mdL, ok := node["metadata"].([]any); ok && len(mdL) > 0 {
+		if m, ok := mdL[0].(map[string]any); ok {
+			md = m
+		}
+	}
+	mdPath := path + ".metadata.0"
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !slices.Contains(eventFlowNodeMetadataAttributes[nodeType], k) && !isEmptyFlowDefinitionValue(md[k]) {
+			errs = append(errs, fmt.Errorf("%s.%s: not used by %s nodes", mdPath, k, nodeType))
+		}
+	}
+
+	definitions := make(map[string]bool)
+	if dL, ok := md["definitions"].([]any); ok && nodeType == ilert.EventFlowNodeType.DefineBranches {
+		for i, it := range dL {
+			dm, _ := it.(map[string]any)
+			name, _ := dm["branch_name"].(string)
+			if definitions[name] {
+				errs = append(errs, fmt.Errorf("%s.definitions.%d.branch_name: duplicate branch name %q", mdPath, i, name))
+				continue
+			}
+			definitions[name] = true
+		}
+	}
+	if rL, ok := md["rules"].([]any); ok && nodeType == ilert.EventFlowNodeType.Transform {
+		for i, it := range rL {
+			if rm, ok := it.(map[string]any); ok {
+				errs = validateEventFlowRule(rm, fmt.Sprintf("%s.rules.%d", mdPath, i), errs)
+			}
+		}
+	}
+
+	bL, _ := node["branches"].([]any)
+	seen := make(map[string]string)
+	matched := make(map[string]bool)
+	for i, it := range bL {
+		branch, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		branchPath := fmt.Sprintf("%s.branches.%d", path, i)
+		branchType, _ := branch["branch_type"].(string)
+		condition, _ := branch["condition"].(string)
+
+		key := branchType + "\x00" + condition
+		if branchType == ilert.EventFlowBranchType.CatchAll {
+			key = branchType
+		}
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicates %s, so its target is never reached", branchPath, first))
+			continue
+		}
+		seen[key] = branchPath
+
+		if nodeType == ilert.EventFlowNodeType.DefineBranches && branchType == ilert.EventFlowBranchType.Branch {
+			if !definitions[condition] {
+				errs = append(errs, fmt.Errorf("%s.condition: no definition in %s.definitions has the branch name %q, so its target is never reached", branchPath, mdPath, condition))
+			}
+			matched[condition] = true
+		}
+
+		if tL, ok := branch["target"].([]any); ok && len(tL) > 0 {
+			if target, ok := tL[0].(map[string]any); ok {
+				errs = validateEventFlowNode(target, branchPath+".target", errs)
+			}
+		}
+	}
+
+	if dL, ok := md["definitions"].([]any); ok && nodeType == ilert.EventFlowNodeType.DefineBranches {
+		for i, it := range dL {
+			dm, _ := it.(map[string]any)
+			if name, _ := dm["branch_name"].(string); !matched[name] {
+				errs = append(errs, fmt.Errorf("%s.definitions.%d.branch_name: no %s branch of %s has the condition %q", mdPath, i, ilert.EventFlowBranchType.Branch, path, name))
+			}
+		}
+	}
+	return errs
+}
+
+func validateEventFlowRule(rule map[string]any, path string, errs []error) []error {
+	operator, _ := rule["operator"].(string)
+	required, ok := eventFlowRuleAttributes[operator]
+	if !ok {
+		return errs
+	}
+	for _, k := range eventFlowRuleOperands {
+		set := !isEmptyFlowDefinitionValue(rule[k])
+		switch {
+		case slices.Contains(required, k) && !set:
+			errs = append(errs, fmt.Errorf("%s.%s: required by operator %s", path, k, operator))
+		case !slices.Contains(required, k) && set:
+			errs = append(errs, fmt.Errorf("%s.%s: not used by operator %s", path, k, operator))
+		}
+	}
+	return errs
+}
+
+func isEmptyFlowDefinitionValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case map[string]any:
+		return len(t) == 0
+	case []any:
+		return len(t) == 0
+	}
+	return false
+}
+
+// renderEventFlowDefinition renders a root_node tree as an event flow
+// definition in its canonical form.
+func renderEventFlowDefinition(node map[string]any) (string, error) {
+	return renderFlowDefinition(node, eventFlowDefinitionSchema())
+}
+
+// canonicalEventFlowDefinition parses a definition and renders it back in its
+// canonical form. The graph is not checked, so definitions the API answers
+// with still compare.
+func canonicalEventFlowDefinition(definition string) (string, error) {
+	node, err := parseFlowDefinition(definition, eventFlowDefinitionSchema())
+	if err != nil {
+		return "", err
+	}
+	return renderEventFlowDefinition(node)
+}
+
+func validateEventFlowDefinition(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, []error{fmt.Errorf("expected %s to be a JSON or YAML document, got an empty string", k)}
+	}
+	node, err := parseFlowDefinition(value, eventFlowDefinitionSchema())
+	if err != nil {
+		return nil, []error{fmt.Errorf("invalid %s: %s", k, err.Error())}
+	}
+	errs := validateEventFlowGraph(node)
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("invalid %s: %s", k, err.Error())
+	}
+	return nil, errs
+}
+
+// suppressEquivalentEventFlowDefinition hides the diff between definitions
+// that only differ in format, key order or ids.
+func suppressEquivalentEventFlowDefinition(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	o, err := canonicalEventFlowDefinition(old)
+	if err != nil {
+		return false
+	}
+	n, err := canonicalEventFlowDefinition(new)
+	if err != nil {
+		return false
+	}
+	return o == n
+}
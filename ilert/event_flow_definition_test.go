@@ -0,0 +1,174 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+const testEventFlowDefinitionYAML = `
+node_type: ROOT
+branches:
+  - branch_type: ACCEPTED
+    target:
+      node_type: DEFINE_BRANCHES
+      name: By priority
+      metadata:
+        definitions:
+          - branch_name: high
+            conditions: event.priority == "HIGH"
+          - branch_name: low
+            conditions: event.priority == "LOW"
+      branches:
+        - branch_type: BRANCH
+          condition: high
+          target:
+            node_type: ROUTE_EVENT
+            metadata:
+              alert_source_id: 1
+              escalation_policy_id: 2
+        - branch_type: BRANCH
+          condition: low
+          target:
+            node_type: TRANSFORM
+            metadata:
+              condition: event != null
+              rules:
+                - name: Summary
+                  target: context.event.summary
+                  operator: SET
+                  value: low priority event
+`
+
+func TestValidateEventFlowDefinition_AcceptsConsistentGraph(t *testing.T) {
+	if _, errs := validateEventFlowDefinition(testEventFlowDefinitionYAML, "definition"); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateEventFlowDefinition_ReportsNodePaths(t *testing.T) {
+	cases := map[string]struct {
+		old, new string
+		wantErr  string
+	}{
+		"definition without branch": {
+			"condition: low", "condition: medium",
+			`root_node.branches.0.target.metadata.0.definitions.1.branch_name: no BRANCH branch of root_node.branches.0.target has the condition "low"`,
+		},
+		"branch without definition": {
+			"condition: low", "condition: medium",
+			`root_node.branches.0.target.branches.1.condition: no definition in root_node.branches.0.target.metadata.0.definitions has the branch name "medium"`,
+		},
+		"duplicate branch": {
+			"condition: low", "condition: high",
+			"root_node.branches.0.target.branches.1: duplicates root_node.branches.0.target.branches.0",
+		},
+		"reference in wrong node type": {
+			"alert_source_id: 1", "support_hours_id: 1",
+			"root_node.branches.0.target.branches.0.target.metadata.0.support_hours_id: not used by ROUTE_EVENT nodes",
+		},
+		"operator without value": {
+			"value: low priority event", "source: context.event.details",
+			"root_node.branches.0.target.branches.1.target.metadata.0.rules.0.value: required by operator SET",
+		},
+		"operator with unused value": {
+			"operator: SET", "operator: COPY",
+			"root_node.branches.0.target.branches.1.target.metadata.0.rules.0.value: not used by operator COPY",
+		},
+		"nested root": {
+			"node_type: TRANSFORM", "node_type: ROOT",
+			"root_node.branches.0.target.branches.1.target.node_type: only the root node can be of type ROOT",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			definition := strings.Replace(testEventFlowDefinitionYAML, tc.old, tc.new, 1)
+			_, errs := validateEventFlowDefinition(definition, "definition")
+			for _, err := range errs {
+				if strings.Contains(err.Error(), tc.wantErr) {
+					return
+				}
+			}
+			t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, errs)
+		})
+	}
+}
+
+func TestBuildEventFlow_FromDefinition(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceEventFlow().Schema, map[string]any{
+		"name":       "test-event-flow",
+		"definition": testEventFlowDefinitionYAML,
+	})
+
+	eventFlow, err := buildEventFlow(d)
+	if err != nil {
+		t.Fatalf("unexpected error building event flow: %v", err)
+	}
+	if eventFlow.RootNode == nil || len(eventFlow.RootNode.Branches) != 1 {
+		t.Fatalf("expected a root node with one branch, got %+v", eventFlow.RootNode)
+	}
+	branches := eventFlow.RootNode.Branches[0].Target.Branches
+	if len(branches) != 2 || branches[0].Condition != "high" {
+		t.Fatalf("expected two defined branches, got %+v", branches)
+	}
+	md, ok := branches[0].Target.Metadata.(*ilert.EventFlowNodeMetadata)
+	if !ok {
+		t.Fatalf("expected *ilert.EventFlowNodeMetadata, got %T", branches[0].Target.Metadata)
+	}
+	if md.AlertSourceID == nil || *md.AlertSourceID != 1 || md.EscalationPolicyID == nil || *md.EscalationPolicyID != 2 {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+func TestBuildEventFlow_DefinitionRejectsInconsistentGraph(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceEventFlow().Schema, map[string]any{
+		"name":       "test-event-flow",
+		"definition": strings.Replace(testEventFlowDefinitionYAML, "condition: low", "condition: medium", 1),
+	})
+
+	if _, err := buildEventFlow(d); err == nil || !strings.Contains(err.Error(), "root_node.branches.0.target") {
+		t.Fatalf("expected a graph validation error, got: %v", err)
+	}
+}
+
+func TestRenderEventFlowDefinition_MatchesFlattenedState(t *testing.T) {
+	// The shape flattenEventFlowNodeOutput stores for root_node.
+	rootNode := map[string]any{
+		"id":        1,
+		"node_type": "ROOT",
+		"metadata":  []any{},
+		"branches": []any{
+			map[string]any{
+				"id":          10,
+				"branch_type": "ACCEPTED",
+				"target": []any{
+					map[string]any{
+						"id":        2,
+						"node_type": "PLAIN",
+						"metadata": []any{
+							map[string]any{"var_key": "context.event.summary", "var_value": "hello"},
+						},
+					},
+				},
+			},
+		},
+	}
+	definition := `{"node_type": "ROOT", "branches": [{"branch_type": "ACCEPTED", "target": {"node_type": "PLAIN", "metadata": {"var_key": "context.event.summary", "var_value": "hello"}}}]}`
+
+	got, err := renderEventFlowDefinition(rootNode)
+	if err != nil {
+		t.Fatalf("unexpected error rendering definition: %v", err)
+	}
+	want, err := canonicalEventFlowDefinition(definition)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected the rendered state to match the configured definition, got\n%s\nwant\n%s", got, want)
+	}
+	if !suppressEquivalentEventFlowDefinition("definition", definition, got, nil) {
+		t.Fatalf("expected the diff between equivalent definitions to be suppressed")
+	}
+}
@@ -0,0 +1,454 @@
+package ilert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+// supportHourLookup returns the support hours with an id, for the nodes that
+// branch or wait on them.
+type supportHourLookup func(id int64) (*ilert.SupportHour, error)
+
+// eventFlowStep is a node the simulated event passed, with the branch it
+// left the node by.
+type eventFlowStep struct {
+	Path       string
+	Name       string
+	NodeType   string
+	BranchType string
+	Condition  string
+	At         time.Time
+}
+
+type eventFlowSimulationResult struct {
+	Steps              []eventFlowStep
+	Routed             bool
+	AlertSourceID      int64
+	EscalationPolicyID int64
+	Priority           string
+	Event              map[string]any
+	At                 time.Time
+}
+
+// supportHoursWaitLimit bounds how far a WAIT node looks ahead for the start
+// or the end of support hours.
+const supportHoursWaitLimit = 14 * 24 * time.Hour
+
+var eventFlowTemplatePlaceholder = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// simulateEventFlow walks an event through a root_node tree the way ilert
+// would at the given time: transform rules and variables change the event,
+// conditions, branch definitions and support hours pick the branch to follow,
+// and waits move the clock on. The walk ends at a ROUTE_EVENT node or at a
+// node without a branch to follow.
+func simulateEventFlow(root map[string]any, event map[string]any, at time.Time, supportHours supportHourLookup) (*eventFlowSimulationResult, error) {
+	env := map[string]any{"context": map[string]any{"event": event}}
+	result := &eventFlowSimulationResult{Steps: make([]eventFlowStep, 0)}
+
+	node, path := root, "root_node"
+	for node != nil {
+		nodeType, _ := node["node_type"].(string)
+		name, _ := node["name"].(string)
+		md := eventFlowNodeMetadataMap(node)
+		step := eventFlowStep{Path: path, Name: name, NodeType: nodeType, At: at}
+
+		var err error
+		var branch map[string]any
+		var branchIndex int
+		switch nodeType {
+		case ilert.EventFlowNodeType.Plain:
+			if key, _ := md["var_key"].(string); key != "" {
+				if err := setEventFlowPath(env, key, md["var_value"]); err != nil {
+					return nil, fmt.Errorf("%s: %s", path, err.Error())
+				}
+			}
+			branch, branchIndex, err = nextEventFlowBranch(node, env)
+		case ilert.EventFlowNodeType.Transform:
+			err = applyEventFlowTransform(md, env)
+			if err == nil {
+				branch, branchIndex, err = nextEventFlowBranch(node, env)
+			}
+		case ilert.EventFlowNodeType.DefineBranches:
+			branch, branchIndex, err = definedEventFlowBranch(node, md, env)
+		case ilert.EventFlowNodeType.SupportHours:
+			var inside bool
+			inside, err = eventFlowSupportHourStatus(md["support_hours_id"], at, supportHours)
+			if err == nil {
+				status := ilert.SupportStatus.Outside
+				if inside {
+					status = ilert.SupportStatus.During
+				}
+				branch, branchIndex = findEventFlowBranch(node, func(branchType, condition string) bool {
+					return branchType == ilert.EventFlowBranchType.Branch && strings.EqualFold(condition, status)
+				})
+			}
+			if branch == nil {
+				branch, branchIndex = catchAllEventFlowBranch(node)
+			}
+		case ilert.EventFlowNodeType.Wait:
+			at, err = waitEventFlow(md, at, supportHours)
+			if err == nil {
+				branch, branchIndex, err = nextEventFlowBranch(node, env)
+			}
+		case ilert.EventFlowNodeType.RouteEvent:
+			result.Routed = true
+			result.AlertSourceID = int64(eventFlowInt(md["alert_source_id"]))
+			result.EscalationPolicyID = int64(eventFlowInt(md["escalation_policy_id"]))
+			result.Priority, _ = md["overwrite_priority"].(string)
+			if result.Priority == "" {
+				result.Priority, _ = lookupICLPath(env, []any{"context", "event", "priority"}).(string)
+			}
+		default:
+			branch, branchIndex, err = nextEventFlowBranch(node, env)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		if branch == nil {
+			result.Steps = append(result.Steps, step)
+			break
+		}
+
+		step.BranchType, _ = branch["branch_type"].(string)
+		step.Condition, _ = branch["condition"].(string)
+		result.Steps = append(result.Steps, step)
+
+		node, path = nil, fmt.Sprintf("%s.branches.%d.target", path, branchIndex)
+		if tL, ok := branch["target"].([]any); ok && len(tL) > 0 {
+			node, _ = tL[0].(map[string]any)
+		}
+	}
+
+	result.Event, _ = lookupICLPath(env, []any{"context", "event"}).(map[string]any)
+	result.At = at
+	return result, nil
+}
+
+func eventFlowNodeMetadataMap(node map[string]any) map[string]any {
+	if mdL, ok := node["metadata"].([]any); ok && len(mdL) > 0 {
+		if md, ok := mdL[0].(map[string]any); ok {
+			return md
+		}
+	}
+	return map[string]any{}
+}
+
+func findEventFlowBranch(node map[string]any, match func(branchType, condition string) bool) (map[string]any, int) {
+	bL, _ := node["branches"].([]any)
+	for i, it := range bL {
+		branch, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		branchType, _ := branch["branch_type"].(string)
+		condition, _ := branch["condition"].(string)
+		if match(branchType, condition) {
+			return branch, i
+		}
+	}
+	return nil, 0
+}
+
+// nextEventFlowBranch picks the branch a node without its own branching logic
+// leaves by: its ACCEPTED branch, the first BRANCH whose condition holds, or
+// else its CATCH_ALL branch.
+func nextEventFlowBranch(node map[string]any, env map[string]any) (map[string]any, int, error) {
+	if branch, i := findEventFlowBranch(node, func(branchType, _ string) bool {
+		return branchType == ilert.EventFlowBranchType.Accepted
+	}); branch != nil {
+		return branch, i, nil
+	}
+	var conditionErr error
+	branch, i := findEventFlowBranch(node, func(branchType, condition string) bool {
+		if branchType != ilert.EventFlowBranchType.Branch || conditionErr != nil {
+			return false
+		}
+		ok, err := evaluateICLCondition(condition, env)
+		conditionErr = err
+		return ok
+	})
+	if conditionErr != nil {
+		return nil, 0, fmt.Errorf("invalid branch condition: %s", conditionErr.Error())
+	}
+	if branch != nil {
+		return branch, i, nil
+	}
+	branch, i = catchAllEventFlowBranch(node)
+	return branch, i, nil
+}
+
+func catchAllEventFlowBranch(node map[string]any) (map[string]any, int) {
+	return findEventFlowBranch(node, func(branchType, _ string) bool {
+		return branchType == ilert.EventFlowBranchType.CatchAll
+	})
+}
+
+// definedEventFlowBranch picks the BRANCH named after the first definition
+// whose conditions hold, or else the CATCH_ALL branch.
+func definedEventFlowBranch(node, md map[string]any, env map[string]any) (map[string]any, int, error) {
+	dL, _ := md["definitions"].([]any)
+	for i, it := range dL {
+		definition, _ := it.(map[string]any)
+		name, _ := definition["branch_name"].(string)
+		conditions, _ := definition["conditions"].(string)
+		ok, err := evaluateICLCondition(conditions, env)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid conditions of definition %d: %s", i, err.Error())
+		}
+		if !ok {
+			continue
+		}
+		branch, index := findEventFlowBranch(node, func(branchType, condition string) bool {
+			return branchType == ilert.EventFlowBranchType.Branch && condition == name
+		})
+		return branch, index, nil
+	}
+	branch, index := catchAllEventFlowBranch(node)
+	return branch, index, nil
+}
+
+func applyEventFlowTransform(md map[string]any, env map[string]any) error {
+	condition, _ := md["condition"].(string)
+	ok, err := evaluateICLCondition(condition, env)
+	if err != nil {
+		return fmt.Errorf("invalid condition: %s", err.Error())
+	}
+	if !ok {
+		return nil
+	}
+
+	rL, _ := md["rules"].([]any)
+	for i, it := range rL {
+		rule, _ := it.(map[string]any)
+		target, _ := rule["target"].(string)
+		operator, _ := rule["operator"].(string)
+		source, _ := rule["source"].(string)
+
+		var value any
+		switch operator {
+		case ilert.EventFlowNodeRuleOperator.Set:
+			value = rule["value"]
+		case ilert.EventFlowNodeRuleOperator.Template:
+			template, _ := rule["value"].(string)
+			value = renderEventFlowTemplate(template, env)
+		case ilert.EventFlowNodeRuleOperator.Copy:
+			value = lookupEventFlowPath(env, source)
+		case ilert.EventFlowNodeRuleOperator.Map:
+			mapping, _ := rule["mapping"].(map[string]any)
+			if key, ok := lookupEventFlowPath(env, source).(string); ok {
+				value = mapping[key]
+			}
+		case ilert.EventFlowNodeRuleOperator.Merge:
+			merged := make(map[string]any)
+			if current, ok := lookupEventFlowPath(env, target).(map[string]any); ok {
+				for k, v := range current {
+					merged[k] = v
+				}
+			}
+			properties, _ := rule["properties"].(map[string]any)
+			for k, v := range properties {
+				merged[k] = v
+			}
+			value = merged
+		case ilert.EventFlowNodeRuleOperator.AppendArray:
+			current, _ := lookupEventFlowPath(env, target).([]any)
+			items, _ := rule["items"].([]any)
+			value = append(append(make([]any, 0, len(current)+len(items)), current...), items...)
+		}
+		if value == nil || value == "" {
+			value = rule["default"]
+		}
+		if value == nil {
+			continue
+		}
+		if err := setEventFlowPath(env, target, value); err != nil {
+			return fmt.Errorf("rule %d: %s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+// renderEventFlowTemplate replaces each {{ path }} placeholder with the value
+// at that path.
+func renderEventFlowTemplate(template string, env map[string]any) string {
+	return eventFlowTemplatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		path := eventFlowTemplatePlaceholder.FindStringSubmatch(placeholder)[1]
+		switch v := lookupEventFlowPath(env, path).(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		default:
+			return fmt.Sprint(v)
+		}
+	})
+}
+
+func lookupEventFlowPath(env map[string]any, path string) any {
+	expr, err := parseICL(path)
+	if err != nil {
+		return nil
+	}
+	p, ok := expr.(*iclPath)
+	if !ok {
+		return nil
+	}
+	return lookupICLPath(env, p.segments)
+}
+
+// setEventFlowPath sets the value at a path such as context.event.summary,
+// creating the objects on the way.
+func setEventFlowPath(env map[string]any, path string, value any) error {
+	expr, err := parseICL(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %s", path, err.Error())
+	}
+	p, ok := expr.(*iclPath)
+	if !ok {
+		return fmt.Errorf("invalid path %q", path)
+	}
+	current := env
+	for i, segment := range p.segments {
+		key, ok := segment.(string)
+		if !ok {
+			return fmt.Errorf("invalid path %q: list indexes cannot be set", path)
+		}
+		if i == len(p.segments)-1 {
+			current[key] = value
+			return nil
+		}
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[key] = next
+		}
+		current = next
+	}
+	return nil
+}
+
+func waitEventFlow(md map[string]any, at time.Time, supportHours supportHourLookup) (time.Time, error) {
+	if s, _ := md["wait_for_duration"].(string); s != "" {
+		duration, err := parseISODuration(s)
+		if err != nil {
+			return at, fmt.Errorf("invalid wait_for_duration %q", s)
+		}
+		at = duration.addTo(at, 1)
+	}
+	for _, wait := range []struct {
+		key    string
+		inside bool
+	}{{"wait_start_support_hours_id", true}, {"wait_end_support_hours_id", false}} {
+		if md[wait.key] == nil || eventFlowInt(md[wait.key]) == 0 {
+			continue
+		}
+		next, err := waitForSupportHourStatus(md[wait.key], wait.inside, at, supportHours)
+		if err != nil {
+			return at, err
+		}
+		at = next
+	}
+	return at, nil
+}
+
+// waitForSupportHourStatus returns the first minute from at on that is inside
+// (or outside) the support hours.
+func waitForSupportHourStatus(id any, inside bool, at time.Time, supportHours supportHourLookup) (time.Time, error) {
+	for t := at; t.Before(at.Add(supportHoursWaitLimit)); t = t.Truncate(time.Minute).Add(time.Minute) {
+		status, err := eventFlowSupportHourStatus(id, t, supportHours)
+		if err != nil {
+			return at, err
+		}
+		if status == inside {
+			return t, nil
+		}
+	}
+	return at, fmt.Errorf("support hours %d do not change within %s", eventFlowInt(id), supportHoursWaitLimit)
+}
+
+func eventFlowSupportHourStatus(id any, at time.Time, supportHours supportHourLookup) (bool, error) {
+	supportHourID := int64(eventFlowInt(id))
+	if supportHourID == 0 {
+		return false, fmt.Errorf("no support hours are set")
+	}
+	if supportHours == nil {
+		return false, fmt.Errorf("support hours %d are not known", supportHourID)
+	}
+	supportHour, err := supportHours(supportHourID)
+	if err != nil {
+		return false, err
+	}
+	return supportHourStatus(supportHour, at)
+}
+
+// supportHourStatus reports whether a time is within support hours. An
+// exception covering the time takes precedence over the support days; a day
+// whose end is not after its start runs past midnight.
+func supportHourStatus(supportHour *ilert.SupportHour, at time.Time) (bool, error) {
+	loc, err := time.LoadLocation(supportHour.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("support hours timezone %q is not a known IANA time zone", supportHour.Timezone)
+	}
+	at = at.In(loc)
+
+	for _, exception := range supportHour.Exceptions {
+		start, err := parseScheduleDateTime(exception.Start, loc)
+		if err != nil {
+			return false, fmt.Errorf("support hours exception start %q is not a date time", exception.Start)
+		}
+		end, err := parseScheduleDateTime(exception.End, loc)
+		if err != nil {
+			return false, fmt.Errorf("support hours exception end %q is not a date time", exception.End)
+		}
+		if !at.Before(start) && at.Before(end) {
+			return exception.SupportStatus != ilert.SupportStatus.Outside, nil
+		}
+	}
+
+	if supportHour.SupportDays == nil {
+		return false, nil
+	}
+	days := supportHour.SupportDays
+	byWeekday := map[time.Weekday]*ilert.SupportDay{
+		time.Monday:    days.MONDAY,
+		time.Tuesday:   days.TUESDAY,
+		time.Wednesday: days.WEDNESDAY,
+		time.Thursday:  days.THURSDAY,
+		time.Friday:    days.FRIDAY,
+		time.Saturday:  days.SATURDAY,
+		time.Sunday:    days.SUNDAY,
+	}
+	now := at.Format("15:04")
+	if day := byWeekday[at.Weekday()]; day != nil {
+		start, end := normalizeTimeOfDay(day.Start), normalizeTimeOfDay(day.End)
+		if start < end && now >= start && now < end {
+			return true, nil
+		}
+		if start >= end && now >= start {
+			return true, nil
+		}
+	}
+	if day := byWeekday[at.AddDate(0, 0, -1).Weekday()]; day != nil {
+		start, end := normalizeTimeOfDay(day.Start), normalizeTimeOfDay(day.End)
+		if start >= end && now < end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func eventFlowInt(v any) int {
+	switch t := v.(type) {
+	case int:
+		return t
+	case int64:
+		return int(t)
+	case float64:
+		return int(t)
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package ilert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+const testEventFlowSimulationYAML = `
+node_type: ROOT
+branches:
+  - branch_type: ACCEPTED
+    target:
+      node_type: TRANSFORM
+      metadata:
+        condition: context.event.labels.env == "prod"
+        rules:
+          - name: Tag
+            target: context.event.summary
+            operator: TEMPLATE
+            value: "[{{ context.event.labels.env }}] {{ context.event.summary }}"
+          - name: Severity
+            target: context.event.priority
+            operator: MAP
+            source: context.event.severity
+            mapping:
+              critical: HIGH
+            default: LOW
+      branches:
+        - branch_type: ACCEPTED
+          target:
+            node_type: DEFINE_BRANCHES
+            metadata:
+              definitions:
+                - branch_name: high
+                  conditions: context.event.priority == "HIGH"
+            branches:
+              - branch_type: BRANCH
+                condition: high
+                target:
+                  node_type: SUPPORT_HOURS
+                  metadata:
+                    support_hours_id: 5
+                  branches:
+                    - branch_type: BRANCH
+                      condition: DURING
+                      target:
+                        node_type: ROUTE_EVENT
+                        metadata:
+                          alert_source_id: 1
+                          escalation_policy_id: 10
+                    - branch_type: BRANCH
+                      condition: OUTSIDE
+                      target:
+                        node_type: WAIT
+                        metadata:
+                          wait_for_duration: PT15M
+                        branches:
+                          - branch_type: ACCEPTED
+                            target:
+                              node_type: ROUTE_EVENT
+                              metadata:
+                                alert_source_id: 1
+                                escalation_policy_id: 20
+                                overwrite_priority: LOW
+              - branch_type: CATCH_ALL
+                target:
+                  node_type: PLAIN
+                  metadata:
+                    var_key: context.event.dropped
+                    var_value: "true"
+`
+
+func testEventFlowSupportHours(id int64) (*ilert.SupportHour, error) {
+	day := &ilert.SupportDay{Start: "08:00", End: "17:00"}
+	return &ilert.SupportHour{
+		Timezone: "Europe/Berlin",
+		SupportDays: &ilert.SupportDays{
+			MONDAY: day, TUESDAY: day, WEDNESDAY: day, THURSDAY: day, FRIDAY: day,
+		},
+		Exceptions: []ilert.SupportHourException{
+			{Start: "2026-12-24T00:00", End: "2026-12-27T00:00", SupportStatus: ilert.SupportStatus.Outside},
+		},
+	}, nil
+}
+
+func simulateTestEventFlow(t *testing.T, event map[string]any, at time.Time) *eventFlowSimulationResult {
+	t.Helper()
+	root, err := parseEventFlowDefinition(testEventFlowSimulationYAML)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+	result, err := simulateEventFlow(root, event, at, testEventFlowSupportHours)
+	if err != nil {
+		t.Fatalf("unexpected error simulating event flow: %v", err)
+	}
+	return result
+}
+
+func TestSimulateEventFlow_RoutesDuringSupportHours(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	at := time.Date(2026, 1, 6, 10, 0, 0, 0, loc)
+	result := simulateTestEventFlow(t, map[string]any{
+		"summary":  "Disk full",
+		"severity": "critical",
+		"labels":   map[string]any{"env": "prod"},
+	}, at)
+
+	if !result.Routed || result.EscalationPolicyID != 10 || result.AlertSourceID != 1 || result.Priority != "HIGH" {
+		t.Fatalf("unexpected routing %+v", result)
+	}
+	if got := result.Event["summary"]; got != "[prod] Disk full" {
+		t.Fatalf("expected the summary template to be rendered, got %v", got)
+	}
+	var nodeTypes []string
+	for _, step := range result.Steps {
+		nodeTypes = append(nodeTypes, step.NodeType)
+	}
+	want := []string{"ROOT", "TRANSFORM", "DEFINE_BRANCHES", "SUPPORT_HOURS", "ROUTE_EVENT"}
+	if !reflect.DeepEqual(nodeTypes, want) {
+		t.Fatalf("expected path %v, got %v", want, nodeTypes)
+	}
+	if last := result.Steps[len(result.Steps)-1].Path; last != "root_node.branches.0.target.branches.0.target.branches.0.target.branches.0.target" {
+		t.Fatalf("unexpected node path %q", last)
+	}
+}
+
+func TestSimulateEventFlow_WaitsOutsideSupportHours(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	at := time.Date(2026, 12, 24, 10, 0, 0, 0, loc)
+	result := simulateTestEventFlow(t, map[string]any{
+		"severity": "critical",
+		"labels":   map[string]any{"env": "prod"},
+	}, at)
+
+	if !result.Routed || result.EscalationPolicyID != 20 || result.Priority != "LOW" {
+		t.Fatalf("unexpected routing %+v", result)
+	}
+	if want := at.Add(15 * time.Minute); !result.At.Equal(want) {
+		t.Fatalf("expected the wait to end at %s, got %s", want, result.At)
+	}
+}
+
+func TestSimulateEventFlow_CatchAllWithoutRouting(t *testing.T) {
+	result := simulateTestEventFlow(t, map[string]any{
+		"severity": "warning",
+		"labels":   map[string]any{"env": "prod"},
+	}, time.Date(2026, 1, 6, 10, 0, 0, 0, time.UTC))
+
+	if result.Routed {
+		t.Fatalf("expected the event not to be routed, got %+v", result)
+	}
+	if result.Event["priority"] != "LOW" || result.Event["dropped"] != "true" {
+		t.Fatalf("expected the default priority and the variable to be set, got %v", result.Event)
+	}
+	if last := result.Steps[len(result.Steps)-1]; last.NodeType != "PLAIN" {
+		t.Fatalf("expected the walk to end at the PLAIN node, got %+v", last)
+	}
+}
+
+func TestSimulateEventFlow_UnknownSupportHours(t *testing.T) {
+	root, err := parseEventFlowDefinition(testEventFlowSimulationYAML)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+	event := map[string]any{"severity": "critical", "labels": map[string]any{"env": "prod"}}
+	_, err = simulateEventFlow(root, event, time.Now(), nil)
+	if err == nil || !strings.Contains(err.Error(), "support hours 5 are not known") {
+		t.Fatalf("expected an error about the unknown support hours, got: %v", err)
+	}
+}
+
+func TestSupportHourStatus_OvernightDays(t *testing.T) {
+	supportHour := &ilert.SupportHour{
+		Timezone:    "UTC",
+		SupportDays: &ilert.SupportDays{FRIDAY: &ilert.SupportDay{Start: "22:00", End: "06:00"}},
+	}
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 1, 9, 21, 59, 0, 0, time.UTC), false},
+		{time.Date(2026, 1, 9, 22, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 1, 10, 5, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 1, 10, 6, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		got, err := supportHourStatus(supportHour, tc.at)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("supportHourStatus at %s = %t, want %t", tc.at, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package ilert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// A flow definition is the root_node tree of a call flow or an event flow
+// written as a JSON or YAML document, with the same attribute names as the
+// blocks. Blocks that hold at most one item (metadata, target, enrichment) are
+// plain objects instead of single item lists, and the ids the API assigns are
+// left out.
+
+// flowDefinitionSchema holds the node and branch blocks a definition is
+// checked against.
+type flowDefinitionSchema struct {
+	node   map[string]*schema.Schema
+	branch map[string]*schema.Schema
+}
+
+// parseFlowDefinition reads a definition into the shape of a root_node block,
+// validating it against the node schema on the way.
+func parseFlowDefinition(definition string, s flowDefinitionSchema) (map[string]any, error) {
+	var raw any
+	if err := json.Unmarshal([]byte(definition), &raw); err != nil {
+		if err := yaml.Unmarshal([]byte(definition), &raw); err != nil {
+			return nil, fmt.Errorf("definition is neither valid JSON nor YAML: %s", err.Error())
+		}
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("definition is empty")
+	}
+	return normalizeFlowDefinitionNode(raw, s, "root_node")
+}
+
+func normalizeFlowDefinitionNode(v any, s flowDefinitionSchema, path string) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an object, got %T", path, v)
+	}
+	branches, hasBranches := m["branches"]
+	delete(m, "branches")
+
+	node, err := normalizeFlowDefinitionObject(m, s.node, path)
+	if err != nil {
+		return nil, err
+	}
+	if !hasBranches || branches == nil {
+		return node, nil
+	}
+
+	bL, ok := branches.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s.branches: expected a list, got %T", path, branches)
+	}
+	result := make([]any, 0, len(bL))
+	for i, b := range bL {
+		branch, err := normalizeFlowDefinitionBranch(b, s, fmt.Sprintf("%s.branches.%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, branch)
+	}
+	node["branches"] = result
+	return node, nil
+}
+
+func normalizeFlowDefinitionBranch(v any, s flowDefinitionSchema, path string) (map[string]any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an object, got %T", path, v)
+	}
+	target, hasTarget := m["target"]
+	delete(m, "target")
+
+	branch, err := normalizeFlowDefinitionObject(m, s.branch, path)
+	if err != nil {
+		return nil, err
+	}
+	if !hasTarget || target == nil {
+		return branch, nil
+	}
+	if tL, ok := target.([]any); ok && len(tL) == 1 {
+		target = tL[0]
+	}
+	node, err := normalizeFlowDefinitionNode(target, s, path+".target")
+	if err != nil {
+		return nil, err
+	}
+	branch["target"] = []any{node}
+	return branch, nil
+}
+
+// normalizeFlowDefinitionObject checks an object against the schema of a
+// block: unknown attributes are rejected, required ones must be present, and
+// computed ids are dropped.
+func normalizeFlowDefinitionObject(m map[string]any, s map[string]*schema.Schema, path string) (map[string]any, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]any)
+	for _, k := range keys {
+		attr, ok := s[k]
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported attribute %q", path, k)
+		}
+		if !attr.Optional && !attr.Required {
+			continue
+		}
+		v, err := normalizeFlowDefinitionValue(m[k], attr, path+"."+k)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			result[k] = v
+		}
+	}
+	for k, attr := range s {
+		if _, ok := result[k]; attr.Required && !ok {
+			return nil, fmt.Errorf("%s: missing required attribute %q", path, k)
+		}
+	}
+	return result, nil
+}
+
+func normalizeFlowDefinitionValue(v any, s *schema.Schema, path string) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var result any
+	switch s.Type {
+	case schema.TypeString:
+		switch t := v.(type) {
+		case string:
+			result = t
+		case int:
+			result = strconv.Itoa(t)
+		case float64:
+			result = strconv.FormatFloat(t, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%s: expected a string, got %T", path, v)
+		}
+	case schema.TypeInt:
+		switch t := v.(type) {
+		case int:
+			result = t
+		case float64:
+			if t != math.Trunc(t) {
+				return nil, fmt.Errorf("%s: expected an integer, got %v", path, t)
+			}
+			result = int(t)
+		default:
+			return nil, fmt.Errorf("%s: expected an integer, got %T", path, v)
+		}
+	case schema.TypeBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a boolean, got %T", path, v)
+		}
+		result = b
+	case schema.TypeMap:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object, got %T", path, v)
+		}
+		elem, _ := s.Elem.(*schema.Schema)
+		values := make(map[string]any, len(m))
+		for k, item := range m {
+			if elem == nil {
+				values[k] = item
+				continue
+			}
+			nv, err := normalizeFlowDefinitionValue(item, elem, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			values[k] = nv
+		}
+		result = values
+	case schema.TypeList, schema.TypeSet:
+		items, ok := v.([]any)
+		if !ok {
+			if _, isObject := v.(map[string]any); isObject && s.MaxItems == 1 {
+				items = []any{v}
+			} else {
+				return nil, fmt.Errorf("%s: expected a list, got %T", path, v)
+			}
+		}
+		if s.MaxItems > 0 && len(items) > s.MaxItems {
+			return nil, fmt.Errorf("%s: expected at most %d items, got %d", path, s.MaxItems, len(items))
+		}
+		values := make([]any, 0, len(items))
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s.%d", path, i)
+			var nv any
+			var err error
+			switch elem := s.Elem.(type) {
+			case *schema.Resource:
+				m, ok := item.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("%s: expected an object, got %T", itemPath, item)
+				}
+				nv, err = normalizeFlowDefinitionObject(m, elem.Schema, itemPath)
+			case *schema.Schema:
+				nv, err = normalizeFlowDefinitionValue(item, elem, itemPath)
+			}
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, nv)
+		}
+		result = values
+	default:
+		return nil, fmt.Errorf("%s: unsupported attribute type %s", path, s.Type)
+	}
+
+	if s.ValidateFunc != nil {
+		if _, errs := s.ValidateFunc(result, path); len(errs) > 0 {
+			return nil, errs[0]
+		}
+	}
+	return result, nil
+}
+
+// renderFlowDefinition renders a root_node tree as a definition in its
+// canonical form: JSON with sorted keys, without ids and without empty values,
+// so two definitions are equal exactly when they describe the same flow.
+func renderFlowDefinition(node map[string]any, s flowDefinitionSchema) (string, error) {
+	b, err := json.Marshal(compactFlowDefinitionNode(node, s))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func compactFlowDefinitionNode(node map[string]any, s flowDefinitionSchema) map[string]any {
+	result := compactFlowDefinitionObject(node, s.node)
+	if bL, ok := node["branches"].([]any); ok && len(bL) > 0 {
+		branches := make([]any, 0, len(bL))
+		for _, b := range bL {
+			bm, ok := b.(map[string]any)
+			if !ok {
+				continue
+			}
+			branch := compactFlowDefinitionObject(bm, s.branch)
+			delete(branch, "target")
+			if tL, ok := bm["target"].([]any); ok && len(tL) > 0 {
+				if tm, ok := tL[0].(map[string]any); ok {
+					branch["target"] = compactFlowDefinitionNode(tm, s)
+				}
+			}
+			branches = append(branches, branch)
+		}
+		result["branches"] = branches
+	}
+	return result
+}
+
+func compactFlowDefinitionObject(m map[string]any, s map[string]*schema.Schema) map[string]any {
+	result := make(map[string]any)
+	for k, attr := range s {
+		if !attr.Optional && !attr.Required {
+			continue
+		}
+		if v := compactFlowDefinitionValue(m[k], attr); v != nil {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func compactFlowDefinitionValue(v any, s *schema.Schema) any {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if t == "" {
+			return nil
+		}
+	case int:
+		if t == 0 {
+			return nil
+		}
+	case bool:
+		if !t {
+			return nil
+		}
+	case map[string]any:
+		if len(t) == 0 {
+			return nil
+		}
+		if s.Type == schema.TypeList || s.Type == schema.TypeSet {
+			// Lists flattened keyed by id, such as call flow enrichment sources;
+			// order them by key.
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			items := make([]any, 0, len(t))
+			for _, k := range keys {
+				items = append(items, t[k])
+			}
+			return compactFlowDefinitionValue(items, s)
+		}
+	case []any:
+		if len(t) == 0 {
+			return nil
+		}
+		elem, isResource := s.Elem.(*schema.Resource)
+		if !isResource {
+			return t
+		}
+		items := make([]any, 0, len(t))
+		for _, item := range t {
+			if m, ok := item.(map[string]any); ok {
+				items = append(items, compactFlowDefinitionObject(m, elem.Schema))
+			}
+		}
+		if s.MaxItems == 1 && len(items) == 1 {
+			return items[0]
+		}
+		return items
+	}
+	return v
+}
@@ -0,0 +1,200 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// flowDiagramLabelWidth bounds a line of metadata in a node label, so long
+// messages or conditions do not blow up the diagram.
+const flowDiagramLabelWidth = 60
+
+type flowDiagramNode struct {
+	id    string
+	lines []string
+}
+
+type flowDiagramEdge struct {
+	from, to string
+	label    string
+}
+
+// flowDiagramGraph lays out a root_node tree as nodes labelled with their type,
+// name and a summary of their metadata, and edges labelled with the branch
+// type and condition. Nodes are numbered depth first, so a diagram only
+// changes where the flow does.
+func flowDiagramGraph(root map[string]any, s flowDefinitionSchema) ([]flowDiagramNode, []flowDiagramEdge) {
+	nodes := make([]flowDiagramNode, 0)
+	edges := make([]flowDiagramEdge, 0)
+
+	var walk func(node map[string]any) string
+	walk = func(node map[string]any) string {
+		id := fmt.Sprintf("n%d", len(nodes))
+		compact := compactFlowDefinitionNode(node, s)
+
+		title, _ := compact["node_type"].(string)
+		if name, _ := compact["name"].(string); name != "" {
+			title += ": " + name
+		}
+		lines := []string{title}
+		if md, ok := compact["metadata"].(map[string]any); ok {
+			keys := make([]string, 0, len(md))
+			for k := range md {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				lines = append(lines, truncateFlowDiagramLine(k+": "+summarizeFlowDiagramValue(md[k])))
+			}
+		}
+		nodes = append(nodes, flowDiagramNode{id: id, lines: lines})
+
+		bL, _ := node["branches"].([]any)
+		for _, it := range bL {
+			branch, ok := it.(map[string]any)
+			if !ok {
+				continue
+			}
+			label, _ := branch["branch_type"].(string)
+			if condition, _ := branch["condition"].(string); condition != "" {
+				label += ": " + truncateFlowDiagramLine(condition)
+			}
+			tL, _ := branch["target"].([]any)
+			if len(tL) == 0 || tL[0] == nil {
+				continue
+			}
+			target := walk(tL[0].(map[string]any))
+			edges = append(edges, flowDiagramEdge{from: id, to: target, label: label})
+		}
+		return id
+	}
+	if root != nil {
+		walk(root)
+	}
+	return nodes, edges
+}
+
+// summarizeFlowDiagramValue writes a compacted metadata value on one line:
+// objects as their values in key order, such as "USER 1" for a call target,
+// and lists comma separated.
+func summarizeFlowDiagramValue(v any) string {
+	switch t := v.(type) {
+	case []any:
+		items := make([]string, 0, len(t))
+		for _, it := range t {
+			items = append(items, summarizeFlowDiagramValue(it))
+		}
+		return strings.Join(items, ", ")
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(t))
+		for _, k := range keys {
+			values = append(values, summarizeFlowDiagramValue(t[k]))
+		}
+		return strings.Join(values, " ")
+	}
+	return fmt.Sprint(v)
+}
+
+func truncateFlowDiagramLine(line string) string {
+	line = strings.Join(strings.Fields(line), " ")
+	if r := []rune(line); len(r) > flowDiagramLabelWidth {
+		return string(r[:flowDiagramLabelWidth-1]) + "…"
+	}
+	return line
+}
+
+// renderFlowDiagramMermaid renders a root_node tree as a Mermaid flowchart.
+func renderFlowDiagramMermaid(root map[string]any, s flowDefinitionSchema) string {
+	nodes, edges := flowDiagramGraph(root, s)
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, n := range nodes {
+		lines := make([]string, 0, len(n.lines))
+		for _, l := range n.lines {
+			lines = append(lines, escape.Replace(l))
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", n.id, strings.Join(lines, "<br/>"))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", e.from, escape.Replace(e.label), e.to)
+	}
+	return b.String()
+}
+
+// renderFlowDiagramDot renders a root_node tree as a Graphviz digraph.
+func renderFlowDiagramDot(root map[string]any, s flowDefinitionSchema, name string) string {
+	nodes, edges := flowDiagramGraph(root, s)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", name)
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range nodes {
+		lines := make([]string, 0, len(n.lines))
+		for _, l := range n.lines {
+			lines = append(lines, escape.Replace(l))
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\"];\n", n.id, strings.Join(lines, `\n`))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=\"%s\"];\n", e.from, e.to, escape.Replace(e.label))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// setFlowDiagrams stores the diagrams of the root_node tree read from the API.
+func setFlowDiagrams(d *schema.ResourceData, rootNode []any, s flowDefinitionSchema, name string) error {
+	var root map[string]any
+	if len(rootNode) > 0 && rootNode[0] != nil {
+		root = rootNode[0].(map[string]any)
+	}
+	if err := d.Set("diagram_mermaid", renderFlowDiagramMermaid(root, s)); err != nil {
+		return fmt.Errorf("[ERROR] Error setting diagram_mermaid: %s", err.Error())
+	}
+	if err := d.Set("diagram_dot", renderFlowDiagramDot(root, s, name)); err != nil {
+		return fmt.Errorf("[ERROR] Error setting diagram_dot: %s", err.Error())
+	}
+	return nil
+}
+
+// customizeFlowDiagrams renders the diagrams of the configured flow at plan
+// time, so a change to a deep tree shows up in the plan as a change to a
+// diagram a reviewer can read.
+func customizeFlowDiagrams(s flowDefinitionSchema, name string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+		if !diffValuesKnown(diff, "root_node") || !diff.NewValueKnown("definition") {
+			if err := diff.SetNewComputed("diagram_mermaid"); err != nil {
+				return err
+			}
+			return diff.SetNewComputed("diagram_dot")
+		}
+
+		var root map[string]any
+		if rL, ok := diff.Get("root_node").([]any); ok && len(rL) > 0 && rL[0] != nil {
+			root = rL[0].(map[string]any)
+		} else if definition, ok := diff.Get("definition").(string); ok && definition != "" {
+			node, err := parseFlowDefinition(definition, s)
+			if err != nil {
+				return nil
+			}
+			root = node
+		}
+
+		if err := diff.SetNew("diagram_mermaid", renderFlowDiagramMermaid(root, s)); err != nil {
+			return err
+		}
+		return diff.SetNew("diagram_dot", renderFlowDiagramDot(root, s, name))
+	}
+}
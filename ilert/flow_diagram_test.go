@@ -0,0 +1,109 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFlowDiagramMermaid_CallFlow(t *testing.T) {
+	root, err := parseCallFlowDefinition(`
+node_type: ROOT
+branches:
+  - branch_type: ANSWERED
+    target:
+      node_type: AUDIO_MESSAGE
+      metadata:
+        text_message: You reached the "platform" on-call line.
+      branches:
+        - branch_type: CATCH_ALL
+          target:
+            node_type: ROUTE_CALL
+            name: On-call engineer
+            metadata:
+              call_style: ORDERED
+              targets:
+                - type: USER
+                  target: 1
+                - type: ON_CALL_SCHEDULE
+                  target: 2
+`)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"flowchart TD",
+		`  n0["ROOT"]`,
+		`  n1["AUDIO_MESSAGE<br/>text_message: You reached the #quot;platform#quot; on-call line."]`,
+		`  n2["ROUTE_CALL: On-call engineer<br/>call_style: ORDERED<br/>targets: 1 USER, 2 ON_CALL_SCHEDULE"]`,
+		`  n1 -->|"CATCH_ALL"| n2`,
+		`  n0 -->|"ANSWERED"| n1`,
+		"",
+	}, "\n")
+	if got := renderFlowDiagramMermaid(root, callFlowDefinitionSchema()); got != want {
+		t.Fatalf("unexpected diagram, got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderFlowDiagramDot_EventFlow(t *testing.T) {
+	root, err := parseEventFlowDefinition(`{
+  "node_type": "ROOT",
+  "branches": [{
+    "branch_type": "ACCEPTED",
+    "target": {
+      "node_type": "DEFINE_BRANCHES",
+      "metadata": {"definitions": [{"branch_name": "high", "conditions": "context.event.priority == \"HIGH\""}]},
+      "branches": [{"branch_type": "BRANCH", "condition": "high", "target": {"node_type": "ROUTE_EVENT", "metadata": {"escalation_policy_id": 7}}}]
+    }
+  }]
+}`)
+	if err != nil {
+		t.Fatalf("unexpected error parsing definition: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"digraph event_flow {",
+		"  node [shape=box];",
+		`  n0 [label="ROOT"];`,
+		`  n1 [label="DEFINE_BRANCHES\ndefinitions: high context.event.priority == \"HIGH\""];`,
+		`  n2 [label="ROUTE_EVENT\nescalation_policy_id: 7"];`,
+		`  n1 -> n2 [label="BRANCH: high"];`,
+		`  n0 -> n1 [label="ACCEPTED"];`,
+		"}",
+		"",
+	}, "\n")
+	if got := renderFlowDiagramDot(root, eventFlowDefinitionSchema(), "event_flow"); got != want {
+		t.Fatalf("unexpected diagram, got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderFlowDiagram_IgnoresIDsAndTruncatesLongLines(t *testing.T) {
+	rootNode := map[string]any{
+		"id":        1,
+		"node_type": "ROOT",
+		"metadata":  []any{},
+		"branches": []any{
+			map[string]any{
+				"id":          10,
+				"branch_type": "ACCEPTED",
+				"target": []any{
+					map[string]any{
+						"id":        2,
+						"node_type": "PLAIN",
+						"metadata": []any{
+							map[string]any{"var_key": "context.event.summary", "var_value": strings.Repeat("x", 100)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := renderFlowDiagramMermaid(rootNode, eventFlowDefinitionSchema())
+	if strings.Contains(got, "id") {
+		t.Fatalf("expected ids to be left out, got\n%s", got)
+	}
+	if !strings.Contains(got, "var_value: "+strings.Repeat("x", flowDiagramLabelWidth-len("var_value: ")-1)+"…") {
+		t.Fatalf("expected the long value to be truncated, got\n%s", got)
+	}
+}
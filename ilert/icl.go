@@ -0,0 +1,693 @@
+package ilert
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ICL, the ilert condition language, is the expression language of event
+// filters, alert action conditions and event flow conditions. The parser
+// covers the part of it the provider evaluates locally: string, number,
+// boolean and null literals, lists, paths into the event such as
+// context.event.labels["env"], the comparison operators, the in, contains,
+// contains_any, contains_all, startsWith, endsWith and matches operators, and
+// !, && and || with parentheses.
+
+type iclTokenKind int
+
+const (
+	iclTokenEOF iclTokenKind = iota
+	iclTokenIdent
+	iclTokenNumber
+	iclTokenString
+	iclTokenOperator
+)
+
+type iclToken struct {
+	kind  iclTokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// iclSyntaxError is an error at a byte offset of an expression.
+type iclSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *iclSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+var iclOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+// iclWordOperators are the comparison operators written as words.
+var iclWordOperators = []string{"in", "contains", "contains_any", "contains_all", "startsWith", "endsWith", "matches"}
+
+func lexICL(src string) ([]iclToken, error) {
+	tokens := make([]iclToken, 0)
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(src) {
+				if src[i] == c {
+					closed = true
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					switch src[i+1] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(src[i+1])
+					}
+					i += 2
+					continue
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			if !closed {
+				return nil, &iclSyntaxError{Pos: start, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, iclToken{kind: iclTokenString, text: src[start:i], value: b.String(), pos: start})
+		case isICLDigit(c) || (c == '-' && i+1 < len(src) && isICLDigit(src[i+1]) && !iclPrecededByValue(tokens)):
+			start := i
+			i++
+			for i < len(src) && (isICLDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, &iclSyntaxError{Pos: start, Msg: fmt.Sprintf("invalid number %q", src[start:i])}
+			}
+			tokens = append(tokens, iclToken{kind: iclTokenNumber, text: src[start:i], value: n, pos: start})
+		case isICLIdentStart(rune(c)):
+			start := i
+			for i < len(src) && (isICLIdentStart(rune(src[i])) || isICLDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, iclToken{kind: iclTokenIdent, text: src[start:i], pos: start})
+		default:
+			op := ""
+			for _, o := range iclOperators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &iclSyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", src[i])}
+			}
+			tokens = append(tokens, iclToken{kind: iclTokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, iclToken{kind: iclTokenEOF, pos: len(src)}), nil
+}
+
+func isICLDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isICLIdentStart(r rune) bool {
+	return r == '_' || r == '$' || (r < unicode.MaxASCII && unicode.IsLetter(r))
+}
+
+// iclPrecededByValue tells a minus sign after a value, which is not part of
+// a number, from a negative number literal.
+func iclPrecededByValue(tokens []iclToken) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind != iclTokenOperator || last.text == ")" || last.text == "]"
+}
+
+type iclExpr interface {
+	position() int
+}
+
+type iclLiteral struct {
+	pos   int
+	value any
+}
+
+// iclPath is a path into the event; each segment is a string key or an int
+// list index.
+type iclPath struct {
+	pos      int
+	segments []any
+}
+
+type iclList struct {
+	pos   int
+	items []iclExpr
+}
+
+type iclNot struct {
+	pos  int
+	expr iclExpr
+}
+
+type iclBinary struct {
+	pos         int
+	op          string
+	left, right iclExpr
+}
+
+func (e *iclLiteral) position() int { return e.pos }
+func (e *iclPath) position() int    { return e.pos }
+func (e *iclList) position() int    { return e.pos }
+func (e *iclNot) position() int     { return e.pos }
+func (e *iclBinary) position() int  { return e.pos }
+
+type iclParser struct {
+	tokens []iclToken
+	i      int
+}
+
+// parseICL parses an expression, returning an *iclSyntaxError pointing at the
+// offending token when it is not valid.
+func parseICL(src string) (iclExpr, error) {
+	tokens, err := lexICL(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &iclParser{tokens: tokens}
+	if p.peek().kind == iclTokenEOF {
+		return nil, &iclSyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != iclTokenEOF {
+		return nil, &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+	}
+	return expr, nil
+}
+
+func (p *iclParser) peek() iclToken {
+	return p.tokens[p.i]
+}
+
+func (p *iclParser) next() iclToken {
+	t := p.tokens[p.i]
+	if t.kind != iclTokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *iclParser) isOperator(ops ...string) bool {
+	t := p.peek()
+	return t.kind == iclTokenOperator && slices.Contains(ops, t.text)
+}
+
+func (p *iclParser) expect(op string) (iclToken, error) {
+	t := p.next()
+	if t.kind != iclTokenOperator || t.text != op {
+		return t, p.unexpected(t, fmt.Sprintf("%q", op))
+	}
+	return t, nil
+}
+
+func (p *iclParser) unexpected(t iclToken, want string) error {
+	if t.kind == iclTokenEOF {
+		return &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %s, got the end of the expression", want)}
+	}
+	return &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected %s, got %q", want, t.text)}
+}
+
+func (p *iclParser) parseOr() (iclExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		t := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &iclBinary{pos: t.pos, op: t.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *iclParser) parseAnd() (iclExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		t := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &iclBinary{pos: t.pos, op: t.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *iclParser) parseNot() (iclExpr, error) {
+	if p.isOperator("!") {
+		t := p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &iclNot{pos: t.pos, expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *iclParser) parseComparison() (iclExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	isComparison := p.isOperator("==", "!=", "<", "<=", ">", ">=") ||
+		(t.kind == iclTokenIdent && slices.Contains(iclWordOperators, t.text))
+	if !isComparison {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &iclBinary{pos: t.pos, op: t.text, left: left, right: right}, nil
+}
+
+func (p *iclParser) parseOperand() (iclExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case iclTokenString, iclTokenNumber:
+		return &iclLiteral{pos: t.pos, value: t.value}, nil
+	case iclTokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &iclLiteral{pos: t.pos, value: t.text == "true"}, nil
+		case "null":
+			return &iclLiteral{pos: t.pos}, nil
+		}
+		if slices.Contains(iclWordOperators, t.text) {
+			return nil, p.unexpected(t, "a value")
+		}
+		return p.parsePath(t)
+	case iclTokenOperator:
+		switch t.text {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		case "[":
+			list := &iclList{pos: t.pos, items: make([]iclExpr, 0)}
+			if p.isOperator("]") {
+				p.next()
+				return list, nil
+			}
+			for {
+				item, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if p.isOperator(",") {
+					p.next()
+					continue
+				}
+				if _, err := p.expect("]"); err != nil {
+					return nil, err
+				}
+				return list, nil
+			}
+		}
+	}
+	return nil, p.unexpected(t, "a value")
+}
+
+func (p *iclParser) parsePath(first iclToken) (iclExpr, error) {
+	path := &iclPath{pos: first.pos, segments: []any{first.text}}
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			t := p.next()
+			if t.kind != iclTokenIdent {
+				return nil, p.unexpected(t, "a field name")
+			}
+			path.segments = append(path.segments, t.text)
+		case p.isOperator("["):
+			p.next()
+			t := p.next()
+			switch t.kind {
+			case iclTokenString:
+				path.segments = append(path.segments, t.value.(string))
+			case iclTokenNumber:
+				n := t.value.(float64)
+				if n < 0 || n != math.Trunc(n) {
+					return nil, &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected a list index, got %q", t.text)}
+				}
+				path.segments = append(path.segments, int(n))
+			default:
+				return nil, p.unexpected(t, "a key or index")
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+// evaluateICL evaluates an expression against an event, given as decoded
+// JSON. Paths that do not exist evaluate to null.
+func evaluateICL(expr iclExpr, env map[string]any) (any, error) {
+	switch e := expr.(type) {
+	case *iclLiteral:
+		return e.value, nil
+	case *iclPath:
+		return lookupICLPath(env, e.segments), nil
+	case *iclList:
+		items := make([]any, 0, len(e.items))
+		for _, it := range e.items {
+			v, err := evaluateICL(it, env)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case *iclNot:
+		v, err := evaluateICL(e.expr, env)
+		if err != nil {
+			return nil, err
+		}
+		return !iclTruthy(v), nil
+	case *iclBinary:
+		left, err := evaluateICL(e.left, env)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "&&":
+			if !iclTruthy(left) {
+				return false, nil
+			}
+		case "||":
+			if iclTruthy(left) {
+				return true, nil
+			}
+		}
+		right, err := evaluateICL(e.right, env)
+		if err != nil {
+			return nil, err
+		}
+		return compareICL(e.op, left, right, e.pos)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", expr)
+}
+
+func compareICL(op string, left, right any, pos int) (any, error) {
+	switch op {
+	case "&&", "||":
+		return iclTruthy(right), nil
+	case "==":
+		return iclEqual(left, right), nil
+	case "!=":
+		return !iclEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		c, ok := iclCompare(left, right)
+		if !ok {
+			return false, nil
+		}
+		switch op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		}
+		return c >= 0, nil
+	case "in":
+		return iclContains(right, left), nil
+	case "contains":
+		return iclContains(left, right), nil
+	case "contains_any", "contains_all":
+		items, ok := right.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d expects a list", op, pos+1)
+		}
+		for _, item := range items {
+			if iclContains(left, item) == (op == "contains_any") {
+				return op == "contains_any", nil
+			}
+		}
+		return op == "contains_all", nil
+	case "startsWith", "endsWith":
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return false, nil
+		}
+		if op == "startsWith" {
+			return strings.HasPrefix(l, r), nil
+		}
+		return strings.HasSuffix(l, r), nil
+	case "matches":
+		pattern, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("matches at position %d expects a regular expression string", pos+1)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at position %d: %s", pos+1, err.Error())
+		}
+		s, ok := left.(string)
+		return ok && re.MatchString(s), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q at position %d", op, pos+1)
+}
+
+// evaluateICLCondition parses and evaluates a condition, treating an empty
+// condition as true.
+func evaluateICLCondition(condition string, env map[string]any) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return true, nil
+	}
+	expr, err := parseICL(condition)
+	if err != nil {
+		return false, err
+	}
+	v, err := evaluateICL(expr, env)
+	if err != nil {
+		return false, err
+	}
+	return iclTruthy(v), nil
+}
+
+func lookupICLPath(v any, segments []any) any {
+	for _, s := range segments {
+		switch key := s.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = m[key]
+		case int:
+			l, ok := v.([]any)
+			if !ok || key >= len(l) {
+				return nil
+			}
+			v = l[key]
+		}
+	}
+	return v
+}
+
+func iclTruthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case float64:
+		return t != 0
+	case []any:
+		return len(t) > 0
+	}
+	return true
+}
+
+func iclNumber(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+func iclEqual(a, b any) bool {
+	if x, ok := iclNumber(a); ok {
+		y, ok := iclNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func iclCompare(a, b any) (int, bool) {
+	if x, ok := iclNumber(a); ok {
+		y, ok := iclNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, xok := a.(string)
+	y, yok := b.(string)
+	if !xok || !yok {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+// iclContains reports whether a list holds an item, or a string a substring.
+func iclContains(container, item any) bool {
+	switch c := container.(type) {
+	case string:
+		s, ok := item.(string)
+		return ok && strings.Contains(c, s)
+	case []any:
+		for _, it := range c {
+			if iclEqual(it, item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateICLExpression checks an expression such as an event filter at plan
+// time, pointing at the offending token, instead of the filter being rejected
+// mid-apply or silently never matching.
+func validateICLExpression(v any, path cty.Path) diag.Diagnostics {
+	value, ok := v.(string)
+	if !ok {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: "Expected a string", AttributePath: path}}
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	_, err := parseICL(value)
+	if err == nil {
+		return nil
+	}
+	detail := err.Error()
+	if syntaxErr, ok := err.(*iclSyntaxError); ok {
+		detail = fmt.Sprintf("%s:\n\n%s", detail, iclErrorContext(value, syntaxErr.Pos))
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       "Invalid ICL expression",
+		Detail:        detail,
+		AttributePath: path,
+	}}
+}
+
+// iclErrorContext quotes the line of an expression holding a byte offset,
+// with a caret under it.
+func iclErrorContext(src string, pos int) string {
+	start := strings.LastIndex(src[:pos], "\n") + 1
+	end := strings.Index(src[pos:], "\n")
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += pos
+	}
+	return fmt.Sprintf("  %s\n  %s^", src[start:end], strings.Repeat(" ", len([]rune(src[start:pos]))))
+}
+
+// suppressEquivalentICL hides the diff between expressions that only differ in
+// whitespace or quotes, e.g. a condition wrapped over several lines in a
+// heredoc and the single line the API answers with.
+func suppressEquivalentICL(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	oldTokens, err := lexICL(old)
+	if err != nil {
+		return false
+	}
+	newTokens, err := lexICL(new)
+	if err != nil || len(oldTokens) != len(newTokens) {
+		return false
+	}
+	for i := range oldTokens {
+		o, n := oldTokens[i], newTokens[i]
+		if o.kind != n.kind {
+			return false
+		}
+		switch o.kind {
+		case iclTokenString, iclTokenNumber:
+			if o.value != n.value {
+				return false
+			}
+		default:
+			if o.text != n.text {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// quoteICL writes a string as an ICL string literal.
+func quoteICL(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
@@ -0,0 +1,120 @@
+package ilert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestEvaluateICLCondition(t *testing.T) {
+	env := map[string]any{
+		"context": map[string]any{
+			"event": map[string]any{
+				"summary":  "CPU load high on db-1",
+				"priority": "HIGH",
+				"count":    float64(3),
+				"labels":   map[string]any{"env": "prod", "team-name": "platform"},
+				"tags":     []any{"db", "linux"},
+			},
+		},
+	}
+	cases := []struct {
+		condition string
+		want      bool
+	}{
+		{"", true},
+		{`context.event.priority == "HIGH"`, true},
+		{`context.event.priority != 'HIGH'`, false},
+		{`context.event.count >= 3 && context.event.count < 4`, true},
+		{`context.event.count > -1`, true},
+		{`context.event.labels.env == "staging" || context.event.labels["team-name"] == "platform"`, true},
+		{`!(context.event.summary contains "CPU")`, false},
+		{`context.event.summary startsWith "CPU" && context.event.summary endsWith "db-1"`, true},
+		{`context.event.summary matches "db-[0-9]+$"`, true},
+		{`"linux" in context.event.tags`, true},
+		{`context.event.tags contains "windows"`, false},
+		{`context.event.labels.env in ["prod", "staging"]`, true},
+		{`context.event.tags contains_any ["windows", "linux"]`, true},
+		{`context.event.tags contains_all ["db", "windows"]`, false},
+		{`context.event.summary contains_all ["CPU", "db-1"]`, true},
+		{`context.event.tags[1] == "linux"`, true},
+		{`context.event.missing == null`, true},
+		{`context.event.missing`, false},
+		{`context.event != null`, true},
+	}
+	for _, tc := range cases {
+		got, err := evaluateICLCondition(tc.condition, env)
+		if err != nil {
+			t.Errorf("evaluateICLCondition(%q) unexpected error: %v", tc.condition, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("evaluateICLCondition(%q) = %t, want %t", tc.condition, got, tc.want)
+		}
+	}
+}
+
+func TestParseICL_ReportsPositions(t *testing.T) {
+	cases := []struct {
+		src     string
+		wantPos int
+		wantMsg string
+	}{
+		{`event.priority == `, 18, "got the end of the expression"},
+		{`event.priority = "HIGH"`, 15, "unexpected character"},
+		{`event.summary contains "CPU`, 23, "unterminated string"},
+		{`(event.priority == "HIGH"`, 25, `expected ")"`},
+		{`event.priority == "HIGH" "LOW"`, 25, `unexpected "\"LOW\""`},
+		{`event.tags[-1] == "a"`, 11, "expected a list index"},
+	}
+	for _, tc := range cases {
+		_, err := parseICL(tc.src)
+		var syntaxErr *iclSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("parseICL(%q) expected a syntax error, got %v", tc.src, err)
+			continue
+		}
+		if syntaxErr.Pos != tc.wantPos || !strings.Contains(syntaxErr.Msg, tc.wantMsg) {
+			t.Errorf("parseICL(%q) = %q at %d, want %q at %d", tc.src, syntaxErr.Msg, syntaxErr.Pos, tc.wantMsg, tc.wantPos)
+		}
+	}
+}
+
+func TestValidateICLExpression(t *testing.T) {
+	path := cty.GetAttrPath("event_filter")
+	for _, value := range []string{"", `(event.customDetails.body contains_any ["alarm"])`, "event.priority == \"HIGH\"\n  && event.labels.env in [\"prod\"]"} {
+		if diags := validateICLExpression(value, path); diags.HasError() {
+			t.Errorf("validateICLExpression(%q) unexpected diagnostics: %v", value, diags)
+		}
+	}
+
+	diags := validateICLExpression("event.priority == \"HIGH\"\n  && event.labels.env = \"prod\"", path)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(path) {
+		t.Fatalf("expected one diagnostic on the attribute, got %v", diags)
+	}
+	want := "unexpected character '=' at position 48:\n\n    && event.labels.env = \"prod\"\n                        ^"
+	if diags[0].Detail != want {
+		t.Fatalf("unexpected detail, got\n%s\nwant\n%s", diags[0].Detail, want)
+	}
+}
+
+func TestSuppressEquivalentICL(t *testing.T) {
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{`event.priority == "HIGH"`, "event.priority==\"HIGH\"", true},
+		{`(event.body contains_any ["a", "b"])`, "(\n  event.body contains_any [\n    'a',\n    'b'\n  ]\n)", true},
+		{`event.count > 1`, `event.count > 1.0`, true},
+		{`event.priority == "HIGH"`, `event.priority == "high"`, false},
+		{`event.priority == "HIGH"`, `(event.priority == "HIGH")`, false},
+		{`event.priority == "HIGH`, `event.priority  == "HIGH`, false},
+	}
+	for _, tc := range cases {
+		if got := suppressEquivalentICL("event_filter", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("suppressEquivalentICL(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}
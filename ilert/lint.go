@@ -0,0 +1,243 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// Severities of a lint rule. A rule is a warning unless the lint block of the
+// provider sets it to error or turns it off.
+const (
+	lintSeverityOff     = "off"
+	lintSeverityWarning = "warning"
+	lintSeverityError   = "error"
+)
+
+var lintSeverityAll = []string{lintSeverityOff, lintSeverityWarning, lintSeverityError}
+
+// lintTimeout bounds the lookups of one lint run, so an unreachable API delays
+// a plan by at most this long. Lookups that do not finish in time are skipped.
+const lintTimeout = 30 * time.Second
+
+// lintRules lists the rules of each linted resource, by the name of its block
+// in the lint block of the provider.
+var lintRules = map[string][]string{
+	"escalation_policy": escalationPolicyLintRules,
+	"schedule":          scheduleLintRules,
+}
+
+func providerLintSchema() *schema.Schema {
+	blocks := make(map[string]*schema.Schema, len(lintRules))
+	for block, rules := range lintRules {
+		s := make(map[string]*schema.Schema, len(rules))
+		for _, rule := range rules {
+			s[rule] = &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      lintSeverityWarning,
+				ValidateFunc: validation.StringInSlice(lintSeverityAll, false),
+			}
+		}
+		blocks[block] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: s,
+			},
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: blocks,
+		},
+	}
+}
+
+// lintConfig maps a rule, e.g. escalation_policy.duplicate_routing_key, to its
+// severity.
+type lintConfig map[string]string
+
+func buildLintConfig(v any) lintConfig {
+	config := make(lintConfig)
+	lL, _ := v.([]any)
+	if len(lL) == 0 || lL[0] == nil {
+		return config
+	}
+	blocks := lL[0].(map[string]any)
+	for block := range lintRules {
+		bL, _ := blocks[block].([]any)
+		if len(bL) == 0 || bL[0] == nil {
+			continue
+		}
+		for rule, severity := range bL[0].(map[string]any) {
+			config[block+"."+rule] = severity.(string)
+		}
+	}
+	return config
+}
+
+func (c lintConfig) severity(rule string) string {
+	if severity, ok := c[rule]; ok && severity != "" {
+		return severity
+	}
+	return lintSeverityWarning
+}
+
+// lintConfigFor returns the lint block of the provider m was configured by.
+func lintConfigFor(m any) lintConfig {
+	if meta, ok := m.(*providerMeta); ok && meta != nil && meta.lint != nil {
+		return meta.lint
+	}
+	return lintConfig{}
+}
+
+// lintFinding is a problem found by a lint rule.
+type lintFinding struct {
+	rule    string
+	summary string
+	detail  string
+}
+
+// lintRead runs a lookup of a lint rule, retried like the reads of the
+// resources until ctx is done.
+func lintRead(ctx context.Context, read func() error) error {
+	return resource.RetryContext(ctx, lintTimeout, func() *resource.RetryError {
+		err := read()
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*ilert.RetryableAPIError); ok {
+			time.Sleep(2 * time.Second)
+			return resource.RetryableError(err)
+		}
+		return resource.NonRetryableError(err)
+	})
+}
+
+// lintFindingsSchema is the computed lint_findings attribute of a linted
+// resource.
+func lintFindingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// flattenLintFindings formats the findings whose rule is not turned off for
+// the lint_findings attribute.
+func flattenLintFindings(config lintConfig, findings []lintFinding) []any {
+	result := make([]any, 0, len(findings))
+	for _, finding := range findings {
+		if config.severity(finding.rule) == lintSeverityOff {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s: %s (lint rule %s)", finding.summary, finding.detail, finding.rule))
+	}
+	return result
+}
+
+// customizeLintFindings plans the findings of a lint run, for use in
+// CustomizeDiff. The SDK cannot show warnings in a plan, so findings whose
+// rule is a warning are planned in lint_findings, and those whose rule is an
+// error fail the plan.
+func customizeLintFindings(diff *schema.ResourceDiff, config lintConfig, findings []lintFinding) error {
+	if err := lintPlanError(config, findings); err != nil {
+		return err
+	}
+	return diff.SetNew("lint_findings", flattenLintFindings(config, findings))
+}
+
+// lintPlanError returns an error for the findings whose rule is an error.
+func lintPlanError(config lintConfig, findings []lintFinding) error {
+	var errs []string
+	for _, finding := range findings {
+		if config.severity(finding.rule) == lintSeverityError {
+			errs = append(errs, fmt.Sprintf("%s: %s (lint rule %s)", finding.summary, finding.detail, finding.rule))
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("[ERROR] %s", errs[0])
+	}
+	msg := fmt.Sprintf("[ERROR] %d lint rules failed:", len(errs))
+	for _, err := range errs {
+		msg += "\n- " + err
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// lintWarnings sets lint_findings and returns every finding whose rule is not
+// turned off as a warning, for use after an apply. A rule set to error only
+// fails the plan: values that were not known at plan time are only linted once
+// applied, when failing would leave the resource tainted.
+func lintWarnings(d *schema.ResourceData, config lintConfig, findings []lintFinding) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := d.Set("lint_findings", flattenLintFindings(config, findings)); err != nil {
+		diags = append(diags, diag.Errorf("[ERROR] Error setting lint findings: %s", err.Error())...)
+	}
+	for _, finding := range findings {
+		if config.severity(finding.rule) == lintSeverityOff {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  finding.summary,
+			Detail:   fmt.Sprintf("%s\nSet the lint rule %s to \"off\" in the provider block to silence this warning.", finding.detail, finding.rule),
+		})
+	}
+	return diags
+}
+
+// userRoleStakeholder is the role of users who follow alerts but cannot
+// respond to them.
+const userRoleStakeholder = "STAKEHOLDER"
+
+// stakeholderLookup returns the user with the given id when it has the
+// STAKEHOLDER role, or nil.
+type stakeholderLookup func(id string) *ilert.User
+
+// newStakeholderLookup reads each user it is asked for once. Users that cannot
+// be read are not reported.
+func newStakeholderLookup(ctx context.Context, client *ilert.Client) stakeholderLookup {
+	users := make(map[string]*ilert.User)
+	return func(id string) *ilert.User {
+		user, ok := users[id]
+		if !ok {
+			if userID, err := strconv.ParseInt(id, 10, 64); err == nil {
+				err := lintRead(ctx, func() error {
+					r, err := client.GetUser(&ilert.GetUserInput{UserID: ilert.Int64(userID)})
+					if err == nil && r != nil {
+						user = r.User
+					}
+					return err
+				})
+				if err != nil {
+					log.Printf("[WARN] Could not read user %s to lint its role: %s", id, err.Error())
+				}
+			}
+			users[id] = user
+		}
+		if user == nil || user.Role != userRoleStakeholder {
+			return nil
+		}
+		return user
+	}
+}
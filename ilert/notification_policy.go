@@ -0,0 +1,234 @@
+package ilert
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contactStatusOK is the status of a contact that can be notified. LOCKED and
+// BLACKLISTED contacts are skipped by the API.
+const contactStatusOK = "OK"
+
+// notificationPolicyPhoneMethods are the methods that notify a phone number
+// contact. EMAIL notifies an email contact and PUSH needs no contact.
+var notificationPolicyPhoneMethods = []string{"SMS", "VOICE", "WHATSAPP"}
+
+// validateNotificationPolicyRules checks that every rule uses a method a
+// contact can be picked for.
+func validateNotificationPolicyRules(rules []notificationPreference) error {
+	for _, rule := range rules {
+		if rule.method != "PUSH" && rule.method != "EMAIL" && !slices.Contains(notificationPolicyPhoneMethods, rule.method) {
+			return fmt.Errorf("%s: the method %s is not supported by notification policies", rule, rule.method)
+		}
+	}
+	return nil
+}
+
+// notificationRuleKey identifies the rule a preference implements: all of its
+// attributes but the contact, which is resolved for each user.
+func notificationRuleKey(p notificationPreference) string {
+	p.contact = ""
+	return p.key()
+}
+
+// resolveNotificationPolicyRule picks the contact a rule notifies for a user:
+// the OK contact of the kind the method needs with the lowest id. It returns an
+// error describing why the rule cannot be applied when there is none.
+func resolveNotificationPolicyRule(rule notificationPreference, contacts []notificationContact) (notificationPreference, error) {
+	if rule.method == "PUSH" {
+		return rule, nil
+	}
+	phone := slices.Contains(notificationPolicyPhoneMethods, rule.method)
+	var found *notificationContact
+	for i := range contacts {
+		contact := contacts[i]
+		if contact.phone != phone || contact.status != contactStatusOK {
+			continue
+		}
+		if found == nil || contact.id < found.id {
+			found = &contacts[i]
+		}
+	}
+	if found == nil {
+		kind := "email"
+		if phone {
+			kind = "phone number"
+		}
+		return rule, fmt.Errorf("%s: no verified %s contact", rule, kind)
+	}
+	rule.contact = found.key()
+	return rule, nil
+}
+
+// notificationPolicyOwned maps a user to the preferences the policy created
+// for them, by notificationPreferenceRef. Only those are ever replaced or
+// deleted by the policy.
+type notificationPolicyOwned map[int64]map[string]bool
+
+// notificationPreferenceRef identifies a preference of a user. The ids of the
+// kinds of preferences are separate, so the kind is part of it.
+func notificationPreferenceRef(p notificationPreference) string {
+	return fmt.Sprintf("%s:%d", p.kind, p.id)
+}
+
+func (o notificationPolicyOwned) users() []int64 {
+	users := make([]int64, 0, len(o))
+	for userID, refs := range o {
+		if len(refs) > 0 {
+			users = append(users, userID)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+	return users
+}
+
+// notificationPolicyUserPlan holds the changes a policy makes to one user and
+// the rules that could not be applied to them. owned are the preferences of
+// the user the policy created that still exist.
+type notificationPolicyUserPlan struct {
+	userID      int64
+	contacts    []notificationContact
+	plan        notificationProfilePlan
+	owned       []notificationPreference
+	unsatisfied []string
+}
+
+// ownedAfter returns the refs of the preferences the policy owns for the user
+// once the plan is applied, given the preferences applying it created.
+// deleted is false when applying the plan failed before the deletions
+// finished, the preferences it was to delete are kept then.
+func (p notificationPolicyUserPlan) ownedAfter(created []notificationPreference, deleted bool) map[string]bool {
+	refs := make(map[string]bool, len(p.owned)+len(created))
+	for _, preference := range p.owned {
+		refs[notificationPreferenceRef(preference)] = true
+	}
+	if deleted {
+		for _, preference := range p.plan.deletePreferences {
+			delete(refs, notificationPreferenceRef(preference))
+		}
+	}
+	for _, preference := range created {
+		refs[notificationPreferenceRef(preference)] = true
+	}
+	return refs
+}
+
+// planNotificationPolicy plans the preferences of every target, previous
+// target and user the policy owns preferences of, given the current profile of
+// each. The preferences the policy created, see notificationPolicyOwned, are
+// replaced to match the rules and removed from users that are no longer
+// targeted. Every other preference is left alone; one of the user's own that
+// already matches a rule satisfies it without being taken over.
+func planNotificationPolicy(rules []notificationPreference, targets, previousTargets []int64, profiles map[int64]notificationProfile, owned notificationPolicyOwned) []notificationPolicyUserPlan {
+	users := append(append(append([]int64{}, targets...), previousTargets...), owned.users()...)
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+	users = slices.Compact(users)
+
+	plans := make([]notificationPolicyUserPlan, 0, len(users))
+	for _, userID := range users {
+		profile := profiles[userID]
+		userPlan := notificationPolicyUserPlan{userID: userID, contacts: profile.contacts}
+
+		// preferences implementing a rule that cannot be applied are kept,
+		// they are as close to the rule as the user gets
+		kept := make(map[string]bool)
+		resolved := make([]notificationPreference, 0, len(rules))
+		if slices.Contains(targets, userID) {
+			for _, rule := range rules {
+				preference, err := resolveNotificationPolicyRule(rule, profile.contacts)
+				if err != nil {
+					userPlan.unsatisfied = append(userPlan.unsatisfied, fmt.Sprintf("user %d: %s", userID, err.Error()))
+					kept[notificationRuleKey(rule)] = true
+					continue
+				}
+				resolved = append(resolved, preference)
+			}
+		}
+
+		current := make([]notificationPreference, 0)
+		own := make(map[string]bool)
+		for _, preference := range profile.preferences {
+			if !owned[userID][notificationPreferenceRef(preference)] {
+				own[preference.key()] = true
+				continue
+			}
+			userPlan.owned = append(userPlan.owned, preference)
+			if !kept[notificationRuleKey(preference)] {
+				current = append(current, preference)
+			}
+		}
+		desired := make([]notificationPreference, 0, len(resolved))
+		for _, preference := range resolved {
+			if !own[preference.key()] {
+				desired = append(desired, preference)
+			}
+		}
+
+		userPlan.plan = planNotificationProfile(notificationProfile{preferences: desired}, notificationProfile{preferences: current})
+		plans = append(plans, userPlan)
+	}
+	return plans
+}
+
+func flattenNotificationPolicyChanges(plans []notificationPolicyUserPlan) []any {
+	changes := make([]any, 0)
+	for _, userPlan := range plans {
+		for _, preference := range userPlan.plan.createPreferences {
+			changes = append(changes, fmt.Sprintf("user %d: create %s", userPlan.userID, preference))
+		}
+		for _, preference := range userPlan.plan.deletePreferences {
+			changes = append(changes, fmt.Sprintf("user %d: delete %s", userPlan.userID, preference))
+		}
+	}
+	return changes
+}
+
+func flattenNotificationPolicyUnsatisfied(plans []notificationPolicyUserPlan) []any {
+	unsatisfied := make([]any, 0)
+	for _, userPlan := range plans {
+		for _, u := range userPlan.unsatisfied {
+			unsatisfied = append(unsatisfied, u)
+		}
+	}
+	return unsatisfied
+}
+
+func expandNotificationPolicyOwned(v any) (notificationPolicyOwned, error) {
+	owned := make(notificationPolicyOwned)
+	m, _ := v.(map[string]any)
+	for user, it := range m {
+		userID, err := strconv.ParseInt(user, 10, 64)
+		if err != nil {
+			return nil, unconvertibleIDErr(user, err)
+		}
+		refs := make(map[string]bool)
+		for _, ref := range strings.Split(it.(string), ",") {
+			if ref != "" {
+				refs[ref] = true
+			}
+		}
+		owned[userID] = refs
+	}
+	return owned, nil
+}
+
+// flattenNotificationPolicyOwned sets the refs of each user as a sorted, comma
+// separated list, leaving out users the policy owns nothing of.
+func flattenNotificationPolicyOwned(owned notificationPolicyOwned) map[string]any {
+	result := make(map[string]any, len(owned))
+	for userID, refs := range owned {
+		if len(refs) == 0 {
+			continue
+		}
+		list := make([]string, 0, len(refs))
+		for ref := range refs {
+			list = append(list, ref)
+		}
+		sort.Strings(list)
+		result[strconv.FormatInt(userID, 10)] = strings.Join(list, ",")
+	}
+	return result
+}
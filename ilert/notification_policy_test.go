@@ -0,0 +1,134 @@
+package ilert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveNotificationPolicyRule(t *testing.T) {
+	contacts := []notificationContact{
+		{id: 3, target: "jane@example.com", status: contactStatusOK},
+		{id: 5, phone: true, target: "+491701234567", status: "LOCKED"},
+		{id: 4, phone: true, target: "+491707654321", status: contactStatusOK},
+		{id: 2, phone: true, target: "+491700000000", status: contactStatusOK},
+	}
+	cases := []struct {
+		method  string
+		contact string
+		err     string
+	}{
+		{"PUSH", "", ""},
+		{"EMAIL", "jane@example.com", ""},
+		{"SMS", "+491700000000", ""},
+	}
+	for _, tc := range cases {
+		preference, err := resolveNotificationPolicyRule(notificationPreference{kind: notificationPreferenceAlert, method: tc.method}, contacts)
+		if err != nil || preference.contact != tc.contact {
+			t.Errorf("%s: got %q, %v, want %q", tc.method, preference.contact, err, tc.contact)
+		}
+	}
+
+	_, err := resolveNotificationPolicyRule(notificationPreference{kind: notificationPreferenceAlert, method: "VOICE"}, contacts[:2])
+	if err == nil || !strings.Contains(err.Error(), "no verified phone number contact") {
+		t.Errorf("expected a missing contact error, got %v", err)
+	}
+	if err := validateNotificationPolicyRules([]notificationPreference{{kind: notificationPreferenceAlert, method: "TELEGRAM"}}); err == nil {
+		t.Errorf("expected TELEGRAM to be rejected")
+	}
+}
+
+func TestPlanNotificationPolicy(t *testing.T) {
+	push := notificationPreference{kind: notificationPreferenceAlert, method: "PUSH", preferenceType: "HIGH_PRIORITY"}
+	sms := notificationPreference{kind: notificationPreferenceAlert, method: "SMS", preferenceType: "HIGH_PRIORITY", minutes: 5}
+	duty := notificationPreference{kind: notificationPreferenceDuty, method: "PUSH", preferenceType: "ON_CALL", minutes: 30}
+
+	phone := notificationContact{id: 1, phone: true, target: "+491701234567", status: contactStatusOK}
+	own := notificationPreference{kind: notificationPreferenceAlert, id: 20, method: "EMAIL", contact: "jane@example.com", preferenceType: "LOW_PRIORITY"}
+	profiles := map[int64]notificationProfile{
+		// has the SMS the policy created for a previous rule and a
+		// preference of its own
+		1: {
+			contacts: []notificationContact{phone},
+			preferences: []notificationPreference{
+				withNotificationPreferenceID(push, 10),
+				{kind: notificationPreferenceAlert, id: 11, method: "SMS", contact: phone.key(), preferenceType: "HIGH_PRIORITY", minutes: 10},
+				own,
+			},
+		},
+		// no phone number, keeps its own SMS preference to a locked one
+		2: {
+			contacts: []notificationContact{{id: 2, phone: true, target: "+491707654321", status: "LOCKED"}},
+			preferences: []notificationPreference{
+				{kind: notificationPreferenceAlert, id: 12, method: "SMS", contact: "+491707654321", preferenceType: "HIGH_PRIORITY", minutes: 5},
+			},
+		},
+		// no longer targeted, keeps its own duty preference
+		3: {preferences: []notificationPreference{withNotificationPreferenceID(push, 13), withNotificationPreferenceID(duty, 14), withNotificationPreferenceID(duty, 17)}},
+		// already has preferences of its own matching the rules
+		4: {
+			contacts: []notificationContact{phone},
+			preferences: []notificationPreference{
+				withNotificationPreferenceID(push, 15),
+				{kind: notificationPreferenceAlert, id: 19, method: "SMS", contact: phone.key(), preferenceType: "HIGH_PRIORITY", minutes: 5},
+			},
+		},
+		// only known from the owned preferences
+		5: {preferences: []notificationPreference{withNotificationPreferenceID(push, 16)}},
+	}
+	owned := notificationPolicyOwned{
+		1: {"alert:10": true, "alert:11": true},
+		3: {"alert:13": true, "duty:14": true},
+		5: {"alert:16": true, "alert:18": true},
+	}
+
+	plans := planNotificationPolicy([]notificationPreference{push, sms}, []int64{2, 1, 4}, []int64{1, 3}, profiles, owned)
+	if len(plans) != 5 {
+		t.Fatalf("unexpected plans %+v", plans)
+	}
+	for i, plan := range plans {
+		if plan.userID != int64(i+1) {
+			t.Fatalf("unexpected plans %+v", plans)
+		}
+	}
+
+	got := make([]string, 0)
+	for _, change := range flattenNotificationPolicyChanges(plans) {
+		got = append(got, change.(string))
+	}
+	want := []string{
+		"user 1: create alert preference SMS HIGH_PRIORITY to +491701234567 after 5 min",
+		"user 1: delete alert preference SMS HIGH_PRIORITY to +491701234567 after 10 min",
+		"user 2: create alert preference PUSH HIGH_PRIORITY after 0 min",
+		"user 3: delete alert preference PUSH HIGH_PRIORITY after 0 min",
+		"user 3: delete duty preference PUSH ON_CALL 30 min before",
+		"user 5: delete alert preference PUSH HIGH_PRIORITY after 0 min",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes\n got: %s\nwant: %s", strings.Join(got, "\n      "), strings.Join(want, "\n      "))
+	}
+	if plans[2].plan.deletePreferences[1].id != 14 {
+		t.Errorf("expected the owned duty preference to be deleted, got %+v", plans[2].plan.deletePreferences)
+	}
+
+	unsatisfied := flattenNotificationPolicyUnsatisfied(plans)
+	if len(unsatisfied) != 1 || unsatisfied[0] != "user 2: alert preference SMS HIGH_PRIORITY after 5 min: no verified phone number contact" {
+		t.Errorf("unexpected unsatisfied %v", unsatisfied)
+	}
+
+	after := notificationPolicyOwned{
+		1: plans[0].ownedAfter([]notificationPreference{withNotificationPreferenceID(sms, 30)}, true),
+		2: plans[1].ownedAfter(nil, false),
+		3: plans[2].ownedAfter(nil, false),
+		5: plans[4].ownedAfter(nil, true),
+	}
+	flattened := flattenNotificationPolicyOwned(after)
+	wantOwned := map[string]any{"1": "alert:10,alert:30", "3": "alert:13,duty:14"}
+	if !reflect.DeepEqual(flattened, wantOwned) {
+		t.Errorf("unexpected owned preferences %v, want %v", flattened, wantOwned)
+	}
+	expanded, err := expandNotificationPolicyOwned(flattened)
+	if err != nil || !reflect.DeepEqual(flattenNotificationPolicyOwned(expanded), wantOwned) {
+		t.Errorf("unexpected expanded owned preferences %v, %v", expanded, err)
+	}
+}
@@ -0,0 +1,230 @@
+package ilert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of user notification preferences, named after the blocks of
+// ilert_user_notification_profile without the _preference suffix.
+const (
+	notificationPreferenceAlert        = "alert"
+	notificationPreferenceDuty         = "duty"
+	notificationPreferenceUpdate       = "update"
+	notificationPreferenceSubscription = "subscription"
+)
+
+var notificationPreferenceKinds = []string{
+	notificationPreferenceAlert,
+	notificationPreferenceDuty,
+	notificationPreferenceUpdate,
+	notificationPreferenceSubscription,
+}
+
+// notificationContact is an email or phone number contact of a user. Contacts
+// are identified by their target, see notificationContactKey.
+type notificationContact struct {
+	id         int64
+	phone      bool
+	regionCode string
+	target     string
+	status     string
+}
+
+func (c notificationContact) key() string {
+	if c.phone {
+		if n, err := normalizePhoneNumber(c.regionCode, c.target); err == nil {
+			return n
+		}
+	}
+	return notificationContactKey(c.target)
+}
+
+// notificationContactKey is the value preferences use to reference a contact.
+// Email addresses are compared case-insensitively and international phone
+// numbers by their E.164 form, so both kinds share one namespace.
+func notificationContactKey(target string) string {
+	if !strings.Contains(target, "@") {
+		if n, err := normalizePhoneNumber("", target); err == nil {
+			return n
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(target))
+}
+
+// notificationPreference is a preference rule of any kind. contact holds the
+// key of the contact it notifies, or an empty string for PUSH. minutes is the
+// delay_min of alert and the before_min of duty preferences.
+type notificationPreference struct {
+	kind           string
+	id             int64
+	method         string
+	contact        string
+	preferenceType string
+	minutes        int
+}
+
+func (p notificationPreference) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%d", p.kind, p.method, p.contact, p.preferenceType, p.minutes)
+}
+
+func (p notificationPreference) String() string {
+	s := fmt.Sprintf("%s preference %s", p.kind, p.method)
+	if p.preferenceType != "" {
+		s += " " + p.preferenceType
+	}
+	if p.contact != "" {
+		s += " to " + p.contact
+	}
+	switch p.kind {
+	case notificationPreferenceAlert:
+		s += fmt.Sprintf(" after %d min", p.minutes)
+	case notificationPreferenceDuty:
+		s += fmt.Sprintf(" %d min before", p.minutes)
+	}
+	return s
+}
+
+// notificationProfile is the complete notification setup of a user.
+type notificationProfile struct {
+	contacts    []notificationContact
+	preferences []notificationPreference
+}
+
+// notificationProfilePlan lists the changes that make the current profile of
+// a user match the desired one. Contacts are created and updated first so new
+// preferences can reference them, preferences are created before the ones
+// they replace are deleted so the user is never left without a rule, and
+// contacts are deleted last.
+type notificationProfilePlan struct {
+	createContacts    []notificationContact
+	updateContacts    []notificationContact
+	createPreferences []notificationPreference
+	deletePreferences []notificationPreference
+	deleteContacts    []notificationContact
+}
+
+func (p notificationProfilePlan) empty() bool {
+	return len(p.createContacts) == 0 && len(p.updateContacts) == 0 && len(p.createPreferences) == 0 &&
+		len(p.deletePreferences) == 0 && len(p.deleteContacts) == 0
+}
+
+// planNotificationProfile compares the desired profile with the current one.
+// Contacts match by target and preferences by all of their attributes, so a
+// changed preference is replaced rather than updated. Updated contacts keep
+// the id of the current one.
+func planNotificationProfile(desired, current notificationProfile) notificationProfilePlan {
+	plan := notificationProfilePlan{}
+
+	currentContacts := make(map[string]notificationContact, len(current.contacts))
+	for _, contact := range current.contacts {
+		currentContacts[contact.key()] = contact
+	}
+	desiredContacts := make(map[string]bool, len(desired.contacts))
+	for _, contact := range desired.contacts {
+		desiredContacts[contact.key()] = true
+		existing, ok := currentContacts[contact.key()]
+		switch {
+		case !ok:
+			plan.createContacts = append(plan.createContacts, contact)
+		case contact.phone && existing.regionCode != contact.regionCode:
+			contact.id = existing.id
+			plan.updateContacts = append(plan.updateContacts, contact)
+		}
+	}
+	for _, contact := range current.contacts {
+		if !desiredContacts[contact.key()] {
+			plan.deleteContacts = append(plan.deleteContacts, contact)
+		}
+	}
+
+	currentPreferences := make(map[string]int, len(current.preferences))
+	for _, preference := range current.preferences {
+		currentPreferences[preference.key()]++
+	}
+	desiredPreferences := make(map[string]int, len(desired.preferences))
+	for _, preference := range desired.preferences {
+		desiredPreferences[preference.key()]++
+		if desiredPreferences[preference.key()] > currentPreferences[preference.key()] {
+			plan.createPreferences = append(plan.createPreferences, preference)
+		}
+	}
+	kept := make(map[string]int, len(current.preferences))
+	for _, preference := range current.preferences {
+		kept[preference.key()]++
+		if kept[preference.key()] > desiredPreferences[preference.key()] {
+			plan.deletePreferences = append(plan.deletePreferences, preference)
+		}
+	}
+
+	sortNotificationPreferences(plan.createPreferences)
+	sortNotificationPreferences(plan.deletePreferences)
+	return plan
+}
+
+func sortNotificationPreferences(preferences []notificationPreference) {
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].key() < preferences[j].key()
+	})
+}
+
+// validateNotificationPreferences checks that every preference either uses
+// PUSH or references one of the given contacts.
+func validateNotificationPreferences(contacts []notificationContact, preferences []notificationPreference) error {
+	known := make(map[string]bool, len(contacts))
+	for _, contact := range contacts {
+		known[contact.key()] = true
+	}
+	for _, preference := range preferences {
+		if preference.method == "PUSH" {
+			if preference.contact != "" {
+				return fmt.Errorf("%s: contact must not be set when method is 'PUSH'", preference)
+			}
+			continue
+		}
+		if preference.contact == "" {
+			return fmt.Errorf("%s: contact must be set when method is '%s'", preference, preference.method)
+		}
+		if !known[preference.contact] {
+			return fmt.Errorf("%s: contact %q is not an email_contact or phone_contact of this profile", preference, preference.contact)
+		}
+	}
+	return nil
+}
+
+// notificationProfileContactStatusKnown reports whether status holds the
+// status of every contact an alert preference notifies, so that the warnings
+// about them can be planned.
+func notificationProfileContactStatusKnown(preferences []notificationPreference, status map[string]any) bool {
+	known := make(map[string]bool, len(status))
+	for target := range status {
+		known[notificationContactKey(target)] = true
+	}
+	for _, preference := range preferences {
+		if preference.kind == notificationPreferenceAlert && preference.contact != "" && !known[preference.contact] {
+			return false
+		}
+	}
+	return true
+}
+
+// notificationProfileContactWarnings describes the alert preferences that
+// notify a contact which is not verified. status holds the status of each
+// contact as in contact_status.
+func notificationProfileContactWarnings(preferences []notificationPreference, status map[string]any) []string {
+	statusByKey := make(map[string]string, len(status))
+	for target, s := range status {
+		statusByKey[notificationContactKey(target)], _ = s.(string)
+	}
+	warnings := make([]string, 0)
+	for _, preference := range preferences {
+		if preference.kind != notificationPreferenceAlert || preference.contact == "" {
+			continue
+		}
+		if warning := unverifiedContactWarning("The "+preference.String(), preference.contact, statusByKey[preference.contact]); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
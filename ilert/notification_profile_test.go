@@ -0,0 +1,109 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestExpandNotificationProfile(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceUserNotificationProfile().Schema, map[string]any{
+		"user":          "1",
+		"email_contact": []any{map[string]any{"target": "Jane.Doe@example.com"}},
+		"phone_contact": []any{map[string]any{"region_code": "DE", "target": "+491701234567"}},
+		"alert_preference": []any{
+			map[string]any{"method": "PUSH", "delay_min": 0, "type": "HIGH_PRIORITY"},
+			map[string]any{"method": "SMS", "contact": "+491701234567", "delay_min": 5, "type": "HIGH_PRIORITY"},
+		},
+		"subscription_preference": []any{map[string]any{"method": "EMAIL", "contact": "jane.doe@EXAMPLE.com"}},
+	})
+	profile, err := expandNotificationProfile(d.Get)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(profile.contacts) != 2 || len(profile.preferences) != 3 {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceUserNotificationProfile().Schema, map[string]any{
+		"user":             "1",
+		"email_contact":    []any{map[string]any{"target": "jane.doe@example.com"}},
+		"alert_preference": []any{map[string]any{"method": "SMS", "contact": "+491701234567", "delay_min": 5, "type": "HIGH_PRIORITY"}},
+	})
+	if _, err := expandNotificationProfile(d.Get); err == nil || !strings.Contains(err.Error(), "is not an email_contact or phone_contact") {
+		t.Fatalf("expected an unknown contact error, got %v", err)
+	}
+}
+
+func TestValidateNotificationPreferences(t *testing.T) {
+	contacts := []notificationContact{{target: "jane@example.com"}}
+	cases := []struct {
+		preference notificationPreference
+		err        string
+	}{
+		{notificationPreference{kind: notificationPreferenceAlert, method: "PUSH"}, ""},
+		{notificationPreference{kind: notificationPreferenceAlert, method: "PUSH", contact: "jane@example.com"}, "must not be set"},
+		{notificationPreference{kind: notificationPreferenceUpdate, method: "EMAIL"}, "must be set"},
+		{notificationPreference{kind: notificationPreferenceUpdate, method: "EMAIL", contact: "jane@example.com"}, ""},
+	}
+	for _, tc := range cases {
+		err := validateNotificationPreferences(contacts, []notificationPreference{tc.preference})
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: got %v, want %q", tc.preference, err, tc.err)
+		}
+	}
+}
+
+func TestPlanNotificationProfile(t *testing.T) {
+	push := notificationPreference{kind: notificationPreferenceAlert, method: "PUSH", preferenceType: "HIGH_PRIORITY"}
+	sms := notificationPreference{kind: notificationPreferenceAlert, method: "SMS", contact: "+491701234567", preferenceType: "HIGH_PRIORITY", minutes: 5}
+	email := notificationPreference{kind: notificationPreferenceSubscription, method: "EMAIL", contact: "jane@example.com"}
+
+	desired := notificationProfile{
+		contacts: []notificationContact{
+			{target: "Jane@example.com"},
+			{phone: true, regionCode: "DE", target: "+491701234567"},
+		},
+		preferences: []notificationPreference{push, sms, email},
+	}
+	current := notificationProfile{
+		contacts: []notificationContact{
+			{id: 1, target: "jane@example.com"},
+			{id: 2, phone: true, regionCode: "AT", target: "+491701234567"},
+			{id: 3, target: "old@example.com"},
+		},
+		preferences: []notificationPreference{
+			withNotificationPreferenceID(push, 10),
+			withNotificationPreferenceID(push, 11),
+			withNotificationPreferenceID(email, 12),
+			{kind: notificationPreferenceAlert, id: 13, method: "TELEGRAM", contact: "#4", preferenceType: "HIGH_PRIORITY"},
+		},
+	}
+
+	plan := planNotificationProfile(desired, current)
+	if len(plan.createContacts) != 0 {
+		t.Errorf("unexpected contacts to create %+v", plan.createContacts)
+	}
+	if len(plan.updateContacts) != 1 || plan.updateContacts[0].id != 2 || plan.updateContacts[0].regionCode != "DE" {
+		t.Errorf("unexpected contacts to update %+v", plan.updateContacts)
+	}
+	if len(plan.deleteContacts) != 1 || plan.deleteContacts[0].id != 3 {
+		t.Errorf("unexpected contacts to delete %+v", plan.deleteContacts)
+	}
+	if len(plan.createPreferences) != 1 || plan.createPreferences[0] != sms {
+		t.Errorf("unexpected preferences to create %+v", plan.createPreferences)
+	}
+	if len(plan.deletePreferences) != 2 || plan.deletePreferences[0].id != 11 || plan.deletePreferences[1].id != 13 {
+		t.Errorf("unexpected preferences to delete %+v", plan.deletePreferences)
+	}
+
+	if plan := planNotificationProfile(desired, notificationProfile{contacts: desired.contacts, preferences: desired.preferences}); !plan.empty() {
+		t.Errorf("expected an empty plan, got %+v", plan)
+	}
+}
+
+func withNotificationPreferenceID(preference notificationPreference, id int64) notificationPreference {
+	preference.id = id
+	return preference
+}
@@ -0,0 +1,87 @@
+package ilert
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// phonenumberUnknownRegion is the region libphonenumber parses international
+// numbers with.
+const phonenumberUnknownRegion = "ZZ"
+
+// normalizePhoneNumber returns the E.164 form of a phone number, e.g.
+// +491701234567 for "+49 (0)170 123-4567" or, with region DE, "0170 1234567".
+// Numbers are parsed and validated with the libphonenumber metadata of their
+// region, so national numbers and the international call prefix of the
+// region are understood. Numbers of a given region must carry its calling
+// code. Without a region libphonenumber knows, the number has to be
+// international.
+func normalizePhoneNumber(regionCode, number string) (string, error) {
+	region := strings.ToUpper(strings.TrimSpace(regionCode))
+	if phonenumbers.GetCountryCodeForRegion(region) == 0 {
+		region = phonenumberUnknownRegion
+	}
+	if strings.IndexFunc(number, unicode.IsLetter) >= 0 {
+		return "", fmt.Errorf("%q is not a phone number", number)
+	}
+	if region == phonenumberUnknownRegion && !strings.HasPrefix(strings.TrimSpace(number), "+") {
+		return "", fmt.Errorf("%q must be in E.164 format, e.g. +491701234567", number)
+	}
+
+	n, err := phonenumbers.Parse(number, region)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a phone number: %s", number, err.Error())
+	}
+	if region != phonenumberUnknownRegion {
+		if callingCode := phonenumbers.GetCountryCodeForRegion(region); int(n.GetCountryCode()) != callingCode {
+			return "", fmt.Errorf("%q is not a number of region %s, its numbers start with +%d", number, region, callingCode)
+		}
+	}
+	if !phonenumbers.IsValidNumber(n) {
+		return "", fmt.Errorf("%q is not a valid number%s", number, phoneNumberInvalidReason(n))
+	}
+	return phonenumbers.Format(n, phonenumbers.E164), nil
+}
+
+// phoneNumberInvalidReason explains why a parsed number is not valid, for
+// error messages.
+func phoneNumberInvalidReason(n *phonenumbers.PhoneNumber) string {
+	region := phonenumbers.GetRegionCodeForCountryCode(int(n.GetCountryCode()))
+	switch phonenumbers.IsPossibleNumberWithReason(n) {
+	case phonenumbers.TOO_SHORT:
+		return fmt.Sprintf(" of region %s: it is too short", region)
+	case phonenumbers.TOO_LONG:
+		return fmt.Sprintf(" of region %s: it is too long", region)
+	case phonenumbers.INVALID_LENGTH:
+		return fmt.Sprintf(" of region %s: its length is not possible", region)
+	}
+	return fmt.Sprintf(" of region %s", region)
+}
+
+// equivalentPhoneNumbers reports whether two phone numbers are the same once
+// normalized. Numbers that cannot be normalized are compared as written.
+func equivalentPhoneNumbers(regionCode, a, b string) bool {
+	if a == b {
+		return true
+	}
+	na, err := normalizePhoneNumber(regionCode, a)
+	if err != nil {
+		return false
+	}
+	nb, err := normalizePhoneNumber(regionCode, b)
+	if err != nil {
+		return false
+	}
+	return na == nb
+}
+
+// suppressEquivalentPhoneNumber is the DiffSuppressFunc of phone number
+// attributes with a sibling region_code attribute.
+func suppressEquivalentPhoneNumber(k, old, new string, d *schema.ResourceData) bool {
+	regionCode, _ := d.Get(strings.TrimSuffix(k, "target") + "region_code").(string)
+	return equivalentPhoneNumbers(regionCode, old, new)
+}
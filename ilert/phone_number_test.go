@@ -0,0 +1,75 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	cases := []struct {
+		region string
+		number string
+		want   string
+		err    string
+	}{
+		{"DE", "+491701234567", "+491701234567", ""},
+		{"DE", "+49 170 1234567", "+491701234567", ""},
+		{"DE", "+49 (0)170 123-4567", "+491701234567", ""},
+		{"DE", "0049 170 1234567", "+491701234567", ""},
+		{"DE", "0170 1234567", "+491701234567", ""},
+		{"de", "0170/1234567", "+491701234567", ""},
+		{"US", "(415) 555-0132", "+14155550132", ""},
+		{"US", "1 415 555 0132", "+14155550132", ""},
+		{"IT", "06 1234 5678", "+390612345678", ""},
+		{"", "+44 20 7946 0958", "+442079460958", ""},
+		{"XX", "+1 415 555 0132", "+14155550132", ""},
+		{"DE", "+44 20 7946 0958", "", "is not a number of region DE"},
+		{"US", "+1 415 555 013", "", "too short"},
+		{"DE", "+49 170 123", "", "is not a valid number of region DE"},
+		{"US", "011 1 415 555 0132", "+14155550132", ""},
+		{"", "+999 1234567", "", "is not a phone number"},
+		{"", "0170 1234567", "", "E.164"},
+		{"DE", "+49 170 CALL ME", "", "is not a phone number"},
+		{"", "+1234567890123456", "", "too long"},
+	}
+	for _, tc := range cases {
+		got, err := normalizePhoneNumber(tc.region, tc.number)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s %q: expected an error containing %q, got %q, %v", tc.region, tc.number, tc.err, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%s %q: got %q, %v, want %q", tc.region, tc.number, got, err, tc.want)
+		}
+	}
+}
+
+func TestEquivalentPhoneNumbers(t *testing.T) {
+	if !equivalentPhoneNumbers("DE", "+49 170 1234567", "+491701234567") {
+		t.Errorf("expected formatting differences to be equivalent")
+	}
+	if !equivalentPhoneNumbers("DE", "0170 1234567", "+491701234567") {
+		t.Errorf("expected a national number to match its E.164 form")
+	}
+	if equivalentPhoneNumbers("DE", "+491701234567", "+491701234568") {
+		t.Errorf("expected different numbers not to be equivalent")
+	}
+	if equivalentPhoneNumbers("", "0170 1234567", "0170  1234567") {
+		t.Errorf("expected numbers that cannot be normalized to be compared as written")
+	}
+}
+
+func TestNotificationContactKey(t *testing.T) {
+	if got := notificationContactKey("+49 170 1234567"); got != "+491701234567" {
+		t.Errorf("got %q", got)
+	}
+	if got := notificationContactKey(" Jane@Example.com "); got != "jane@example.com" {
+		t.Errorf("got %q", got)
+	}
+	national := notificationContact{phone: true, regionCode: "DE", target: "0170 1234567"}
+	if got := national.key(); got != "+491701234567" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -46,12 +46,15 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("ILERT_DEBUG", false),
 				Description: "Enable full request/response tracing (method, URL, headers, body) to diagnose API issues such as WAF/proxy blocks. Can also be enabled via the ILERT_DEBUG environment variable. WARNING: the resulting debug logs contain request and response bodies and other potentially sensitive data (the Authorization header is masked) - do not share them or commit them to CI logs.",
 			},
+			"lint": providerLintSchema(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"ilert_alert_action":              dataSourceAlertAction(),
+			"ilert_alert_action_condition":    dataSourceAlertActionCondition(),
 			"ilert_alert_source":              dataSourceAlertSource(),
 			"ilert_event_flow":                dataSourceEventFlow(),
 			"ilert_event_flow_integration":    dataSourceEventFlowIntegration(),
+			"ilert_event_flow_simulation":     dataSourceEventFlowSimulation(),
 			"ilert_connection":                dataSourceConnection(),
 			"ilert_connector":                 dataSourceConnector(),
 			"ilert_deployment_pipeline":       dataSourceDeploymentPipeline(),
@@ -60,9 +63,12 @@ func Provider() *schema.Provider {
 			"ilert_incident_template":         dataSourceIncidentTemplate(),
 			"ilert_metric":                    dataSourceMetric(),
 			"ilert_metric_data_source":        dataSourceMetricDataSource(),
+			"ilert_on_call":                   dataSourceOnCall(),
 			"ilert_schedule":                  dataSourceSchedule(),
+			"ilert_schedule_ics":              dataSourceScheduleICS(),
 			"ilert_service":                   dataSourceService(),
 			"ilert_call_flow":                 dataSourceCallFlow(),
+			"ilert_flow_diagram":              dataSourceFlowDiagram(),
 			"ilert_status_page":               dataSourceStatusPage(),
 			"ilert_status_page_group":         dataSourceStatusPageGroup(),
 			"ilert_support_hour":              dataSourceSupportHour(),
@@ -71,11 +77,14 @@ func Provider() *schema.Provider {
 			"ilert_user":                      dataSourceUser(),
 			"ilert_user_email_contact":        dataSourceUserEmailContact(),
 			"ilert_user_phone_number_contact": dataSourceUserPhoneNumberContact(),
+			"ilert_user_pending_invitations":  dataSourceUserPendingInvitations(),
+			"ilert_user_unverified_contacts":  dataSourceUserUnverifiedContacts(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"ilert_alert_action":                   resourceAlertAction(),
 			"ilert_alert_action_source_attachment": resourceAlertActionSourceAttachment(),
 			"ilert_alert_source":                   resourceAlertSource(),
+			"ilert_api_key":                        resourceAPIKey(),
 			"ilert_automation_rule":                resourceAutomationRule(),
 			"ilert_call_flow":                      resourceCallFlow(),
 			"ilert_event_flow":                     resourceEventFlow(),
@@ -83,23 +92,30 @@ func Provider() *schema.Provider {
 			"ilert_connection":                     resourceConnection(),
 			"ilert_connector":                      resourceConnector(),
 			"ilert_deployment_pipeline":            resourceDeploymentPipeline(),
+			"ilert_directory_sync":                 resourceDirectorySync(),
 			"ilert_escalation_policy":              resourceEscalationPolicy(),
 			"ilert_heartbeat_monitor":              resourceHeartbeatMonitor(),
 			"ilert_incident_template":              resourceIncidentTemplate(),
 			"ilert_metric":                         resourceMetric(),
 			"ilert_metric_data_source":             resourceMetricDataSource(),
+			"ilert_notification_policy":            resourceNotificationPolicy(),
+			"ilert_role":                           resourceRole(),
 			"ilert_schedule":                       resourceSchedule(),
+			"ilert_schedule_override":              resourceScheduleOverride(),
 			"ilert_service":                        resourceService(),
+			"ilert_service_account":                resourceServiceAccount(),
 			"ilert_status_page":                    resourceStatusPage(),
 			"ilert_status_page_group":              resourceStatusPageGroup(),
 			"ilert_support_hour":                   resourceSupportHour(),
 			"ilert_team":                           resourceTeam(),
+			"ilert_team_membership":                resourceTeamMembership(),
 			"ilert_uptime_monitor":                 resourceUptimeMonitor(),
 			"ilert_user":                           resourceUser(),
 			"ilert_user_email_contact":             resourceUserEmailContact(),
 			"ilert_user_phone_number_contact":      resourceUserPhoneNumberContact(),
 			"ilert_user_alert_preference":          resourceUserAlertPreference(),
 			"ilert_user_duty_preference":           resourceUserDutyPreference(),
+			"ilert_user_notification_profile":      resourceUserNotificationProfile(),
 			"ilert_user_subscription_preference":   resourceUserSubscriptionPreference(),
 			"ilert_user_update_preference":         resourceUserUpdatePreference(),
 		},
@@ -149,5 +165,20 @@ func providerConfigure(_ context.Context, d *schema.ResourceData, terraformVersi
 		})
 		return nil, diags
 	}
-	return client, nil
+	return &providerMeta{client: client, lint: buildLintConfig(d.Get("lint"))}, nil
+}
+
+// providerMeta is the meta every resource and data source is handed.
+type providerMeta struct {
+	client *ilert.Client
+	lint   lintConfig
+}
+
+// providerClient returns the client of m, or nil when the provider is not
+// configured.
+func providerClient(m any) *ilert.Client {
+	if meta, ok := m.(*providerMeta); ok && meta != nil {
+		return meta.client
+	}
+	return nil
 }
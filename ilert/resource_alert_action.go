@@ -643,10 +643,20 @@ func resourceAlertAction() *schema.Resource {
 				Optional: true,
 			},
 			"conditions": {
-				Type:     schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ConflictsWith:    []string{"condition"},
+				DiffSuppressFunc: suppressEquivalentICL,
+			},
+			"condition": {
+				Type:     schema.TypeList,
 				Optional: true,
+				MaxItems: 1,
+				Elem:     resourceAlertActionConditionGroup(alertActionConditionDepth),
 			},
 		},
+		CustomizeDiff: customizeAlertActionConditions,
 		CreateContext: resourceAlertActionCreate,
 		ReadContext:   resourceAlertActionRead,
 		UpdateContext: resourceAlertActionUpdate,
@@ -1055,12 +1065,19 @@ func buildAlertAction(d *schema.ResourceData) (*ilert.AlertAction, error) {
 	if val, ok := d.GetOk("conditions"); ok {
 		alertAction.Conditions = val.(string)
 	}
+	if val, ok := d.GetOk("condition"); ok {
+		conditions, err := alertActionConditionExpression(val)
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR] Could not compile condition: %s", err.Error())
+		}
+		alertAction.Conditions = conditions
+	}
 
 	return alertAction, nil
 }
 
 func resourceAlertActionCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertAction, err := buildAlertAction(d)
 	if err != nil {
@@ -1106,7 +1123,7 @@ func resourceAlertActionCreate(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceAlertActionRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID := d.Id()
 	log.Printf("[DEBUG] Reading alert action: %s", d.Id())
@@ -1157,7 +1174,7 @@ func resourceAlertActionRead(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceAlertActionUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertAction, err := buildAlertAction(d)
 	if err != nil {
@@ -1189,7 +1206,7 @@ func resourceAlertActionUpdate(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceAlertActionDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID := d.Id()
 	log.Printf("[DEBUG] Deleting alert action: %s", d.Id())
@@ -1215,7 +1232,7 @@ func resourceAlertActionDelete(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceAlertActionExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID := d.Id()
 	log.Printf("[DEBUG] Reading alert action: %s", d.Id())
@@ -118,7 +118,7 @@ func resourceAlertActionSourceAttachment() *schema.Resource {
 }
 
 func resourceAlertActionSourceAttachmentCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID := blockID(d, "alert_action")
 	alertSourceIDStr := blockID(d, "alert_source")
@@ -164,7 +164,7 @@ func resourceAlertActionSourceAttachmentCreate(ctx context.Context, d *schema.Re
 }
 
 func resourceAlertActionSourceAttachmentRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID, alertSourceID, err := parseAlertActionSourceAttachmentID(d.Id())
 	if err != nil {
@@ -218,7 +218,7 @@ func resourceAlertActionSourceAttachmentRead(ctx context.Context, d *schema.Reso
 }
 
 func resourceAlertActionSourceAttachmentDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID, alertSourceID, err := parseAlertActionSourceAttachmentID(d.Id())
 	if err != nil {
@@ -264,7 +264,7 @@ func resourceAlertActionSourceAttachmentDelete(ctx context.Context, d *schema.Re
 }
 
 func resourceAlertActionSourceAttachmentExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertActionID, alertSourceID, err := parseAlertActionSourceAttachmentID(d.Id())
 	if err != nil {
@@ -2,10 +2,13 @@ package ilert
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -19,14 +22,18 @@ func getSupportDaySchemaResource() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"start": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "08:00",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "08:00",
+				ValidateFunc:     validateTimeOfDay,
+				DiffSuppressFunc: suppressEquivalentTimeOfDay,
 			},
 			"end": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "17:00",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "17:00",
+				ValidateFunc:     validateTimeOfDay,
+				DiffSuppressFunc: suppressEquivalentTimeOfDay,
 			},
 		},
 	}
@@ -238,6 +245,7 @@ func resourceAlertSource() *schema.Resource {
 							Deprecated:   "The field `timezone` is deprecated! Please use the support hour resource instead and reference it via field `id`.",
 							Optional:     true,
 							RequiredWith: []string{"support_hours.0.support_days"},
+							ValidateFunc: validateTimezone,
 						},
 						"auto_raise_incidents": { // @deprecated
 							Deprecated: "The field `auto_raise_incidents` is deprecated! Please use auto_raise_alerts instead.",
@@ -469,8 +477,9 @@ func resourceAlertSource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"text_template": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateTemplateText,
 						},
 					},
 				},
@@ -482,8 +491,9 @@ func resourceAlertSource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"text_template": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateTemplateText,
 						},
 					},
 				},
@@ -495,8 +505,9 @@ func resourceAlertSource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"text_template": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateTemplateText,
 						},
 					},
 				},
@@ -508,8 +519,9 @@ func resourceAlertSource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"text_template": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateTemplateText,
 						},
 					},
 				},
@@ -532,8 +544,9 @@ func resourceAlertSource() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"text_template": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateTemplateText,
 									},
 								},
 							},
@@ -546,8 +559,9 @@ func resourceAlertSource() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"text_template": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateTemplateText,
 									},
 								},
 							},
@@ -569,8 +583,9 @@ func resourceAlertSource() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"text_template": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateTemplateText,
 									},
 								},
 							},
@@ -610,8 +625,9 @@ func resourceAlertSource() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"text_template": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateTemplateText,
 									},
 								},
 							},
@@ -672,7 +688,7 @@ func resourceAlertSource() *schema.Resource {
 						"text_template": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validation.StringIsNotEmpty,
+							ValidateFunc: validation.All(validation.StringIsNotEmpty, validateTemplateText),
 						},
 					},
 				},
@@ -692,20 +708,41 @@ func resourceAlertSource() *schema.Resource {
 				Optional: true,
 			},
 			"event_filter": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateICLExpression,
+				DiffSuppressFunc: suppressEquivalentICL,
 			},
 			"event_type_filter_create": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateICLExpression,
+				DiffSuppressFunc: suppressEquivalentICL,
 			},
 			"event_type_filter_accept": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateICLExpression,
+				DiffSuppressFunc: suppressEquivalentICL,
 			},
 			"event_type_filter_resolve": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateICLExpression,
+				DiffSuppressFunc: suppressEquivalentICL,
+			},
+			"sample_event": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "A sample inbound event as JSON, which the templates are rendered against in rendered_preview.",
+			},
+			"rendered_preview": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 		},
 		CreateContext: resourceAlertSourceCreate,
@@ -713,7 +750,7 @@ func resourceAlertSource() *schema.Resource {
 		UpdateContext: resourceAlertSourceUpdate,
 		DeleteContext: resourceAlertSourceDelete,
 		Exists:        resourceAlertSourceExists,
-		CustomizeDiff: validateLinkTemplates,
+		CustomizeDiff: customizeAlertSourceDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -726,6 +763,13 @@ func resourceAlertSource() *schema.Resource {
 	}
 }
 
+func customizeAlertSourceDiff(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if err := validateLinkTemplates(ctx, diff, m); err != nil {
+		return err
+	}
+	return customizeAlertSourceRenderedPreview(ctx, diff, m)
+}
+
 // validateLinkTemplates surfaces the API requirement that every link template
 // carries a display name at plan time instead of failing mid-apply. Values that
 // are not known until apply are skipped, they get checked again in
@@ -771,6 +815,124 @@ func validateLinkTemplates(ctx context.Context, diff *schema.ResourceDiff, m any
 	return nil
 }
 
+// alertSourceTemplateKeys are the attributes rendered_preview renders.
+var alertSourceTemplateKeys = []string{"summary_template", "details_template", "routing_template", "alert_key_template", "link_template", "priority_template", "severity_template", "services_template"}
+
+// customizeAlertSourceRenderedPreview renders the templates against the
+// sample_event at plan time, so reviewers see what an alert will look like
+// instead of finding out when real alerts render wrong.
+func customizeAlertSourceRenderedPreview(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	for _, key := range append([]string{"sample_event"}, alertSourceTemplateKeys...) {
+		if !diffValuesKnown(diff, key) {
+			return diff.SetNewComputed("rendered_preview")
+		}
+	}
+
+	preview, _, err := alertSourceRenderedPreview(diff.Get)
+	if err != nil {
+		return err
+	}
+	if old, _ := diff.GetChange("rendered_preview"); len(preview) == 0 && len(old.(map[string]any)) == 0 {
+		return nil
+	}
+	return diff.SetNew("rendered_preview", preview)
+}
+
+// setAlertSourceRenderedPreview refreshes rendered_preview from the templates
+// in d, the same way customizeAlertSourceRenderedPreview planned it.
+func setAlertSourceRenderedPreview(d *schema.ResourceData) error {
+	preview, _, err := alertSourceRenderedPreview(d.Get)
+	if err != nil {
+		log.Printf("[WARN] Could not render the templates of alert source %s: %s", d.Id(), err.Error())
+		preview = map[string]any{}
+	}
+	if err := d.Set("rendered_preview", preview); err != nil {
+		return fmt.Errorf("[ERROR] Error setting rendered preview: %s", err.Error())
+	}
+	return nil
+}
+
+// alertSourceTemplateWarnings warns after an apply about the templates left
+// out of rendered_preview because they call functions the provider cannot
+// render.
+func alertSourceTemplateWarnings(d *schema.ResourceData) diag.Diagnostics {
+	_, skipped, err := alertSourceRenderedPreview(d.Get)
+	if err != nil || len(skipped) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(skipped))
+	for key := range skipped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("- %s: %s", key, skipped[key]))
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Alert source %q has templates missing from rendered_preview", d.Get("name").(string)),
+			Detail:   fmt.Sprintf("The following templates call functions the provider cannot render, so they are not previewed. ilert still renders them for real alerts.\n%s", strings.Join(lines, "\n")),
+		},
+	}
+}
+
+// alertSourceRenderedPreview renders each configured template against the
+// sample_event, keyed by the attribute path of the template, such as
+// summary_template or link_template.0.href_template. It is empty without a
+// sample_event. The templates that could not be rendered are returned with the
+// reason, keyed the same way.
+func alertSourceRenderedPreview(get func(key string) any) (map[string]any, map[string]string, error) {
+	preview := make(map[string]any)
+	skipped := make(map[string]string)
+	sample, _ := get("sample_event").(string)
+	if sample == "" {
+		return preview, skipped, nil
+	}
+	var event map[string]any
+	if err := json.Unmarshal([]byte(sample), &event); err != nil {
+		return nil, nil, fmt.Errorf("[ERROR] sample_event must be a JSON object: %s", err.Error())
+	}
+
+	render := func(key string, blocks any) {
+		bL, _ := blocks.([]any)
+		if len(bL) == 0 || bL[0] == nil {
+			return
+		}
+		text, _ := bL[0].(map[string]any)["text_template"].(string)
+		if text == "" {
+			return
+		}
+		rendered, err := renderTemplate(text, event)
+		if err != nil {
+			skipped[key] = err.Error()
+			return
+		}
+		preview[key] = rendered
+	}
+	for _, key := range []string{"summary_template", "details_template", "routing_template", "alert_key_template"} {
+		render(key, get(key))
+	}
+	for _, key := range []string{"priority_template", "severity_template"} {
+		if bL, _ := get(key).([]any); len(bL) > 0 && bL[0] != nil {
+			render(key, bL[0].(map[string]any)["value_template"])
+		}
+	}
+	links, _ := get("link_template").([]any)
+	for i, it := range links {
+		if v, ok := it.(map[string]any); ok {
+			render(fmt.Sprintf("link_template.%d.link_text_template", i), v["link_text_template"])
+			render(fmt.Sprintf("link_template.%d.href_template", i), v["href_template"])
+		}
+	}
+	services, _ := get("services_template").([]any)
+	for i, it := range services {
+		render(fmt.Sprintf("services_template.%d", i), []any{it})
+	}
+	return preview, skipped, nil
+}
+
 func buildAlertSource(d *schema.ResourceData) (*ilert.AlertSource, error) {
 	escalationPolicyID, err := strconv.ParseInt(d.Get("escalation_policy").(string), 10, 64)
 	if err != nil {
@@ -1233,7 +1395,7 @@ func buildCreateAlertSource(d *schema.ResourceData) (*ilert.AlertSource, error)
 }
 
 func resourceAlertSourceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertSource, err := buildCreateAlertSource(d)
 	if err != nil {
@@ -1267,11 +1429,15 @@ func resourceAlertSourceCreate(ctx context.Context, d *schema.ResourceData, m an
 		return diag.Errorf("alert source response is empty")
 	}
 	d.SetId(strconv.FormatInt(result.AlertSource.ID, 10))
-	return resourceAlertSourceRead(ctx, d, m)
+	diags := resourceAlertSourceRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, alertSourceTemplateWarnings(d)...)
 }
 
 func resourceAlertSourceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertSourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -1318,7 +1484,7 @@ func resourceAlertSourceRead(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceAlertSourceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertSource, err := buildAlertSource(d)
 	if err != nil {
@@ -1349,11 +1515,15 @@ func resourceAlertSourceUpdate(ctx context.Context, d *schema.ResourceData, m an
 		log.Printf("[ERROR] Updating ilert alert source error %s", err.Error())
 		return diag.FromErr(err)
 	}
-	return resourceAlertSourceRead(ctx, d, m)
+	diags := resourceAlertSourceRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, alertSourceTemplateWarnings(d)...)
 }
 
 func resourceAlertSourceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertSourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -1380,7 +1550,7 @@ func resourceAlertSourceDelete(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceAlertSourceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	alertSourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -1660,7 +1830,7 @@ func transformAlertSourceResource(alertSource *ilert.AlertSource, d *schema.Reso
 		}
 	}
 
-	return nil
+	return setAlertSourceRenderedPreview(d)
 }
 
 func flattenEmailPredicateList(predicateList []ilert.EmailPredicate) ([]any, error) {
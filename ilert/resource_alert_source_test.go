@@ -685,3 +685,102 @@ func TestFlattenLinkTemplatesList(t *testing.T) {
 		t.Fatalf("expected no link_text_template for a legacy link template, got %v", got)
 	}
 }
+
+func TestAlertSourceRenderedPreview(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertSource().Schema, map[string]any{
+		"name":              "test-alert-source",
+		"integration_type":  "EMAIL2",
+		"escalation_policy": "1",
+		"sample_event":      `{"subject": "ALARM my server db-1 is down", "body": {"env": "prod"}}`,
+		"summary_template": []any{
+			map[string]any{"text_template": `[{{ body.env }}] {{ subject.splitTakeAt("my server", 1).trim() }}`},
+		},
+		"link_template": []any{
+			map[string]any{
+				"link_text_template": []any{map[string]any{"text_template": "Runbook"}},
+				"href_template":      []any{map[string]any{"text_template": "https://runbooks.example.com/{{ body.env }}"}},
+			},
+		},
+		"priority_template": []any{
+			map[string]any{
+				"value_template": []any{map[string]any{"text_template": "{{ body.env.toUpperCase() }}"}},
+				"mapping":        []any{map[string]any{"value": "PROD", "priority": "HIGH"}},
+			},
+		},
+		"services_template": []any{
+			map[string]any{"text_template": "db,{{ body.env }}"},
+		},
+	})
+
+	preview, skipped, err := alertSourceRenderedPreview(d.Get)
+	if err != nil {
+		t.Fatalf("unexpected error rendering the preview: %v", err)
+	}
+	want := map[string]any{
+		"summary_template":                   "[prod] db-1 is down",
+		"link_template.0.link_text_template": "Runbook",
+		"link_template.0.href_template":      "https://runbooks.example.com/prod",
+		"priority_template":                  "PROD",
+		"services_template.0":                "db,prod",
+	}
+	if len(preview) != len(want) {
+		t.Fatalf("expected %d rendered templates, got %v", len(want), preview)
+	}
+	if len(skipped) != 0 {
+		t.Fatalf("expected no skipped templates, got %v", skipped)
+	}
+	for k, v := range want {
+		if preview[k] != v {
+			t.Errorf("rendered_preview[%q] = %v, want %q", k, preview[k], v)
+		}
+	}
+
+	if err := setAlertSourceRenderedPreview(d); err != nil {
+		t.Fatalf("unexpected error setting the preview: %v", err)
+	}
+	if got := d.Get("rendered_preview").(map[string]any)["summary_template"]; got != "[prod] db-1 is down" {
+		t.Fatalf("unexpected rendered summary in state: %v", got)
+	}
+}
+
+func TestAlertSourceRenderedPreview_EmptyWithoutSampleEvent(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertSource().Schema, map[string]any{
+		"name":              "test-alert-source",
+		"integration_type":  "API",
+		"escalation_policy": "1",
+		"summary_template":  []any{map[string]any{"text_template": "{{ summary }}"}},
+	})
+
+	preview, skipped, err := alertSourceRenderedPreview(d.Get)
+	if err != nil {
+		t.Fatalf("unexpected error rendering the preview: %v", err)
+	}
+	if len(preview) != 0 || len(skipped) != 0 {
+		t.Fatalf("expected no preview without a sample event, got %v and %v", preview, skipped)
+	}
+}
+
+func TestAlertSourceRenderedPreview_SkipsUnknownFunctions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAlertSource().Schema, map[string]any{
+		"name":              "test-alert-source",
+		"integration_type":  "API",
+		"escalation_policy": "1",
+		"sample_event":      `{"summary": "db-1 is down", "body": {"env": "prod"}}`,
+		"summary_template":  []any{map[string]any{"text_template": "{{ summary }} in {{ body.env.capitalize() }}"}},
+		"details_template":  []any{map[string]any{"text_template": "{{ summary }}"}},
+	})
+
+	preview, skipped, err := alertSourceRenderedPreview(d.Get)
+	if err != nil {
+		t.Fatalf("unexpected error rendering the preview: %v", err)
+	}
+	if _, ok := preview["summary_template"]; ok {
+		t.Fatalf("expected summary_template to be left out of the preview, got %v", preview)
+	}
+	if preview["details_template"] != "db-1 is down" {
+		t.Fatalf("expected details_template to be rendered, got %v", preview)
+	}
+	if !strings.Contains(skipped["summary_template"], "capitalize") {
+		t.Fatalf("expected summary_template to be skipped for capitalize, got %v", skipped)
+	}
+}
@@ -0,0 +1,275 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// resourceAPIKey manages a key of a service account. The API returns the key
+// only in the response to its creation, so it is written to state once and
+// never read back. Every argument forces a new key, which is how keys are
+// rotated.
+func resourceAPIKey() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"service_account": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric service account id",
+				),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+			},
+			"expires_at": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressEquivalentTimestamp,
+			},
+			"rotation_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		CreateContext: resourceAPIKeyCreate,
+		ReadContext:   resourceAPIKeyRead,
+		DeleteContext: resourceAPIKeyDelete,
+		CustomizeDiff: customizeAPIKey,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAPIKeyImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// validateAPIKeyExpiry checks that a new key does not expire before now.
+func validateAPIKeyExpiry(expiresAt string, now time.Time) error {
+	if expiresAt == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return fmt.Errorf("invalid expires_at %q: %s", expiresAt, err.Error())
+	}
+	if !t.After(now) {
+		return fmt.Errorf("expires_at %s is not in the future, the key would be created expired", expiresAt)
+	}
+	return nil
+}
+
+// apiKeyReplaceKeys are the attributes that replace the key when they change.
+var apiKeyReplaceKeys = []string{"service_account", "name", "expires_at", "rotation_trigger"}
+
+// customizeAPIKey validates the expiry of the keys about to be created, which
+// includes replacing a key when any attribute changes: an expires_at that has
+// passed since the key was created would create the replacement expired.
+func customizeAPIKey(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if diff.Id() != "" && !diff.HasChanges(apiKeyReplaceKeys...) {
+		return nil
+	}
+	if !diffValuesKnown(diff, "expires_at") {
+		return nil
+	}
+	return validateAPIKeyExpiry(diff.Get("expires_at").(string), time.Now())
+}
+
+func buildAPIKey(d *schema.ResourceData) *ilert.APIKey {
+	return &ilert.APIKey{
+		Name:      d.Get("name").(string),
+		ExpiresAt: d.Get("expires_at").(string),
+	}
+}
+
+func resourceAPIKeyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	accountID, err := strconv.ParseInt(d.Get("service_account").(string), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Get("service_account").(string), err))
+	}
+	key := buildAPIKey(d)
+
+	log.Printf("[INFO] Creating api key %s for service account %d", key.Name, accountID)
+
+	result := &ilert.CreateAPIKeyOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		r, err := client.CreateAPIKey(&ilert.CreateAPIKeyInput{ServiceAccountID: ilert.Int64(accountID), APIKey: key})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Creating ilert api key error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for api key to be created, error: %s", err.Error()))
+			}
+			return resource.NonRetryableError(err)
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Creating ilert api key error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if result == nil || result.APIKey == nil {
+		log.Printf("[ERROR] Creating ilert api key error: empty response")
+		return diag.Errorf("api key response is empty")
+	}
+
+	d.SetId(apiKeyID(accountID, result.APIKey.ID))
+	// the key is only returned once, the read keeps it from now on
+	d.Set("key", result.APIKey.Key)
+
+	return resourceAPIKeyRead(ctx, d, m)
+}
+
+func resourceAPIKeyRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	accountID, keyID, err := parseAPIKeyID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Reading api key: %s", d.Id())
+
+	result := &ilert.GetAPIKeyOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetAPIKey(&ilert.GetAPIKeyInput{ServiceAccountID: ilert.Int64(accountID), APIKeyID: ilert.Int64(keyID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] Removing api key %s from state because it no longer exist", d.Id())
+				d.SetId("")
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for api key with id '%s' to be read, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read an api key with ID %s, error: %s", d.Id(), err.Error()))
+		}
+		result = r
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert api key error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	if result == nil || result.APIKey == nil {
+		log.Printf("[ERROR] Reading ilert api key error: empty response")
+		return diag.Errorf("api key response is empty")
+	}
+
+	if err := transformAPIKeyResource(result.APIKey, accountID, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceAPIKeyDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	accountID, keyID, err := parseAPIKeyID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Deleting api key: %s", d.Id())
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err = client.DeleteAPIKey(&ilert.DeleteAPIKeyInput{ServiceAccountID: ilert.Int64(accountID), APIKeyID: ilert.Int64(keyID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for api key with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not delete an api key with id %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Deleting ilert api key error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceAPIKeyImport imports the key without its value, which the API does
+// not return after the creation.
+func resourceAPIKeyImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	accountID, _, err := parseAPIKeyID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("service_account", strconv.FormatInt(accountID, 10))
+	return []*schema.ResourceData{d}, nil
+}
+
+func apiKeyID(accountID, keyID int64) string {
+	return fmt.Sprintf("%d/%d", accountID, keyID)
+}
+
+func parseAPIKeyID(id string) (accountID, keyID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return 0, 0, fmt.Errorf("expected ID in the form '<service_account_id>/<api_key_id>', got %q", id)
+	}
+	accountID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid service_account_id %q in ID %q: %s", parts[0], id, err.Error())
+	}
+	keyID, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid api_key_id %q in ID %q: %s", parts[1], id, err.Error())
+	}
+	return accountID, keyID, nil
+}
+
+func transformAPIKeyResource(key *ilert.APIKey, accountID int64, d *schema.ResourceData) error {
+	d.Set("service_account", strconv.FormatInt(accountID, 10))
+	d.Set("name", key.Name)
+	d.Set("expires_at", key.ExpiresAt)
+	d.Set("created_at", key.CreatedAt)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyIsWriteOnceAndSensitive(t *testing.T) {
+	resourceSchema := resourceAPIKey().Schema
+
+	key, ok := resourceSchema["key"]
+	if !ok {
+		t.Fatalf("schema is missing %q", "key")
+	}
+	if !key.Computed || key.Optional || !key.Sensitive {
+		t.Errorf("expected the key to be computed only and sensitive, got %+v", key)
+	}
+	for name, attributeSchema := range resourceSchema {
+		if !attributeSchema.Computed && !attributeSchema.ForceNew {
+			t.Errorf("expected %q to force a new key", name)
+		}
+	}
+}
+
+func TestValidateAPIKeyExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		expiresAt string
+		err       string
+	}{
+		{"", ""},
+		{"2026-10-20T00:00:00Z", ""},
+		{"2026-10-19T14:30:00+02:00", ""},
+		{"2026-10-19T12:00:00Z", "not in the future"},
+		{"2026-10-19T13:00:00+02:00", "not in the future"},
+		{"2026-10-20", "invalid expires_at"},
+	}
+	for _, tc := range cases {
+		err := validateAPIKeyExpiry(tc.expiresAt, now)
+		if tc.err == "" && err != nil {
+			t.Errorf("validateAPIKeyExpiry(%q): unexpected error %v", tc.expiresAt, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("validateAPIKeyExpiry(%q): expected an error containing %q, got %v", tc.expiresAt, tc.err, err)
+		}
+	}
+}
+
+func TestParseAPIKeyID(t *testing.T) {
+	accountID, keyID, err := parseAPIKeyID(apiKeyID(12, 34))
+	if err != nil || accountID != 12 || keyID != 34 {
+		t.Fatalf("unexpected round trip %d, %d, %v", accountID, keyID, err)
+	}
+	for _, id := range []string{"12", "12/", "/34", "12/34/56", "a/34"} {
+		if _, _, err := parseAPIKeyID(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
@@ -188,7 +188,7 @@ func buildAutomationRule(d *schema.ResourceData) (*ilert.AutomationRule, error)
 }
 
 func resourceAutomationRuleCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	automationRule, err := buildAutomationRule(d)
 	if err != nil {
@@ -227,7 +227,7 @@ func resourceAutomationRuleCreate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceAutomationRuleRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	automationRuleID := d.Id()
 	log.Printf("[DEBUG] Reading automation rule: %s", d.Id())
@@ -290,7 +290,7 @@ func resourceAutomationRuleRead(ctx context.Context, d *schema.ResourceData, m a
 }
 
 func resourceAutomationRuleUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	automationRule, err := buildAutomationRule(d)
 	if err != nil {
@@ -326,7 +326,7 @@ func resourceAutomationRuleUpdate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceAutomationRuleDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	automationRuleID := d.Id()
 	log.Printf("[DEBUG] Deleting automation rule: %s", d.Id())
@@ -351,7 +351,7 @@ func resourceAutomationRuleDelete(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceAutomationRuleExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	automationRuleID := d.Id()
 	log.Printf("[DEBUG] Reading automation rule: %s", d.Id())
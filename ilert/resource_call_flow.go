@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -82,13 +83,33 @@ func resourceCallFlow() *schema.Resource {
 				},
 			},
 			"root_node": {
-				Type:     schema.TypeList,
-				Required: true,
-				MinItems: 1,
-				MaxItems: 1,
-				Elem:     resourceCallFlowRoot(callFlowDepth),
+				Type:         schema.TypeList,
+				Optional:     true,
+				MinItems:     1,
+				MaxItems:     1,
+				Elem:         resourceCallFlowRoot(callFlowDepth),
+				ExactlyOneOf: []string{"root_node", "definition"},
+				Deprecated:   "The root_node blocks are deprecated and nest at most 50 levels deep. Please use definition instead.",
+			},
+			"definition": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateCallFlowDefinition,
+				DiffSuppressFunc: suppressEquivalentCallFlowDefinition,
+			},
+			"diagram_mermaid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"diagram_dot": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
+		CustomizeDiff: customdiff.All(
+			customizeCallFlowPhoneTargets,
+			customizeFlowDiagrams(callFlowDefinitionSchema(), "call_flow"),
+		),
 		CreateContext: resourceCallFlowCreate,
 		ReadContext:   resourceCallFlowRead,
 		UpdateContext: resourceCallFlowUpdate,
@@ -241,8 +262,9 @@ func resourceCallFlowNodeMetadata() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"target": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressEquivalentCallFlowTarget,
 						},
 						"type": {
 							Type:         schema.TypeString,
@@ -451,6 +473,16 @@ func buildCallFlow(d *schema.ResourceData) (*ilert.CallFlow, error) {
 			}
 			callFlow.RootNode = node
 		}
+	} else if val, ok := d.GetOk("definition"); ok {
+		rn, err := parseCallFlowDefinition(val.(string))
+		if err != nil {
+			return nil, err
+		}
+		node, err := buildCallFlowNodeFromMap(rn)
+		if err != nil {
+			return nil, err
+		}
+		callFlow.RootNode = node
 	}
 
 	if err := validateCallFlowNodeRouteCallStyle(callFlow.RootNode); err != nil {
@@ -663,9 +695,16 @@ func buildCallFlowNodeFromMap(rn map[string]any) (*ilert.CallFlowNode, error) {
 				}
 				enr.InformationTypes = infos
 			}
-			if m, ok := ev["sources"].(map[string]any); ok && len(m) > 0 {
-				srcs := make([]ilert.CallFlowNodeMetadataEnrichmentSource, 0, len(m))
+			// sources is a list in the schema, but is flattened keyed by id.
+			sources, _ := ev["sources"].([]any)
+			if m, ok := ev["sources"].(map[string]any); ok {
 				for _, val := range m {
+					sources = append(sources, val)
+				}
+			}
+			if len(sources) > 0 {
+				srcs := make([]ilert.CallFlowNodeMetadataEnrichmentSource, 0, len(sources))
+				for _, val := range sources {
 					if val == nil {
 						continue
 					}
@@ -719,7 +758,7 @@ func buildCallFlowNodeFromMap(rn map[string]any) (*ilert.CallFlowNode, error) {
 }
 
 func resourceCallFlowCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	CallFlow, err := buildCallFlow(d)
 	if err != nil {
@@ -759,7 +798,7 @@ func resourceCallFlowCreate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceCallFlowRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	CallFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -804,7 +843,7 @@ func resourceCallFlowRead(ctx context.Context, d *schema.ResourceData, m any) di
 }
 
 func resourceCallFlowUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	CallFlow, err := buildCallFlow(d)
 	if err != nil {
@@ -840,7 +879,7 @@ func resourceCallFlowUpdate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceCallFlowDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	CallFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -869,7 +908,7 @@ func resourceCallFlowDelete(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceCallFlowExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	CallFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -944,6 +983,12 @@ func transformCallFlowResource(callFlow *ilert.CallFlowOutput, d *schema.Resourc
 	if err != nil {
 		return fmt.Errorf("[ERROR] Error flattening root node: %s", err.Error())
 	}
+	if err := setFlowDiagrams(d, rootNode, callFlowDefinitionSchema(), "call_flow"); err != nil {
+		return err
+	}
+	if val, ok := d.GetOk("definition"); ok {
+		return setCallFlowDefinition(d, val.(string), rootNode)
+	}
 	if err := d.Set("root_node", rootNode); err != nil {
 		return fmt.Errorf("[ERROR] Error setting root node: %s", err.Error())
 	}
@@ -951,6 +996,30 @@ func transformCallFlowResource(callFlow *ilert.CallFlowOutput, d *schema.Resourc
 	return nil
 }
 
+// setCallFlowDefinition stores the call flow read from the API as definition,
+// for call flows managed as a document. A definition that describes the same
+// call flow is kept as written, so the state keeps the user's formatting.
+func setCallFlowDefinition(d *schema.ResourceData, current string, rootNode []any) error {
+	if len(rootNode) == 0 || rootNode[0] == nil {
+		d.Set("definition", "")
+		return nil
+	}
+	definition, err := renderCallFlowDefinition(rootNode[0].(map[string]any))
+	if err != nil {
+		return fmt.Errorf("[ERROR] Error rendering call flow definition: %s", err.Error())
+	}
+	if canonical, err := canonicalCallFlowDefinition(current); err == nil && canonical == definition {
+		definition = current
+	}
+	if err := d.Set("definition", definition); err != nil {
+		return fmt.Errorf("[ERROR] Error setting call flow definition: %s", err.Error())
+	}
+	if err := d.Set("root_node", []any{}); err != nil {
+		return fmt.Errorf("[ERROR] Error setting root node: %s", err.Error())
+	}
+	return nil
+}
+
 func flattenCallFlowNodeOutput(node *ilert.CallFlowNodeOutput) ([]any, error) {
 	if node == nil {
 		return make([]any, 0), nil
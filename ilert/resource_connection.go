@@ -1063,7 +1063,7 @@ func buildConnection(d *schema.ResourceData) (*ilert.Connection, error) {
 }
 
 func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connection, err := buildConnection(d)
 	if err != nil {
@@ -1108,7 +1108,7 @@ func resourceConnectionCreate(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceConnectionRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectionID := d.Id()
 	log.Printf("[DEBUG] Reading connection: %s", d.Id())
@@ -1246,7 +1246,7 @@ func resourceConnectionRead(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connection, err := buildConnection(d)
 	if err != nil {
@@ -1278,7 +1278,7 @@ func resourceConnectionUpdate(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceConnectionDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectionID := d.Id()
 	log.Printf("[DEBUG] Deleting connection: %s", d.Id())
@@ -1304,7 +1304,7 @@ func resourceConnectionDelete(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceConnectionExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectionID := d.Id()
 	log.Printf("[DEBUG] Reading connection: %s", d.Id())
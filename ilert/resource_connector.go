@@ -469,7 +469,7 @@ func buildConnector(d *schema.ResourceData) (*ilert.Connector, error) {
 }
 
 func resourceConnectorCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connector, err := buildConnector(d)
 	if err != nil {
@@ -509,7 +509,7 @@ func resourceConnectorCreate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceConnectorRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectorID := d.Id()
 	log.Printf("[DEBUG] Reading connector: %s", d.Id())
@@ -552,7 +552,7 @@ func resourceConnectorRead(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceConnectorUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connector, err := buildConnector(d)
 	if err != nil {
@@ -584,7 +584,7 @@ func resourceConnectorUpdate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceConnectorDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectorID := d.Id()
 	log.Printf("[DEBUG] Deleting connector: %s", d.Id())
@@ -608,7 +608,7 @@ func resourceConnectorDelete(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceConnectorExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	connectorID := d.Id()
 	log.Printf("[DEBUG] Reading connector: %s", d.Id())
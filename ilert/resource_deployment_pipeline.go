@@ -220,7 +220,7 @@ func buildDeploymentPipeline(d *schema.ResourceData) (*ilert.DeploymentPipeline,
 }
 
 func resourceDeploymentPipelineCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	deploymentPipeline, err := buildDeploymentPipeline(d)
 	if err != nil {
@@ -261,7 +261,7 @@ func resourceDeploymentPipelineCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceDeploymentPipelineRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	deploymentPipelineID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -308,7 +308,7 @@ func resourceDeploymentPipelineRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceDeploymentPipelineUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	deploymentPipeline, err := buildDeploymentPipeline(d)
 	if err != nil {
@@ -347,7 +347,7 @@ func resourceDeploymentPipelineUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceDeploymentPipelineDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	deploymentPipelineID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -376,7 +376,7 @@ func resourceDeploymentPipelineDelete(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceDeploymentPipelineExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	deploymentPipelineID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -0,0 +1,561 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// directorySyncPlanTimeout bounds the account lookups made at plan time, where
+// no resource timeout applies.
+const directorySyncPlanTimeout = 5 * time.Minute
+
+func resourceDirectorySync() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"email": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"first_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"last_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "USER",
+							ValidateFunc: validation.StringInSlice([]string{
+								"ADMIN",
+								"USER",
+								"RESPONDER",
+								"STAKEHOLDER",
+								"GUEST",
+								"VIEWER",
+							}, false),
+						},
+						"team": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"role": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      ilert.TeamMemberRoles.Responder,
+										ValidateFunc: validation.StringInSlice(ilert.TeamMemberRolesAll, false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"send_no_invitation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"dry_run": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"delete_removed_users": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"max_deletions": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"changes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"synced_user": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"first_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"team": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"role": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		CreateContext: resourceDirectorySyncCreate,
+		ReadContext:   resourceDirectorySyncRead,
+		UpdateContext: resourceDirectorySyncUpdate,
+		DeleteContext: resourceDirectorySyncDelete,
+		CustomizeDiff: customizeDirectorySync,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// customizeDirectorySync compares the listed users with the account at plan
+// time, so the plan shows every change as an entry of changes.
+func customizeDirectorySync(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diffValuesKnown(diff, "user") {
+		return diff.SetNewComputed("changes")
+	}
+	desired, err := expandDirectorySyncUsers(diff.Get("user"))
+	if err != nil {
+		return err
+	}
+	previous, err := expandDirectorySyncUsers(diff.Get("synced_user"))
+	if err != nil {
+		return err
+	}
+	client := providerClient(m)
+	if client == nil {
+		return nil
+	}
+
+	changes, _, err := planDirectorySyncChanges(ctx, client, desired, previous, diff.Get("delete_removed_users").(bool), directorySyncPlanTimeout)
+	if err != nil {
+		return err
+	}
+	if err := checkDirectorySyncDeletions(changes, diff.Get("max_deletions").(int)); err != nil {
+		return err
+	}
+	planned := flattenDirectorySyncChanges(changes)
+	if diff.Get("dry_run").(bool) {
+		return diff.SetNew("changes", planned)
+	}
+	// plans a sync until one succeeded, even when the users are unchanged
+	if err := diff.SetNew("synced_user", flattenDirectorySyncUsers(desired)); err != nil {
+		return err
+	}
+	// the apply keeps the changes it made, so the plan only sets changes when
+	// there are new ones, and a plan without changes has no diff
+	if len(planned) == 0 {
+		return nil
+	}
+	if o, _ := diff.GetChange("changes"); reflect.DeepEqual(o, planned) {
+		// the same changes as the last apply would not show up as a diff
+		return diff.SetNewComputed("changes")
+	}
+	return diff.SetNew("changes", planned)
+}
+
+func resourceDirectorySyncCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	log.Printf("[INFO] Creating directory sync")
+
+	d.SetId(resource.UniqueId())
+	return applyDirectorySync(ctx, d, m, d.Timeout(schema.TimeoutCreate))
+}
+
+func resourceDirectorySyncRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	// The account is compared with the listed users at plan time, see
+	// customizeDirectorySync, so there is nothing to refresh here.
+	log.Printf("[DEBUG] Reading directory sync: %s", d.Id())
+	return nil
+}
+
+func resourceDirectorySyncUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	log.Printf("[DEBUG] Updating directory sync: %s", d.Id())
+
+	return applyDirectorySync(ctx, d, m, d.Timeout(schema.TimeoutUpdate))
+}
+
+func resourceDirectorySyncDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting directory sync %s, the synced users are left in the account", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// applyDirectorySync compares the listed users with the account again, as it
+// may have changed since the plan, and makes the changes unless dry_run is set.
+// synced_user, which the next sync removes users by, only becomes the listed
+// users once every change succeeded.
+func applyDirectorySync(ctx context.Context, d *schema.ResourceData, m any, timeout time.Duration) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	desired, err := expandDirectorySyncUsers(d.Get("user"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	o, _ := d.GetChange("synced_user")
+	previous, err := expandDirectorySyncUsers(o)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// the plan sets synced_user to the listed users, keep the previous ones
+	// in case the sync fails
+	if err := d.Set("synced_user", flattenDirectorySyncUsers(previous)); err != nil {
+		return diag.Errorf("[ERROR] Error setting synced users: %s", err.Error())
+	}
+
+	changes, accounts, err := planDirectorySyncChanges(ctx, client, desired, previous, d.Get("delete_removed_users").(bool), timeout)
+	if err != nil {
+		log.Printf("[ERROR] Planning directory sync error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if err := checkDirectorySyncDeletions(changes, d.Get("max_deletions").(int)); err != nil {
+		return diag.FromErr(err)
+	}
+	if planned, ok := directorySyncPlannedChanges(d); ok && !reflect.DeepEqual(planned, flattenDirectorySyncChanges(changes)) {
+		return diag.Errorf("the account changed since the plan, which showed %d change(s) where %d are needed now. Plan again to review them", len(planned), len(changes))
+	}
+
+	if d.Get("dry_run").(bool) {
+		log.Printf("[INFO] Directory sync is a dry run, skipping %d change(s)", len(changes))
+		if err := d.Set("changes", flattenDirectorySyncChanges(changes)); err != nil {
+			return diag.Errorf("[ERROR] Error setting changes: %s", err.Error())
+		}
+		return nil
+	}
+
+	if err := applyDirectorySyncChanges(ctx, client, changes, accounts, d.Get("send_no_invitation").(bool), timeout); err != nil {
+		log.Printf("[ERROR] Applying directory sync error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	// keeps the changes of the last apply that made any, as planned
+	if len(changes) > 0 {
+		if err := d.Set("changes", flattenDirectorySyncChanges(changes)); err != nil {
+			return diag.Errorf("[ERROR] Error setting changes: %s", err.Error())
+		}
+	}
+	if err := d.Set("synced_user", flattenDirectorySyncUsers(desired)); err != nil {
+		return diag.Errorf("[ERROR] Error setting synced users: %s", err.Error())
+	}
+	return nil
+}
+
+// directorySyncPlannedChanges returns the changes the plan showed, see
+// customizeDirectorySync, or false when the plan left them to the apply.
+func directorySyncPlannedChanges(d *schema.ResourceData) ([]any, bool) {
+	plan := d.GetRawPlan()
+	if plan.IsNull() || !plan.IsKnown() || !plan.GetAttr("changes").IsKnown() {
+		return nil, false
+	}
+	o, n := d.GetChange("changes")
+	if !d.Get("dry_run").(bool) && reflect.DeepEqual(o, n) {
+		// the plan keeps the changes of the last apply when there are no new ones
+		return []any{}, true
+	}
+	planned, _ := n.([]any)
+	if planned == nil {
+		planned = []any{}
+	}
+	return planned, true
+}
+
+func checkDirectorySyncDeletions(changes []directorySyncChange, maxDeletions int) error {
+	if n := countDirectorySyncRemovals(changes); n > maxDeletions {
+		return fmt.Errorf("directory sync would delete, deactivate or remove from their teams %d users that are no longer listed, more than max_deletions (%d). Raise max_deletions if this is intended", n, maxDeletions)
+	}
+	return nil
+}
+
+// planDirectorySyncChanges looks up the account users and teams of the desired
+// and previous users and plans the changes, see planDirectorySync. It lists the
+// users of the account once, one request per 100 users, and reads each team
+// of the listed users once.
+func planDirectorySyncChanges(ctx context.Context, client *ilert.Client, desired, previous []directorySyncUser, deleteRemoved bool, timeout time.Duration) ([]directorySyncChange, map[string]*ilert.User, error) {
+	accounts, err := listDirectorySyncAccounts(ctx, client, desired, previous, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teams := make(map[int64]*ilert.Team)
+	for _, teamID := range directorySyncTeamIDs(desired, previous) {
+		team, err := getTeamForMembership(ctx, client, teamID, timeout)
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil, nil, fmt.Errorf("team %d does not exist", teamID)
+			}
+			return nil, nil, err
+		}
+		teams[teamID] = team
+	}
+
+	return planDirectorySync(desired, previous, accounts, teams, deleteRemoved), accounts, nil
+}
+
+// listDirectorySyncAccounts returns the account users of the desired and
+// previous users by email, nil for those without an account.
+func listDirectorySyncAccounts(ctx context.Context, client *ilert.Client, desired, previous []directorySyncUser, timeout time.Duration) (map[string]*ilert.User, error) {
+	byEmail := make(map[string]*ilert.User)
+	err := listUserReferences(ctx, timeout, "users", func(start int) (int, error) {
+		r, err := client.GetUsers(&ilert.GetUsersInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(userReferencesPageSize)})
+		if err != nil || r == nil {
+			return 0, err
+		}
+		for _, user := range r.Users {
+			if user != nil {
+				byEmail[strings.ToLower(strings.TrimSpace(user.Email))] = user
+			}
+		}
+		return len(r.Users), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[string]*ilert.User)
+	for _, users := range [][]directorySyncUser{desired, previous} {
+		for _, user := range users {
+			accounts[user.email] = byEmail[user.email]
+		}
+	}
+	return accounts, nil
+}
+
+// applyDirectorySyncChanges makes the planned changes in the order of
+// directorySyncActionOrder. Team changes are applied per team under the lock
+// ilert_team_membership takes.
+func applyDirectorySyncChanges(ctx context.Context, client *ilert.Client, changes []directorySyncChange, accounts map[string]*ilert.User, sendNoInvitation bool, timeout time.Duration) error {
+	created := make(map[string]int64)
+	userID := func(change directorySyncChange) int64 {
+		if change.userID != 0 {
+			return change.userID
+		}
+		return created[change.user.email]
+	}
+
+	teamChanges := make(map[int64][]directorySyncChange)
+	teamIDs := make([]int64, 0)
+	for _, action := range directorySyncActionOrder {
+		for _, change := range changes {
+			if change.action != action {
+				continue
+			}
+			switch action {
+			case directorySyncCreate:
+				id, err := createDirectorySyncUser(ctx, client, change.user, sendNoInvitation, timeout)
+				if err != nil {
+					return err
+				}
+				created[change.user.email] = id
+			case directorySyncUpdate:
+				if err := updateDirectorySyncUser(ctx, client, change.user, accounts[change.user.email], timeout); err != nil {
+					return err
+				}
+			case directorySyncAddToTeam, directorySyncUpdateTeamRole, directorySyncRemoveFromTeam:
+				if _, ok := teamChanges[change.teamID]; !ok {
+					teamIDs = append(teamIDs, change.teamID)
+				}
+				teamChanges[change.teamID] = append(teamChanges[change.teamID], change)
+			case directorySyncDeactivate:
+				if err := deactivateDirectorySyncUser(ctx, client, change.user, accounts[change.user.email], timeout); err != nil {
+					return err
+				}
+			case directorySyncDelete:
+				if err := deleteDirectorySyncUser(ctx, client, change.userID, timeout); err != nil {
+					return err
+				}
+			}
+		}
+		if action == directorySyncRemoveFromTeam {
+			// team changes go before removed users are deactivated or
+			// deleted
+			for _, teamID := range teamIDs {
+				if err := applyDirectorySyncTeamChanges(ctx, client, teamID, teamChanges[teamID], userID, timeout); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func applyDirectorySyncTeamChanges(ctx context.Context, client *ilert.Client, teamID int64, changes []directorySyncChange, userID func(directorySyncChange) int64, timeout time.Duration) error {
+	teamKey := strconv.FormatInt(teamID, 10)
+	teamMemberLock.Lock(teamKey)
+	defer teamMemberLock.Unlock(teamKey)
+
+	team, err := getTeamForMembership(ctx, client, teamID, timeout)
+	if err != nil {
+		return err
+	}
+	members := append(make([]ilert.TeamMember, 0, len(team.Members)+len(changes)), team.Members...)
+	for _, change := range changes {
+		id := userID(change)
+		log.Printf("[INFO] Directory sync: %s", change.String())
+		switch change.action {
+		case directorySyncAddToTeam, directorySyncUpdateTeamRole:
+			if member := findTeamMember(members, id); member != nil {
+				member.Role = change.role
+				continue
+			}
+			members = append(members, ilert.TeamMember{User: ilert.User{ID: id}, Role: change.role})
+		case directorySyncRemoveFromTeam:
+			for i := range members {
+				if members[i].User.ID == id {
+					members = append(members[:i], members[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return updateTeamMembers(ctx, client, teamID, team, members, timeout)
+}
+
+func createDirectorySyncUser(ctx context.Context, client *ilert.Client, user directorySyncUser, sendNoInvitation bool, timeout time.Duration) (int64, error) {
+	log.Printf("[INFO] Directory sync: create user %s", user.email)
+
+	input := &ilert.CreateUserInput{
+		User: &ilert.User{
+			Email:     user.email,
+			FirstName: user.firstName,
+			LastName:  user.lastName,
+			Role:      user.role,
+		},
+	}
+	if sendNoInvitation {
+		input.SendNoInvitation = Bool(true)
+	}
+
+	result := &ilert.CreateUserOutput{}
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		r, err := client.CreateUser(input)
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for user %s to be created, error: %s", user.email, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not create user %s, error: %s", user.email, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.User == nil {
+		return 0, fmt.Errorf("user response is empty")
+	}
+	return result.User.ID, nil
+}
+
+func updateDirectorySyncUser(ctx context.Context, client *ilert.Client, user directorySyncUser, account *ilert.User, timeout time.Duration) error {
+	log.Printf("[INFO] Directory sync: update user %s", user.email)
+
+	// start from the account user, so attributes the directory does not list,
+	// e.g. the timezone, are kept
+	update := *account
+	update.FirstName = user.firstName
+	update.LastName = user.lastName
+	update.Role = user.role
+
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		_, err := client.UpdateUser(&ilert.UpdateUserInput{User: &update, UserID: ilert.Int64(account.ID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for user %s to be updated, error: %s", user.email, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not update user %s, error: %s", user.email, err.Error()))
+		}
+		return nil
+	})
+}
+
+// deactivateDirectorySyncUser gives a user no longer listed by the directory
+// directorySyncDeactivatedRole, keeping the user and what references it.
+func deactivateDirectorySyncUser(ctx context.Context, client *ilert.Client, user directorySyncUser, account *ilert.User, timeout time.Duration) error {
+	log.Printf("[INFO] Directory sync: deactivate user %s", user.email)
+
+	update := *account
+	update.Role = directorySyncDeactivatedRole
+
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		_, err := client.UpdateUser(&ilert.UpdateUserInput{User: &update, UserID: ilert.Int64(account.ID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for user %s to be deactivated, error: %s", user.email, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not deactivate user %s, error: %s", user.email, err.Error()))
+		}
+		return nil
+	})
+}
+
+func deleteDirectorySyncUser(ctx context.Context, client *ilert.Client, userID int64, timeout time.Duration) error {
+	log.Printf("[INFO] Directory sync: delete user %d", userID)
+
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		_, err := client.DeleteUser(&ilert.DeleteUserInput{UserID: ilert.Int64(userID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for user %d to be deleted, error: %s", userID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not delete user %d, error: %s", userID, err.Error()))
+		}
+		return nil
+	})
+}
@@ -149,11 +149,14 @@ func resourceEscalationPolicy() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"lint_findings":       lintFindingsSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		CreateContext: resourceEscalationPolicyCreate,
 		ReadContext:   resourceEscalationPolicyRead,
 		UpdateContext: resourceEscalationPolicyUpdate,
 		DeleteContext: resourceEscalationPolicyDelete,
+		CustomizeDiff: customizeEscalationPolicyLint,
 		Exists:        resourceEscalationPolicyExists,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -329,7 +332,7 @@ func buildEscalationPolicy(d *schema.ResourceData) (*ilert.EscalationPolicy, err
 }
 
 func resourceEscalationPolicyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	escalationPolicy, err := buildEscalationPolicy(d)
 	if err != nil {
@@ -365,11 +368,15 @@ func resourceEscalationPolicyCreate(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(strconv.FormatInt(result.EscalationPolicy.ID, 10))
 
-	return resourceEscalationPolicyRead(ctx, d, m)
+	diags := resourceEscalationPolicyRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, escalationPolicyLintWarnings(ctx, d, m)...)
 }
 
 func resourceEscalationPolicyRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	escalationPolicyID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -416,7 +423,7 @@ func resourceEscalationPolicyRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceEscalationPolicyUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	escalationPolicy, err := buildEscalationPolicy(d)
 	if err != nil {
@@ -448,19 +455,27 @@ func resourceEscalationPolicyUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	return resourceEscalationPolicyRead(ctx, d, m)
+	diags := resourceEscalationPolicyRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, escalationPolicyLintWarnings(ctx, d, m)...)
 }
 
 func resourceEscalationPolicyDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	escalationPolicyID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		log.Printf("[ERROR] Could not parse escalation policy id %s", err.Error())
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
+	if diags := checkDeletion(d, "escalation_policy"); diags.HasError() {
+		return diags
+	}
 	log.Printf("[DEBUG] Deleting escalation policy: %s", d.Id())
 
+	var apiErr error
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err = client.DeleteEscalationPolicy(&ilert.DeleteEscalationPolicyInput{EscalationPolicyID: ilert.Int64(escalationPolicyID)})
 		if err != nil {
@@ -468,13 +483,14 @@ func resourceEscalationPolicyDelete(ctx context.Context, d *schema.ResourceData,
 				time.Sleep(2 * time.Second)
 				return resource.RetryableError(fmt.Errorf("waiting for escalation policy with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
 			}
+			apiErr = err
 			return resource.NonRetryableError(fmt.Errorf("could not delete an escalation policy with ID %s, error: %s", d.Id(), err.Error()))
 		}
 		return nil
 	})
 	if err != nil {
 		log.Printf("[ERROR] Deleting ilert escalation policy error %s", err.Error())
-		return diag.FromErr(err)
+		return deletionError(ctx, d, client, "escalation_policy", escalationPolicyID, apiErr, err)
 	}
 
 	d.SetId("")
@@ -482,7 +498,7 @@ func resourceEscalationPolicyDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceEscalationPolicyExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	escalationPolicyID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
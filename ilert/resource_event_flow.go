@@ -46,13 +46,30 @@ func resourceEventFlow() *schema.Resource {
 				},
 			},
 			"root_node": {
-				Type:     schema.TypeList,
-				Required: true,
-				MinItems: 1,
-				MaxItems: 1,
-				Elem:     resourceEventFlowRoot(eventFlowDepth),
+				Type:         schema.TypeList,
+				Optional:     true,
+				MinItems:     1,
+				MaxItems:     1,
+				Elem:         resourceEventFlowRoot(eventFlowDepth),
+				ExactlyOneOf: []string{"root_node", "definition"},
+				Deprecated:   "The root_node blocks are deprecated and nest at most 50 levels deep. Please use definition instead.",
+			},
+			"definition": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateEventFlowDefinition,
+				DiffSuppressFunc: suppressEquivalentEventFlowDefinition,
+			},
+			"diagram_mermaid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"diagram_dot": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
+		CustomizeDiff: customizeFlowDiagrams(eventFlowDefinitionSchema(), "event_flow"),
 		CreateContext: resourceEventFlowCreate,
 		ReadContext:   resourceEventFlowRead,
 		UpdateContext: resourceEventFlowUpdate,
@@ -325,6 +342,16 @@ func buildEventFlow(d *schema.ResourceData) (*ilert.EventFlow, error) {
 			}
 			eventFlow.RootNode = node
 		}
+	} else if val, ok := d.GetOk("definition"); ok {
+		rn, err := parseEventFlowDefinition(val.(string))
+		if err != nil {
+			return nil, err
+		}
+		node, err := buildEventFlowNodeFromMap(rn)
+		if err != nil {
+			return nil, err
+		}
+		eventFlow.RootNode = node
 	}
 
 	return eventFlow, nil
@@ -504,7 +531,7 @@ func buildEventFlowNodeFromMap(rn map[string]any) (*ilert.EventFlowNode, error)
 }
 
 func resourceEventFlowCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	EventFlow, err := buildEventFlow(d)
 	if err != nil {
@@ -544,7 +571,7 @@ func resourceEventFlowCreate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceEventFlowRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	EventFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -589,7 +616,7 @@ func resourceEventFlowRead(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceEventFlowUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	EventFlow, err := buildEventFlow(d)
 	if err != nil {
@@ -625,7 +652,7 @@ func resourceEventFlowUpdate(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceEventFlowDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	EventFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -654,7 +681,7 @@ func resourceEventFlowDelete(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceEventFlowExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	EventFlowID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -704,6 +731,12 @@ func transformEventFlowResource(eventFlow *ilert.EventFlowOutput, d *schema.Reso
 	if err != nil {
 		return fmt.Errorf("[ERROR] Error flattening root node: %s", err.Error())
 	}
+	if err := setFlowDiagrams(d, rootNode, eventFlowDefinitionSchema(), "event_flow"); err != nil {
+		return err
+	}
+	if val, ok := d.GetOk("definition"); ok {
+		return setEventFlowDefinition(d, val.(string), rootNode)
+	}
 	if err := d.Set("root_node", rootNode); err != nil {
 		return fmt.Errorf("[ERROR] Error setting root node: %s", err.Error())
 	}
@@ -711,6 +744,30 @@ func transformEventFlowResource(eventFlow *ilert.EventFlowOutput, d *schema.Reso
 	return nil
 }
 
+// setEventFlowDefinition stores the event flow read from the API as
+// definition, for event flows managed as a document. A definition that
+// describes the same event flow is kept as written.
+func setEventFlowDefinition(d *schema.ResourceData, current string, rootNode []any) error {
+	if len(rootNode) == 0 || rootNode[0] == nil {
+		d.Set("definition", "")
+		return nil
+	}
+	definition, err := renderEventFlowDefinition(rootNode[0].(map[string]any))
+	if err != nil {
+		return fmt.Errorf("[ERROR] Error rendering event flow definition: %s", err.Error())
+	}
+	if canonical, err := canonicalEventFlowDefinition(current); err == nil && canonical == definition {
+		definition = current
+	}
+	if err := d.Set("definition", definition); err != nil {
+		return fmt.Errorf("[ERROR] Error setting event flow definition: %s", err.Error())
+	}
+	if err := d.Set("root_node", []any{}); err != nil {
+		return fmt.Errorf("[ERROR] Error setting root node: %s", err.Error())
+	}
+	return nil
+}
+
 func flattenEventFlowNodeOutput(node *ilert.EventFlowNodeOutput) ([]any, error) {
 	if node == nil {
 		return make([]any, 0), nil
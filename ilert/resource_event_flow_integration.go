@@ -63,7 +63,7 @@ func buildEventFlowIntegration(d *schema.ResourceData) *ilert.EventFlowIntegrati
 }
 
 func resourceEventFlowIntegrationCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	integration := buildEventFlowIntegration(d)
 
@@ -99,7 +99,7 @@ func resourceEventFlowIntegrationCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceEventFlowIntegrationRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	integrationID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -141,7 +141,7 @@ func resourceEventFlowIntegrationRead(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceEventFlowIntegrationUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	integration := buildEventFlowIntegration(d)
 
@@ -173,7 +173,7 @@ func resourceEventFlowIntegrationUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceEventFlowIntegrationDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	integrationID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -202,7 +202,7 @@ func resourceEventFlowIntegrationDelete(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceEventFlowIntegrationExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	integrationID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
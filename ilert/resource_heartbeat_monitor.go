@@ -161,7 +161,7 @@ func buildHeartbeatMonitor(d *schema.ResourceData) (*ilert.HeartbeatMonitor, err
 }
 
 func resourceHeartbeatMonitorCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	heartbeatMonitor, err := buildHeartbeatMonitor(d)
 	if err != nil {
@@ -202,7 +202,7 @@ func resourceHeartbeatMonitorCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceHeartbeatMonitorRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	heartbeatMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -249,7 +249,7 @@ func resourceHeartbeatMonitorRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceHeartbeatMonitorUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	heartbeatMonitor, err := buildHeartbeatMonitor(d)
 	if err != nil {
@@ -288,7 +288,7 @@ func resourceHeartbeatMonitorUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceHeartbeatMonitorDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	heartbeatMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -317,7 +317,7 @@ func resourceHeartbeatMonitorDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceHeartbeatMonitorExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	heartbeatMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
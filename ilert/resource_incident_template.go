@@ -127,7 +127,7 @@ func buildIncidentTemplate(d *schema.ResourceData) (*ilert.IncidentTemplate, err
 }
 
 func resourceIncidentTemplateCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	incidentTemplate, err := buildIncidentTemplate(d)
 	if err != nil {
@@ -166,7 +166,7 @@ func resourceIncidentTemplateCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceIncidentTemplateRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	incidentTemplateID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -212,7 +212,7 @@ func resourceIncidentTemplateRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceIncidentTemplateUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	incidentTemplate, err := buildIncidentTemplate(d)
 	if err != nil {
@@ -248,7 +248,7 @@ func resourceIncidentTemplateUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceIncidentTemplateDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	incidentTemplateID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -277,7 +277,7 @@ func resourceIncidentTemplateDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceIncidentTemplateExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	incidentTemplateID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
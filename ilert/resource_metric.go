@@ -209,7 +209,7 @@ func buildMetric(d *schema.ResourceData) (*ilert.Metric, error) {
 }
 
 func resourceMetricCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metric, err := buildMetric(d)
 	if err != nil {
@@ -249,7 +249,7 @@ func resourceMetricCreate(ctx context.Context, d *schema.ResourceData, m any) di
 }
 
 func resourceMetricRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -295,7 +295,7 @@ func resourceMetricRead(ctx context.Context, d *schema.ResourceData, m any) diag
 }
 
 func resourceMetricUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metric, err := buildMetric(d)
 	if err != nil {
@@ -331,7 +331,7 @@ func resourceMetricUpdate(ctx context.Context, d *schema.ResourceData, m any) di
 }
 
 func resourceMetricDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -360,7 +360,7 @@ func resourceMetricDelete(ctx context.Context, d *schema.ResourceData, m any) di
 }
 
 func resourceMetricExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -397,6 +397,7 @@ func resourceMetricExists(d *schema.ResourceData, m any) (bool, error) {
 
 func transformMetricResource(metric *ilert.Metric, d *schema.ResourceData) error {
 	d.Set("name", metric.Name)
+	d.Set("description", metric.Description)
 	d.Set("aggregation_type", metric.AggregationType)
 	d.Set("display_type", metric.DisplayType)
 	d.Set("interpolate_gaps", metric.InterpolateGaps)
@@ -185,7 +185,7 @@ func buildMetricDataSource(d *schema.ResourceData) (*ilert.MetricDataSource, err
 }
 
 func resourceMetricDataSourceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricDataSource, err := buildMetricDataSource(d)
 	if err != nil {
@@ -224,7 +224,7 @@ func resourceMetricDataSourceCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceMetricDataSourceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricDataSourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -270,7 +270,7 @@ func resourceMetricDataSourceRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceMetricDataSourceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricdatasource, err := buildMetricDataSource(d)
 	if err != nil {
@@ -306,7 +306,7 @@ func resourceMetricDataSourceUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceMetricDataSourceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricdatasourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -335,7 +335,7 @@ func resourceMetricDataSourceDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceMetricDataSourceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	metricdatasourceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -0,0 +1,440 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// notificationPolicyPlanTimeout bounds the lookups made at plan time, where no
+// resource timeout applies.
+const notificationPolicyPlanTimeout = 5 * time.Minute
+
+var notificationPolicyRuleKeys = []string{"alert_preference", "duty_preference", "update_preference", "subscription_preference"}
+
+func resourceNotificationPolicy() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+			},
+			"alert_preference":        notificationPreferenceSchema(notificationPreferenceAlert, false),
+			"duty_preference":         notificationPreferenceSchema(notificationPreferenceDuty, false),
+			"update_preference":       notificationPreferenceSchema(notificationPreferenceUpdate, false),
+			"subscription_preference": notificationPreferenceSchema(notificationPreferenceSubscription, false),
+			"users": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"users", "teams"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringMatch(
+						regexp.MustCompile(`^[0-9]+$`),
+						"must be a numeric user id",
+					),
+				},
+			},
+			"teams": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"users", "teams"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringMatch(
+						regexp.MustCompile(`^[0-9]+$`),
+						"must be a numeric team id",
+					),
+				},
+			},
+			"applied_users": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"changes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"unsatisfied": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"owned_preferences": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		CreateContext: resourceNotificationPolicyCreate,
+		ReadContext:   resourceNotificationPolicyRead,
+		UpdateContext: resourceNotificationPolicyUpdate,
+		DeleteContext: resourceNotificationPolicyDelete,
+		CustomizeDiff: customizeNotificationPolicy,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+// customizeNotificationPolicy compares the rules with the preferences of the
+// targeted users at plan time, so the plan shows every preference the policy
+// creates or deletes as an entry of changes, and users drifting from the
+// policy show up as a diff.
+func customizeNotificationPolicy(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	for _, key := range append([]string{"users", "teams"}, notificationPolicyRuleKeys...) {
+		if !diffValuesKnown(diff, key) {
+			for _, computed := range []string{"applied_users", "changes", "unsatisfied", "owned_preferences"} {
+				if err := diff.SetNewComputed(computed); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	rules := expandNotificationPreferences(diff.Get)
+	if err := validateNotificationPolicyRules(rules); err != nil {
+		return err
+	}
+	client := providerClient(m)
+	if client == nil {
+		return nil
+	}
+
+	previousTargets, err := expandNotificationPolicyUsers(diff.Get("applied_users"))
+	if err != nil {
+		return err
+	}
+	owned, err := expandNotificationPolicyOwned(diff.Get("owned_preferences"))
+	if err != nil {
+		return err
+	}
+	targets, plans, err := planNotificationPolicyChanges(ctx, client, diff.Get("users"), diff.Get("teams"), rules, previousTargets, owned, notificationPolicyPlanTimeout)
+	if err != nil {
+		return err
+	}
+
+	unsatisfied := flattenNotificationPolicyUnsatisfied(plans)
+	for _, u := range unsatisfied {
+		log.Printf("[WARN] Notification policy %s: %s", diff.Get("name").(string), u)
+	}
+	if err := diff.SetNew("applied_users", flattenNotificationPolicyUsers(targets)); err != nil {
+		return err
+	}
+	if err := diff.SetNew("unsatisfied", unsatisfied); err != nil {
+		return err
+	}
+	changes := flattenNotificationPolicyChanges(plans)
+	if len(changes) > 0 {
+		// the ids of the preferences the policy creates are only known
+		// after the apply
+		if err := diff.SetNewComputed("owned_preferences"); err != nil {
+			return err
+		}
+	} else {
+		// drops the preferences users deleted themselves
+		current := make(notificationPolicyOwned, len(plans))
+		for _, userPlan := range plans {
+			current[userPlan.userID] = userPlan.ownedAfter(nil, true)
+		}
+		if err := diff.SetNew("owned_preferences", flattenNotificationPolicyOwned(current)); err != nil {
+			return err
+		}
+	}
+	return diff.SetNew("changes", changes)
+}
+
+func resourceNotificationPolicyCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	log.Printf("[INFO] Creating notification policy %s", d.Get("name").(string))
+
+	d.SetId(resource.UniqueId())
+	return applyNotificationPolicy(ctx, d, m, d.Timeout(schema.TimeoutCreate))
+}
+
+func resourceNotificationPolicyRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	// The preferences of the targeted users are compared with the rules at
+	// plan time, see customizeNotificationPolicy, so there is nothing to
+	// refresh here.
+	log.Printf("[DEBUG] Reading notification policy: %s", d.Id())
+	return nil
+}
+
+func resourceNotificationPolicyUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	log.Printf("[DEBUG] Updating notification policy: %s", d.Id())
+
+	return applyNotificationPolicy(ctx, d, m, d.Timeout(schema.TimeoutUpdate))
+}
+
+// resourceNotificationPolicyDelete removes the preferences the policy created
+// from the users it was applied to.
+func resourceNotificationPolicyDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	log.Printf("[DEBUG] Deleting notification policy: %s", d.Id())
+
+	previousTargets, err := expandNotificationPolicyUsers(d.Get("applied_users"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	owned, err := expandNotificationPolicyOwned(d.Get("owned_preferences"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	profiles, err := readNotificationPolicyProfiles(ctx, client, append(previousTargets, owned.users()...), d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		log.Printf("[ERROR] Deleting notification policy error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	plans := planNotificationPolicy(nil, nil, previousTargets, profiles, owned)
+	if _, err := applyNotificationPolicyPlans(ctx, client, plans, owned, d.Timeout(schema.TimeoutDelete)); err != nil {
+		log.Printf("[ERROR] Deleting notification policy error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// applyNotificationPolicy compares the rules with the preferences of the
+// targeted users again, as they may have changed since the plan, and applies
+// the changes. Rules that cannot be applied to a user are reported as
+// warnings. The preferences it creates are recorded in owned_preferences, also
+// when applying fails, so they are not left behind.
+func applyNotificationPolicy(ctx context.Context, d *schema.ResourceData, m any, timeout time.Duration) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	// the planned values are unknown or already account for the changes,
+	// the previous ones are what the policy was applied to
+	o, _ := d.GetChange("applied_users")
+	previousTargets, err := expandNotificationPolicyUsers(o)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	o, _ = d.GetChange("owned_preferences")
+	owned, err := expandNotificationPolicyOwned(o)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("owned_preferences", flattenNotificationPolicyOwned(owned)); err != nil {
+		return diag.Errorf("[ERROR] Error setting owned preferences: %s", err.Error())
+	}
+
+	rules := expandNotificationPreferences(d.Get)
+	if err := validateNotificationPolicyRules(rules); err != nil {
+		return diag.FromErr(err)
+	}
+	targets, plans, err := planNotificationPolicyChanges(ctx, client, d.Get("users"), d.Get("teams"), rules, previousTargets, owned, timeout)
+	if err != nil {
+		log.Printf("[ERROR] Planning notification policy error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	owned, err = applyNotificationPolicyPlans(ctx, client, plans, owned, timeout)
+	if setErr := d.Set("owned_preferences", flattenNotificationPolicyOwned(owned)); setErr != nil {
+		return diag.Errorf("[ERROR] Error setting owned preferences: %s", setErr.Error())
+	}
+	if err != nil {
+		log.Printf("[ERROR] Applying notification policy error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	unsatisfied := flattenNotificationPolicyUnsatisfied(plans)
+	if err := d.Set("applied_users", flattenNotificationPolicyUsers(targets)); err != nil {
+		return diag.Errorf("[ERROR] Error setting applied users: %s", err.Error())
+	}
+	if err := d.Set("unsatisfied", unsatisfied); err != nil {
+		return diag.Errorf("[ERROR] Error setting unsatisfied: %s", err.Error())
+	}
+	if err := d.Set("changes", []any{}); err != nil {
+		return diag.Errorf("[ERROR] Error setting changes: %s", err.Error())
+	}
+
+	var diags diag.Diagnostics
+	for _, u := range unsatisfied {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Notification policy %q cannot be applied to every user", d.Get("name").(string)),
+			Detail:   u.(string),
+		})
+	}
+	return diags
+}
+
+// planNotificationPolicyChanges resolves the targeted users, reads the profile
+// of every target, previous target and user the policy owns preferences of and
+// plans the changes, see planNotificationPolicy.
+func planNotificationPolicyChanges(ctx context.Context, client *ilert.Client, users, teams any, rules []notificationPreference, previousTargets []int64, owned notificationPolicyOwned, timeout time.Duration) ([]int64, []notificationPolicyUserPlan, error) {
+	targets, err := resolveNotificationPolicyTargets(ctx, client, users, teams, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	profiles, err := readNotificationPolicyProfiles(ctx, client, append(append(append([]int64{}, targets...), previousTargets...), owned.users()...), timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return targets, planNotificationPolicy(rules, targets, previousTargets, profiles, owned), nil
+}
+
+// resolveNotificationPolicyTargets returns the ids of the listed users and of
+// the members of the listed teams, in ascending order.
+func resolveNotificationPolicyTargets(ctx context.Context, client *ilert.Client, users, teams any, timeout time.Duration) ([]int64, error) {
+	targets, err := expandNotificationPolicyUsers(users)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs, err := expandNotificationPolicyUsers(teams)
+	if err != nil {
+		return nil, err
+	}
+	for _, teamID := range teamIDs {
+		team, err := getTeamForMembership(ctx, client, teamID, timeout)
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil, fmt.Errorf("team %d does not exist", teamID)
+			}
+			return nil, err
+		}
+		for _, member := range team.Members {
+			targets = append(targets, member.User.ID)
+		}
+	}
+
+	seen := make(map[int64]bool, len(targets))
+	result := make([]int64, 0, len(targets))
+	for _, id := range targets {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// notificationPolicyProfileReaders bounds how many profiles are read at once.
+// Reading a profile takes six requests, see readUserNotificationProfile.
+const notificationPolicyProfileReaders = 4
+
+// readNotificationPolicyProfiles reads the profile of each user, up to
+// notificationPolicyProfileReaders at once. Users that no longer exist have no
+// profile.
+func readNotificationPolicyProfiles(ctx context.Context, client *ilert.Client, userIDs []int64, timeout time.Duration) (map[int64]notificationProfile, error) {
+	profiles := make(map[int64]notificationProfile, len(userIDs))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	readers := make(chan struct{}, notificationPolicyProfileReaders)
+	read := make(map[int64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if read[userID] {
+			continue
+		}
+		read[userID] = true
+
+		readers <- struct{}{}
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			<-readers
+			break
+		}
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			defer func() { <-readers }()
+			profile, err := readUserNotificationProfile(ctx, client, userID, timeout)
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] User %d not found, skipping it in the notification policy", userID)
+				profile, err = notificationProfile{}, nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			profiles[userID] = profile
+		}(userID)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return profiles, nil
+}
+
+// applyNotificationPolicyPlans applies the plan of each user and returns the
+// preferences the policy owns afterwards. When a plan fails they include the
+// preferences created so far.
+func applyNotificationPolicyPlans(ctx context.Context, client *ilert.Client, plans []notificationPolicyUserPlan, owned notificationPolicyOwned, timeout time.Duration) (notificationPolicyOwned, error) {
+	result := make(notificationPolicyOwned, len(owned))
+	for userID, refs := range owned {
+		result[userID] = refs
+	}
+	for _, userPlan := range plans {
+		if userPlan.plan.empty() {
+			result[userPlan.userID] = userPlan.ownedAfter(nil, true)
+			continue
+		}
+		log.Printf("[INFO] Notification policy: updating %d preference(s) of user %d", len(userPlan.plan.createPreferences)+len(userPlan.plan.deletePreferences), userPlan.userID)
+		created, err := applyNotificationProfilePlan(ctx, client, userPlan.userID, userPlan.plan, userPlan.contacts, timeout)
+		result[userPlan.userID] = userPlan.ownedAfter(created, err == nil)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func expandNotificationPolicyUsers(v any) ([]int64, error) {
+	ids := make([]int64, 0)
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return ids, nil
+	}
+	for _, it := range set.List() {
+		id, err := strconv.ParseInt(it.(string), 10, 64)
+		if err != nil {
+			return nil, unconvertibleIDErr(it.(string), err)
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func flattenNotificationPolicyUsers(ids []int64) []any {
+	result := make([]any, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, strconv.FormatInt(id, 10))
+	}
+	return result
+}
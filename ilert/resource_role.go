@@ -0,0 +1,293 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+			},
+			"permissions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+			},
+		},
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Exists:        resourceRoleExists,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func buildRole(d *schema.ResourceData) (*ilert.CustomRole, error) {
+	role := &ilert.CustomRole{
+		Name:        d.Get("name").(string),
+		Permissions: make([]string, 0),
+	}
+	for _, p := range d.Get("permissions").(*schema.Set).List() {
+		role.Permissions = append(role.Permissions, p.(string))
+	}
+	sort.Strings(role.Permissions)
+
+	return role, nil
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	role, err := buildRole(d)
+	if err != nil {
+		log.Printf("[ERROR] Building role error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Creating role %s", role.Name)
+
+	result := &ilert.CreateCustomRoleOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		r, err := client.CreateCustomRole(&ilert.CreateCustomRoleInput{CustomRole: role})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Creating ilert role error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for role to be created, error: %s", err.Error()))
+			}
+			return resource.NonRetryableError(err)
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Creating ilert role error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if result == nil || result.CustomRole == nil {
+		log.Printf("[ERROR] Creating ilert role error: empty response")
+		return diag.Errorf("role response is empty")
+	}
+
+	d.SetId(strconv.FormatInt(result.CustomRole.ID, 10))
+
+	return resourceRoleRead(ctx, d, m)
+}
+
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	roleID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Reading role: %s", d.Id())
+
+	result := &ilert.GetCustomRoleOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetCustomRole(&ilert.GetCustomRoleInput{CustomRoleID: ilert.Int64(roleID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] Removing role %s from state because it no longer exist", d.Id())
+				d.SetId("")
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for role with id '%s' to be read, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a role with ID %s, error: %s", d.Id(), err.Error()))
+		}
+		result = r
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert role error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	if result == nil || result.CustomRole == nil {
+		log.Printf("[ERROR] Reading ilert role error: empty response")
+		return diag.Errorf("role response is empty")
+	}
+
+	if err := transformRoleResource(result.CustomRole, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	role, err := buildRole(d)
+	if err != nil {
+		log.Printf("[ERROR] Building role error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	roleID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Updating role: %s", d.Id())
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err = client.UpdateCustomRole(&ilert.UpdateCustomRoleInput{CustomRole: role, CustomRoleID: ilert.Int64(roleID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for role with id '%s' to be updated, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not update a role with id %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Updating ilert role error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	return resourceRoleRead(ctx, d, m)
+}
+
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	roleID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Deleting role: %s", d.Id())
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err = client.DeleteCustomRole(&ilert.DeleteCustomRoleInput{CustomRoleID: ilert.Int64(roleID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for role with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not delete a role with id %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Deleting ilert role error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceRoleExists(d *schema.ResourceData, m any) (bool, error) {
+	client := m.(*providerMeta).client
+
+	roleID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return false, unconvertibleIDErr(d.Id(), err)
+	}
+	log.Printf("[DEBUG] Reading role: %s", d.Id())
+
+	ctx := context.Background()
+	result := false
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err := client.GetCustomRole(&ilert.GetCustomRoleInput{CustomRoleID: ilert.Int64(roleID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				result = false
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Reading ilert role error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for role to be read, error: %s", err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a role with ID %s, error: %s", d.Id(), err.Error()))
+		}
+		result = true
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert role error: %s", err.Error())
+		return false, err
+	}
+	return result, nil
+}
+
+// customRoleSchema is the schema of attributes that reference an ilert_role.
+func customRoleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		ValidateFunc: validation.StringMatch(
+			regexp.MustCompile(`^[0-9]+$`),
+			"must be a numeric role id",
+		),
+	}
+}
+
+// expandCustomRole returns the reference to the custom role with the given id,
+// or nil for an empty id.
+func expandCustomRole(id string) (*ilert.CustomRole, error) {
+	if id == "" {
+		return nil, nil
+	}
+	roleID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, unconvertibleIDErr(id, err)
+	}
+	return &ilert.CustomRole{ID: roleID}, nil
+}
+
+func flattenCustomRole(role *ilert.CustomRole) string {
+	if role == nil || role.ID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(role.ID, 10)
+}
+
+func transformRoleResource(role *ilert.CustomRole, d *schema.ResourceData) error {
+	d.Set("name", role.Name)
+	if err := d.Set("permissions", role.Permissions); err != nil {
+		return fmt.Errorf("[ERROR] Error setting permissions: %s", err.Error())
+	}
+
+	return nil
+}
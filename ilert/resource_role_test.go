@@ -0,0 +1,61 @@
+package ilert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestBuildRole(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRole().Schema, map[string]any{
+		"name":        "Responder",
+		"permissions": []any{"incident.write", "alert.read", "alert.write"},
+	})
+
+	role, err := buildRole(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.Name != "Responder" || role.ID != 0 {
+		t.Errorf("unexpected role %+v", role)
+	}
+	// permissions come from a set, they are sorted so the request is stable
+	want := []string{"alert.read", "alert.write", "incident.write"}
+	if !reflect.DeepEqual(role.Permissions, want) {
+		t.Errorf("got permissions %v, want %v", role.Permissions, want)
+	}
+}
+
+func TestExpandCustomRole(t *testing.T) {
+	role, err := expandCustomRole("")
+	if err != nil || role != nil {
+		t.Errorf("expected no role for an empty id, got %+v, %v", role, err)
+	}
+
+	role, err = expandCustomRole("42")
+	if err != nil || role == nil || role.ID != 42 {
+		t.Errorf("unexpected role %+v, %v", role, err)
+	}
+
+	if _, err := expandCustomRole("admin"); err == nil {
+		t.Errorf("expected a non-numeric id to be rejected")
+	}
+}
+
+func TestFlattenCustomRole(t *testing.T) {
+	cases := []struct {
+		role *ilert.CustomRole
+		want string
+	}{
+		{nil, ""},
+		{&ilert.CustomRole{}, ""},
+		{&ilert.CustomRole{ID: 42, Name: "Responder"}, "42"},
+	}
+	for _, tc := range cases {
+		if got := flattenCustomRole(tc.role); got != tc.want {
+			t.Errorf("flattenCustomRole(%+v) = %q, want %q", tc.role, got, tc.want)
+		}
+	}
+}
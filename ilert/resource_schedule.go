@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -23,8 +25,9 @@ func resourceSchedule() *schema.Resource {
 				ValidateFunc: validation.StringLenBetween(1, 255),
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTimezone,
 			},
 			"type": {
 				Type:         schema.TypeString,
@@ -43,12 +46,16 @@ func resourceSchedule() *schema.Resource {
 							ValidateFunc: validation.StringLenBetween(1, 255),
 						},
 						"starts_on": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 						"ends_on": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 						"user": {
 							Type:     schema.TypeList,
@@ -72,8 +79,10 @@ func resourceSchedule() *schema.Resource {
 							},
 						},
 						"rotation": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateISODuration,
+							DiffSuppressFunc: suppressEquivalentISODuration,
 						},
 						"restriction_type": {
 							Type:     schema.TypeString,
@@ -98,8 +107,10 @@ func resourceSchedule() *schema.Resource {
 													ValidateFunc: validation.StringInSlice(ilert.DayOfWeekAll, false),
 												},
 												"time": {
-													Type:     schema.TypeString,
-													Required: true,
+													Type:             schema.TypeString,
+													Required:         true,
+													ValidateFunc:     validateTimeOfDay,
+													DiffSuppressFunc: suppressEquivalentTimeOfDay,
 												},
 											},
 										},
@@ -117,8 +128,10 @@ func resourceSchedule() *schema.Resource {
 													ValidateFunc: validation.StringInSlice(ilert.DayOfWeekAll, false),
 												},
 												"time": {
-													Type:     schema.TypeString,
-													Required: true,
+													Type:             schema.TypeString,
+													Required:         true,
+													ValidateFunc:     validateTimeOfDay,
+													DiffSuppressFunc: suppressEquivalentTimeOfDay,
 												},
 											},
 										},
@@ -140,22 +153,34 @@ func resourceSchedule() *schema.Resource {
 							Required: true,
 						},
 						"start": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 						"end": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 					},
 				},
 			},
+			"ics_content": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateScheduleICS,
+				ConflictsWith: []string{"shift"},
+			},
 			"show_gaps": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
 			"default_shift_duration": {
-				Type: schema.TypeString,
+				Type:             schema.TypeString,
+				ValidateFunc:     validateISODuration,
+				DiffSuppressFunc: suppressEquivalentISODuration,
 				// Computed as well as optional: the API always answers with a duration, and
 				// without this a configuration that leaves the field out would report the
 				// value the API returned as a diff on every plan.
@@ -227,7 +252,63 @@ func resourceSchedule() *schema.Resource {
 					},
 				},
 			},
+			"preview_from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateScheduleDateTime,
+			},
+			"preview_horizon": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateISODuration,
+			},
+			"preview_shifts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"start": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"coverage_gaps": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"start": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"coverage_checked_until": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lint_findings":       lintFindingsSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 		},
+		CustomizeDiff: customdiff.All(
+			customizeSchedulePreview,
+			customizeScheduleLint,
+		),
 		CreateContext: resourceScheduleCreate,
 		ReadContext:   resourceScheduleRead,
 		UpdateContext: resourceScheduleUpdate,
@@ -245,6 +326,140 @@ func resourceSchedule() *schema.Resource {
 	}
 }
 
+// customizeSchedulePreview expands the layers of a recurring schedule at plan
+// time, so the gaps between the shifts they produce show up in the plan as
+// coverage_gaps instead of only when nobody gets paged. The shifts themselves
+// are previewed only when a preview_horizon is set.
+func customizeSchedulePreview(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	for _, key := range []string{"type", "timezone", "preview_from", "preview_horizon", "schedule_layer"} {
+		if !diffValuesKnown(diff, key) {
+			for _, computed := range []string{"preview_shifts", "coverage_gaps", "coverage_checked_until"} {
+				if err := diff.SetNewComputed(computed); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	previewShifts, gaps, checkedUntil := make([]any, 0), make([]any, 0), ""
+	if diff.Get("type").(string) != ilert.ScheduleType.Recurring {
+		oldShifts, _ := diff.GetChange("preview_shifts")
+		oldGaps, _ := diff.GetChange("coverage_gaps")
+		oldCheckedUntil, _ := diff.GetChange("coverage_checked_until")
+		if len(oldShifts.([]any)) == 0 && len(oldGaps.([]any)) == 0 && oldCheckedUntil.(string) == "" {
+			return nil
+		}
+	} else {
+		layers, _ := diff.Get("schedule_layer").([]any)
+		horizon := diff.Get("preview_horizon").(string)
+		s, g, c, err := schedulePreview(layers, diff.Get("timezone").(string), diff.Get("preview_from").(string), horizon)
+		if err != nil {
+			return fmt.Errorf("[ERROR] Could not preview the shifts of the schedule layers: %s", err.Error())
+		}
+		if horizon != "" {
+			previewShifts = s
+		}
+		gaps, checkedUntil = g, c
+	}
+
+	if err := diff.SetNew("preview_shifts", previewShifts); err != nil {
+		return err
+	}
+	if err := diff.SetNew("coverage_gaps", gaps); err != nil {
+		return err
+	}
+	return diff.SetNew("coverage_checked_until", checkedUntil)
+}
+
+// setSchedulePreview refreshes preview_shifts, coverage_gaps and
+// coverage_checked_until from the layers in d, the same way
+// customizeSchedulePreview planned them.
+func setSchedulePreview(d *schema.ResourceData) error {
+	previewShifts, gaps, checkedUntil := make([]any, 0), make([]any, 0), ""
+	if d.Get("type").(string) == ilert.ScheduleType.Recurring {
+		layers, _ := d.Get("schedule_layer").([]any)
+		horizon := d.Get("preview_horizon").(string)
+		s, g, c, err := schedulePreview(layers, d.Get("timezone").(string), d.Get("preview_from").(string), horizon)
+		if err != nil {
+			log.Printf("[WARN] Could not preview the shifts of schedule %s: %s", d.Id(), err.Error())
+		} else {
+			if horizon != "" {
+				previewShifts = s
+			}
+			gaps, checkedUntil = g, c
+		}
+	}
+	if err := d.Set("preview_shifts", previewShifts); err != nil {
+		return fmt.Errorf("[ERROR] Error setting preview shifts: %s", err.Error())
+	}
+	if err := d.Set("coverage_gaps", gaps); err != nil {
+		return fmt.Errorf("[ERROR] Error setting coverage gaps: %s", err.Error())
+	}
+	d.Set("coverage_checked_until", checkedUntil)
+	return nil
+}
+
+// scheduleCoverageWarnings warns after an apply when the layers of a recurring
+// schedule leave gaps in which no one would be paged. It checks the same window
+// as coverage_gaps, so the warning lists the gaps the plan already showed.
+// Setting show_gaps acknowledges the gaps. It also warns when the layers could
+// only be checked for part of that window.
+func scheduleCoverageWarnings(d *schema.ResourceData) diag.Diagnostics {
+	if d.Get("type").(string) != ilert.ScheduleType.Recurring {
+		return nil
+	}
+	layers, err := buildScheduleLayers(d.Get("schedule_layer").([]any))
+	if err != nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(d.Get("timezone").(string))
+	if err != nil {
+		return nil
+	}
+	from := d.Get("preview_from").(string)
+	horizon := d.Get("preview_horizon").(string)
+	start, end, err := schedulePreviewWindow(layers, loc, from, horizon)
+	if err != nil || start.IsZero() {
+		return nil
+	}
+	shifts, expandedUntil, err := expandScheduleLayers(layers, loc.String(), start, end)
+	if err != nil {
+		return nil
+	}
+
+	layout := scheduleDateTimeLayouts[1]
+	diags := make(diag.Diagnostics, 0)
+	if expandedUntil.Before(end) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Schedule %q could only be checked for gaps until %s", d.Get("name").(string), expandedUntil.Format(layout)),
+			Detail: fmt.Sprintf("A schedule layer needs more than %d rotations to cover the window from %s to %s (%s), so its coverage after %s was not checked.",
+				maxScheduleRotations, start.Format(layout), end.Format(layout), loc.String(), expandedUntil.Format(layout)),
+		})
+		end = expandedUntil
+	}
+	gaps := scheduleCoverageGaps(shifts)
+	if len(gaps) == 0 || d.Get("show_gaps").(bool) {
+		return diags
+	}
+
+	lines := make([]string, 0)
+	for i, gap := range gaps {
+		if i == 5 {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(gaps)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("- %s to %s", gap.Start.Format(layout), gap.End.Format(layout)))
+	}
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Schedule %q has gaps in its on-call coverage", d.Get("name").(string)),
+		Detail: fmt.Sprintf("Nobody is on call in the following time ranges (%s) between %s and %s. Alerts routed to this schedule during a gap page nobody. Set show_gaps = true to acknowledge the gaps.\n%s",
+			loc.String(), start.Format(layout), end.Format(layout), strings.Join(lines, "\n")),
+	})
+}
+
 func buildSchedule(d *schema.ResourceData) (*ilert.Schedule, error) {
 	name := d.Get("name").(string)
 	timezone := d.Get("timezone").(string)
@@ -257,72 +472,9 @@ func buildSchedule(d *schema.ResourceData) (*ilert.Schedule, error) {
 	}
 
 	if val, ok := d.GetOk("schedule_layer"); ok && scheduleType == ilert.ScheduleType.Recurring {
-		vL := val.([]any)
-		sdl := make([]ilert.ScheduleLayer, 0)
-		for _, m := range vL {
-			v := m.(map[string]any)
-			sd := ilert.ScheduleLayer{
-				Name:     v["name"].(string),
-				StartsOn: v["starts_on"].(string),
-				Rotation: v["rotation"].(string),
-			}
-
-			usr := make([]ilert.User, 0)
-			uL := v["user"].([]any)
-			for _, u := range uL {
-				v := u.(map[string]any)
-				uid, err := strconv.ParseInt(v["id"].(string), 10, 64)
-				if err != nil {
-					log.Printf("[ERROR] Could not parse user id %s", err.Error())
-					return nil, unconvertibleIDErr(v["id"].(string), err)
-				}
-				us := ilert.User{
-					ID: uid,
-				}
-				if v["first_name"] != nil && v["first_name"].(string) != "" {
-					us.FirstName = v["first_name"].(string)
-				}
-				if v["last_name"] != nil && v["last_name"].(string) != "" {
-					us.LastName = v["last_name"].(string)
-				}
-				usr = append(usr, us)
-			}
-			sd.Users = usr
-
-			if v["restriction_type"] != nil && v["restriction_type"].(string) != "" {
-				sd.RestrictionType = v["restriction_type"].(string)
-			}
-
-			if v["restriction"] != nil {
-				rns := make([]ilert.LayerRestriction, 0)
-				rL := v["restriction"].([]any)
-				for _, r := range rL {
-					v := r.(map[string]any)
-
-					fL := v["from"].([]any)
-					f := fL[0].(map[string]any)
-					from := ilert.TimeOfWeek{
-						DayOfWeek: f["day_of_week"].(string),
-						Time:      f["time"].(string),
-					}
-
-					tL := v["to"].([]any)
-					t := tL[0].(map[string]any)
-					to := ilert.TimeOfWeek{
-						DayOfWeek: t["day_of_week"].(string),
-						Time:      t["time"].(string),
-					}
-
-					rn := ilert.LayerRestriction{
-						From: &from,
-						To:   &to,
-					}
-
-					rns = append(rns, rn)
-				}
-				sd.Restrictions = rns
-			}
-			sdl = append(sdl, sd)
+		sdl, err := buildScheduleLayers(val.([]any))
+		if err != nil {
+			return nil, err
 		}
 		schedule.ScheduleLayers = sdl
 	}
@@ -348,6 +500,16 @@ func buildSchedule(d *schema.ResourceData) (*ilert.Schedule, error) {
 			shs = append(shs, sh)
 		}
 		schedule.Shifts = shs
+	} else if val, ok := d.GetOk("ics_content"); ok && scheduleType == ilert.ScheduleType.Static {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("timezone %q is not a known IANA time zone: %s", timezone, err.Error())
+		}
+		shs, err := parseScheduleICS(val.(string), loc)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the shifts from ics_content: %s", err.Error())
+		}
+		schedule.Shifts = shs
 	}
 
 	if val, ok := d.GetOk("show_gaps"); ok {
@@ -418,8 +580,81 @@ func buildSchedule(d *schema.ResourceData) (*ilert.Schedule, error) {
 	return schedule, nil
 }
 
+func buildScheduleLayers(vL []any) ([]ilert.ScheduleLayer, error) {
+	sdl := make([]ilert.ScheduleLayer, 0)
+	for _, m := range vL {
+		v := m.(map[string]any)
+		sd := ilert.ScheduleLayer{
+			Name:     v["name"].(string),
+			StartsOn: v["starts_on"].(string),
+			Rotation: v["rotation"].(string),
+		}
+		if v["ends_on"] != nil && v["ends_on"].(string) != "" {
+			sd.EndsOn = v["ends_on"].(string)
+		}
+
+		usr := make([]ilert.User, 0)
+		uL := v["user"].([]any)
+		for _, u := range uL {
+			v := u.(map[string]any)
+			uid, err := strconv.ParseInt(v["id"].(string), 10, 64)
+			if err != nil {
+				log.Printf("[ERROR] Could not parse user id %s", err.Error())
+				return nil, unconvertibleIDErr(v["id"].(string), err)
+			}
+			us := ilert.User{
+				ID: uid,
+			}
+			if v["first_name"] != nil && v["first_name"].(string) != "" {
+				us.FirstName = v["first_name"].(string)
+			}
+			if v["last_name"] != nil && v["last_name"].(string) != "" {
+				us.LastName = v["last_name"].(string)
+			}
+			usr = append(usr, us)
+		}
+		sd.Users = usr
+
+		if v["restriction_type"] != nil && v["restriction_type"].(string) != "" {
+			sd.RestrictionType = v["restriction_type"].(string)
+		}
+
+		if v["restriction"] != nil {
+			rns := make([]ilert.LayerRestriction, 0)
+			rL := v["restriction"].([]any)
+			for _, r := range rL {
+				v := r.(map[string]any)
+
+				fL := v["from"].([]any)
+				f := fL[0].(map[string]any)
+				from := ilert.TimeOfWeek{
+					DayOfWeek: f["day_of_week"].(string),
+					Time:      f["time"].(string),
+				}
+
+				tL := v["to"].([]any)
+				t := tL[0].(map[string]any)
+				to := ilert.TimeOfWeek{
+					DayOfWeek: t["day_of_week"].(string),
+					Time:      t["time"].(string),
+				}
+
+				rn := ilert.LayerRestriction{
+					From: &from,
+					To:   &to,
+				}
+
+				rns = append(rns, rn)
+			}
+			sd.Restrictions = rns
+		}
+		sdl = append(sdl, sd)
+	}
+	return sdl, nil
+}
+
 func resourceScheduleCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	schedule, err := buildSchedule(d)
 	if err != nil {
@@ -454,11 +689,16 @@ func resourceScheduleCreate(ctx context.Context, d *schema.ResourceData, m any)
 
 	d.SetId(strconv.FormatInt(result.Schedule.ID, 10))
 
-	return resourceScheduleRead(ctx, d, m)
+	diags := resourceScheduleRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	diags = append(diags, scheduleCoverageWarnings(d)...)
+	return append(diags, scheduleLintWarnings(ctx, d, m)...)
 }
 
 func resourceScheduleRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	scheduleID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -510,7 +750,7 @@ func resourceScheduleRead(ctx context.Context, d *schema.ResourceData, m any) di
 }
 
 func resourceScheduleUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	schedule, err := buildSchedule(d)
 	if err != nil {
@@ -542,18 +782,27 @@ func resourceScheduleUpdate(ctx context.Context, d *schema.ResourceData, m any)
 		return diag.FromErr(err)
 	}
 
-	return resourceScheduleRead(ctx, d, m)
+	diags := resourceScheduleRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	diags = append(diags, scheduleCoverageWarnings(d)...)
+	return append(diags, scheduleLintWarnings(ctx, d, m)...)
 }
 
 func resourceScheduleDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	scheduleID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		log.Printf("[ERROR] Could not parse schedule id %s", err.Error())
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
+	if diags := checkDeletion(d, "schedule"); diags.HasError() {
+		return diags
+	}
 	log.Printf("[DEBUG] Deleting schedule: %s", d.Id())
+	var apiErr error
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err = client.DeleteSchedule(&ilert.DeleteScheduleInput{ScheduleID: ilert.Int64(scheduleID)})
 		if err != nil {
@@ -561,13 +810,14 @@ func resourceScheduleDelete(ctx context.Context, d *schema.ResourceData, m any)
 				time.Sleep(2 * time.Second)
 				return resource.RetryableError(fmt.Errorf("waiting for schedule with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
 			}
+			apiErr = err
 			return resource.NonRetryableError(fmt.Errorf("could not delete an schedule with ID %s, error: %s", d.Id(), err.Error()))
 		}
 		return nil
 	})
 	if err != nil {
 		log.Printf("[ERROR] Deleting ilert schedule error %s", err.Error())
-		return diag.FromErr(err)
+		return deletionError(ctx, d, client, "schedule", scheduleID, apiErr, err)
 	}
 
 	d.SetId("")
@@ -575,7 +825,7 @@ func resourceScheduleDelete(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceScheduleExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	scheduleID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -624,12 +874,8 @@ func transformScheduleResource(schedule *ilert.Schedule, d *schema.ResourceData)
 		return fmt.Errorf("[ERROR] Error setting schedule layers: %s", err.Error())
 	}
 
-	shifts, err := flattenShiftList(schedule.Shifts)
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error flattening shifts: %s", err.Error())
-	}
-	if err := d.Set("shift", shifts); err != nil {
-		return fmt.Errorf("[ERROR] Error setting shifts: %s", err.Error())
+	if err := setScheduleShifts(schedule, d); err != nil {
+		return err
 	}
 
 	d.Set("show_gaps", schedule.ShowGaps)
@@ -660,6 +906,10 @@ func transformScheduleResource(schedule *ilert.Schedule, d *schema.ResourceData)
 		d.Set("next_shift", []any{})
 	}
 
+	if err := setSchedulePreview(d); err != nil {
+		return err
+	}
+
 	teams, err := flattenTeamShortList(schedule.Teams, d)
 	if err != nil {
 		log.Printf("[ERROR] Error flattening teams: %s", err.Error())
@@ -673,6 +923,37 @@ func transformScheduleResource(schedule *ilert.Schedule, d *schema.ResourceData)
 	return nil
 }
 
+// setScheduleShifts stores the shifts of a static schedule. Shifts given as
+// ics_content are not mirrored into the shift blocks, which the configuration
+// leaves empty; when the shifts in ilert no longer match the calendar, the
+// content is cleared instead so the next plan puts them back.
+func setScheduleShifts(schedule *ilert.Schedule, d *schema.ResourceData) error {
+	if content, ok := d.GetOk("ics_content"); ok {
+		loc, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			return fmt.Errorf("[ERROR] Error loading timezone %s: %s", schedule.Timezone, err.Error())
+		}
+		expected, err := parseScheduleICS(content.(string), loc)
+		if err != nil || !sameScheduleShifts(schedule.Shifts, expected, loc) {
+			log.Printf("[WARN] Shifts of schedule %s no longer match its ics_content", d.Id())
+			d.Set("ics_content", "")
+		}
+		if err := d.Set("shift", []any{}); err != nil {
+			return fmt.Errorf("[ERROR] Error setting shifts: %s", err.Error())
+		}
+		return nil
+	}
+
+	shifts, err := flattenShiftList(schedule.Shifts)
+	if err != nil {
+		return fmt.Errorf("[ERROR] Error flattening shifts: %s", err.Error())
+	}
+	if err := d.Set("shift", shifts); err != nil {
+		return fmt.Errorf("[ERROR] Error setting shifts: %s", err.Error())
+	}
+	return nil
+}
+
 func flattenScheduleLayerList(list []ilert.ScheduleLayer, d *schema.ResourceData) ([]any, error) {
 	if list == nil {
 		return make([]any, 0), nil
@@ -687,6 +968,9 @@ func flattenScheduleLayerList(list []ilert.ScheduleLayer, d *schema.ResourceData
 				result := make(map[string]any)
 				result["name"] = item.Name
 				result["starts_on"] = item.StartsOn
+				if item.EndsOn != "" {
+					result["ends_on"] = item.EndsOn
+				}
 
 				var user []any
 				if layerConfig != nil {
@@ -722,6 +1006,9 @@ func flattenScheduleLayerList(list []ilert.ScheduleLayer, d *schema.ResourceData
 			result := make(map[string]any)
 			result["name"] = item.Name
 			result["starts_on"] = item.StartsOn
+			if item.EndsOn != "" {
+				result["ends_on"] = item.EndsOn
+			}
 
 			users, err := flattenUserShortList(item.Users)
 			if err != nil {
@@ -0,0 +1,437 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// The override endpoints replace the schedule's override list as a whole, so two
+// overrides of the same schedule applied in parallel would drop one of them.
+// Serialize them per schedule, like the alert action source attachments.
+var scheduleOverrideLock = newKeyedMutex()
+
+// scheduleDateTimeLayouts are the local date time formats the schedule endpoints
+// accept for layers, shifts and overrides, without a zone offset: the schedule's
+// timezone applies.
+var scheduleDateTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+func resourceScheduleOverride() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"schedule_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric schedule id",
+				),
+			},
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric user id",
+				),
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateScheduleDateTime,
+			},
+			"end": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateScheduleDateTime,
+			},
+			"timezone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		CreateContext: resourceScheduleOverrideCreate,
+		ReadContext:   resourceScheduleOverrideRead,
+		DeleteContext: resourceScheduleOverrideDelete,
+		CustomizeDiff: validateScheduleOverrideDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceScheduleOverrideImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func validateScheduleOverrideDiff(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diff.NewValueKnown("start") || !diff.NewValueKnown("end") {
+		return nil
+	}
+	start, err := parseScheduleDateTime(diff.Get("start").(string), time.UTC)
+	if err != nil {
+		return nil
+	}
+	end, err := parseScheduleDateTime(diff.Get("end").(string), time.UTC)
+	if err != nil {
+		return nil
+	}
+	if !start.Before(end) {
+		return fmt.Errorf("[ERROR] The override start %q must be before its end %q", diff.Get("start").(string), diff.Get("end").(string))
+	}
+	return nil
+}
+
+func resourceScheduleOverrideCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	scheduleID, err := strconv.ParseInt(d.Get("schedule_id").(string), 10, 64)
+	if err != nil {
+		log.Printf("[ERROR] Could not parse schedule id %s", err.Error())
+		return diag.FromErr(unconvertibleIDErr(d.Get("schedule_id").(string), err))
+	}
+	userID, err := strconv.ParseInt(d.Get("user").(string), 10, 64)
+	if err != nil {
+		log.Printf("[ERROR] Could not parse user id %s", err.Error())
+		return diag.FromErr(unconvertibleIDErr(d.Get("user").(string), err))
+	}
+	start := d.Get("start").(string)
+	end := d.Get("end").(string)
+	lockKey := strconv.FormatInt(scheduleID, 10)
+
+	scheduleOverrideLock.Lock(lockKey)
+	defer scheduleOverrideLock.Unlock(lockKey)
+
+	schedule, err := getScheduleForOverride(ctx, client, scheduleID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert schedule error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if schedule.Type != ilert.ScheduleType.Recurring {
+		return diag.Errorf("schedule %d is a %s schedule, overrides can only be added to %s schedules", scheduleID, schedule.Type, ilert.ScheduleType.Recurring)
+	}
+	if err := validateScheduleOverrideWindow(start, end, schedule.Timezone, schedule.ScheduleLayers); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Adding override from %s to %s for user %d to schedule %d", start, end, userID, scheduleID)
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err := client.AddScheduleShiftOverride(&ilert.AddScheduleShiftOverrideInput{
+			ScheduleID: ilert.Int64(scheduleID),
+			Shift: &ilert.Shift{
+				User:  ilert.User{ID: userID},
+				Start: start,
+				End:   end,
+			},
+		})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Adding ilert schedule override error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for override to be added to schedule %d, error: %s", scheduleID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not add an override to schedule %d, error: %s", scheduleID, err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Adding ilert schedule override error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId(scheduleOverrideID(scheduleID, start, end))
+
+	return resourceScheduleOverrideRead(ctx, d, m)
+}
+
+func resourceScheduleOverrideRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	scheduleID, start, end, err := parseScheduleOverrideID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+
+	log.Printf("[DEBUG] Reading schedule override: %s", d.Id())
+
+	schedule, err := getScheduleForOverride(ctx, client, scheduleID, d.Timeout(schema.TimeoutRead))
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			log.Printf("[WARN] Removing schedule override %s from state because the schedule no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] Reading ilert schedule error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return diag.Errorf("schedule %d has an unknown timezone %q: %s", scheduleID, schedule.Timezone, err.Error())
+	}
+
+	result := &ilert.GetScheduleOverridesOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetScheduleOverrides(&ilert.GetScheduleOverridesInput{ScheduleID: ilert.Int64(scheduleID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] Removing schedule override %s from state because the schedule no longer exists", d.Id())
+				d.SetId("")
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for overrides of schedule %d to be read, error: %s", scheduleID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read the overrides of schedule %d, error: %s", scheduleID, err.Error()))
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert schedule overrides error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	found := false
+	for _, override := range result.Overrides {
+		if !sameScheduleDateTime(override.Start, start, loc) || !sameScheduleDateTime(override.End, end, loc) {
+			continue
+		}
+		found = true
+		d.Set("user", strconv.FormatInt(override.User.ID, 10))
+		break
+	}
+	if !found {
+		log.Printf("[WARN] Removing schedule override %s from state because it no longer exists", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("schedule_id", strconv.FormatInt(scheduleID, 10))
+	d.Set("start", start)
+	d.Set("end", end)
+	d.Set("timezone", schedule.Timezone)
+
+	return nil
+}
+
+func resourceScheduleOverrideDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	scheduleID, start, end, err := parseScheduleOverrideID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	userID, err := strconv.ParseInt(d.Get("user").(string), 10, 64)
+	if err != nil {
+		log.Printf("[ERROR] Could not parse user id %s", err.Error())
+		return diag.FromErr(unconvertibleIDErr(d.Get("user").(string), err))
+	}
+	lockKey := strconv.FormatInt(scheduleID, 10)
+
+	scheduleOverrideLock.Lock(lockKey)
+	defer scheduleOverrideLock.Unlock(lockKey)
+
+	log.Printf("[DEBUG] Deleting schedule override: %s", d.Id())
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := client.DeleteScheduleOverride(&ilert.DeleteScheduleOverrideInput{
+			ScheduleID: ilert.Int64(scheduleID),
+			Shift: &ilert.Shift{
+				User:  ilert.User{ID: userID},
+				Start: start,
+				End:   end,
+			},
+		})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] Schedule override %s not found, treating delete as success", d.Id())
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for schedule override '%s' to be deleted, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not delete schedule override %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Deleting ilert schedule override error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceScheduleOverrideImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	scheduleID, start, end, err := parseScheduleOverrideID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("schedule_id", strconv.FormatInt(scheduleID, 10))
+	d.Set("start", start)
+	d.Set("end", end)
+	return []*schema.ResourceData{d}, nil
+}
+
+func getScheduleForOverride(ctx context.Context, client *ilert.Client, scheduleID int64, timeout time.Duration) (*ilert.Schedule, error) {
+	result := &ilert.GetScheduleOutput{}
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		r, err := client.GetSchedule(&ilert.GetScheduleInput{ScheduleID: ilert.Int64(scheduleID), Include: []*string{ilert.String("scheduleLayers")}})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for schedule with id '%d' to be read, error: %s", scheduleID, err.Error()))
+			}
+			return resource.NonRetryableError(err)
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.Schedule == nil {
+		return nil, fmt.Errorf("schedule response is empty")
+	}
+	return result.Schedule, nil
+}
+
+func scheduleOverrideID(scheduleID int64, start, end string) string {
+	return fmt.Sprintf("%d/%s/%s", scheduleID, start, end)
+}
+
+func parseScheduleOverrideID(id string) (scheduleID int64, start, end string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return 0, "", "", fmt.Errorf("expected ID in the form '<schedule_id>/<start>/<end>', got %q", id)
+	}
+	scheduleID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid schedule_id %q in ID %q: %s", parts[0], id, err.Error())
+	}
+	return scheduleID, parts[1], parts[2], nil
+}
+
+// parseScheduleDateTime reads a date time the way the schedule endpoints do: a
+// value without an offset is a wall clock time in the schedule's timezone, while
+// one with an offset (as the API returns them) names an absolute instant.
+func parseScheduleDateTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range scheduleDateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+// sameScheduleDateTime compares two date times as instants in the schedule's
+// timezone, so that "2026-12-24T00:00" matches the "2026-12-24T00:00:00+01:00"
+// the API answers with.
+func sameScheduleDateTime(a, b string, loc *time.Location) bool {
+	at, err := parseScheduleDateTime(a, loc)
+	if err != nil {
+		return a == b
+	}
+	bt, err := parseScheduleDateTime(b, loc)
+	if err != nil {
+		return false
+	}
+	return at.Equal(bt)
+}
+
+// validateScheduleOverrideWindow checks that an override window exists in the
+// schedule's timezone: both ends must be wall clock times the zone actually has,
+// not ones skipped by a daylight saving transition, the window must not be empty,
+// and it must overlap the time covered by the schedule's layers.
+func validateScheduleOverrideWindow(start, end, timezone string, layers []ilert.ScheduleLayer) error {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("schedule timezone %q is not a known IANA time zone: %s", timezone, err.Error())
+	}
+
+	startTime, err := parseScheduleLocalDateTime(start, loc)
+	if err != nil {
+		return fmt.Errorf("override start %s", err.Error())
+	}
+	endTime, err := parseScheduleLocalDateTime(end, loc)
+	if err != nil {
+		return fmt.Errorf("override end %s", err.Error())
+	}
+	if !startTime.Before(endTime) {
+		return fmt.Errorf("override start %q must be before its end %q", start, end)
+	}
+
+	var layersStart, layersEnd time.Time
+	openEnded := false
+	for _, layer := range layers {
+		if layerStart, err := parseScheduleDateTime(layer.StartsOn, loc); err == nil {
+			if layersStart.IsZero() || layerStart.Before(layersStart) {
+				layersStart = layerStart
+			}
+		}
+		if layer.EndsOn == "" {
+			openEnded = true
+			continue
+		}
+		if layerEnd, err := parseScheduleDateTime(layer.EndsOn, loc); err == nil && layerEnd.After(layersEnd) {
+			layersEnd = layerEnd
+		}
+	}
+	if !layersStart.IsZero() && !endTime.After(layersStart) {
+		return fmt.Errorf("override from %q to %q ends before the schedule's first layer starts on %s", start, end, layersStart.Format(scheduleDateTimeLayouts[1]))
+	}
+	if !openEnded && !layersEnd.IsZero() && !startTime.Before(layersEnd) {
+		return fmt.Errorf("override from %q to %q starts after the schedule's last layer ends on %s", start, end, layersEnd.Format(scheduleDateTimeLayouts[1]))
+	}
+
+	return nil
+}
+
+// parseScheduleLocalDateTime parses a wall clock date time in loc and rejects
+// times that do not exist there. time.Date silently moves a time in a daylight
+// saving gap (02:30 on the spring forward night in Europe/Berlin) by an hour.
+func parseScheduleLocalDateTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range scheduleDateTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
+		}
+		if t.Format(layout) != value {
+			return time.Time{}, fmt.Errorf("%q does not exist in time zone %s, it falls into a daylight saving time transition", value, loc.String())
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date time such as 2022-08-30T00:00", value)
+	}
+	return t.In(loc), nil
+}
@@ -0,0 +1,101 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestParseScheduleOverrideID(t *testing.T) {
+	cases := []struct {
+		in             string
+		wantScheduleID int64
+		wantStart      string
+		wantEnd        string
+		wantErr        bool
+	}{
+		{"123/2026-12-24T00:00/2026-12-27T00:00", 123, "2026-12-24T00:00", "2026-12-27T00:00", false},
+		{"1/2026-12-24T08:00:00/2026-12-24T18:00:00", 1, "2026-12-24T08:00:00", "2026-12-24T18:00:00", false},
+		{"", 0, "", "", true},
+		{"123", 0, "", "", true},
+		{"123/2026-12-24T00:00", 0, "", "", true},
+		{"123//2026-12-27T00:00", 0, "", "", true},
+		{"abc/2026-12-24T00:00/2026-12-27T00:00", 0, "", "", true},
+	}
+	for _, tc := range cases {
+		gotScheduleID, gotStart, gotEnd, err := parseScheduleOverrideID(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseScheduleOverrideID(%q) expected error, got nil", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseScheduleOverrideID(%q) unexpected error: %v", tc.in, err)
+			continue
+		}
+		if gotScheduleID != tc.wantScheduleID || gotStart != tc.wantStart || gotEnd != tc.wantEnd {
+			t.Errorf("parseScheduleOverrideID(%q) = (%d, %q, %q), want (%d, %q, %q)",
+				tc.in, gotScheduleID, gotStart, gotEnd, tc.wantScheduleID, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func TestValidateScheduleOverrideWindow(t *testing.T) {
+	layers := []ilert.ScheduleLayer{
+		{Name: "layer1", StartsOn: "2026-01-01T00:00", EndsOn: "2026-12-31T00:00", Rotation: "P7D"},
+	}
+
+	cases := []struct {
+		name    string
+		start   string
+		end     string
+		layers  []ilert.ScheduleLayer
+		wantErr string
+	}{
+		{"valid window", "2026-12-24T00:00", "2026-12-27T00:00", layers, ""},
+		{"valid window with seconds", "2026-12-24T00:00:00", "2026-12-27T00:00:00", layers, ""},
+		{"end before start", "2026-12-27T00:00", "2026-12-24T00:00", layers, "must be before its end"},
+		{"empty window", "2026-12-24T00:00", "2026-12-24T00:00", layers, "must be before its end"},
+		{"start in daylight saving gap", "2026-03-29T02:30", "2026-03-29T08:00", layers, "does not exist in time zone Europe/Berlin"},
+		{"before the first layer", "2025-12-24T00:00", "2025-12-27T00:00", layers, "ends before the schedule's first layer"},
+		{"after the last layer", "2027-01-02T00:00", "2027-01-03T00:00", layers, "starts after the schedule's last layer"},
+		{"open ended layer", "2030-01-02T00:00", "2030-01-03T00:00", []ilert.ScheduleLayer{{StartsOn: "2026-01-01T00:00"}}, ""},
+		{"malformed start", "24.12.2026", "2026-12-27T00:00", layers, "is not a date time"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateScheduleOverrideWindow(tc.start, tc.end, "Europe/Berlin", tc.layers)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleOverrideWindow_UnknownTimezone(t *testing.T) {
+	if err := validateScheduleOverrideWindow("2026-12-24T00:00", "2026-12-27T00:00", "Europe/Berlinn", nil); err == nil {
+		t.Fatalf("expected an error for an unknown timezone")
+	}
+}
+
+func TestSameScheduleDateTime_MatchesAPIOffsetFormat(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error loading location: %v", err)
+	}
+	if !sameScheduleDateTime("2026-12-24T00:00:00+01:00", "2026-12-24T00:00", loc) {
+		t.Fatalf("expected the API offset format to match the configured wall clock time")
+	}
+	if sameScheduleDateTime("2026-12-24T00:00:00Z", "2026-12-24T00:00", loc) {
+		t.Fatalf("expected midnight UTC not to match midnight in Europe/Berlin")
+	}
+}
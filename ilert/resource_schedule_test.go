@@ -132,6 +132,117 @@ func TestTransformScheduleResource_DefaultShiftDurationFollowsTheAPI(t *testing.
 	}
 }
 
+func TestSetSchedulePreview_ReportsGapsOfRestrictedLayers(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceSchedule().Schema, map[string]any{
+		"name":            "test-schedule",
+		"type":            ilert.ScheduleType.Recurring,
+		"timezone":        "Europe/Berlin",
+		"preview_horizon": "P1D",
+		"schedule_layer": []any{
+			map[string]any{
+				"name":             "business hours",
+				"starts_on":        "2026-01-05T00:00",
+				"rotation":         "P7D",
+				"restriction_type": "TIMES_OF_WEEK",
+				"user":             []any{map[string]any{"id": "1"}},
+				"restriction":      []any{newRestrictionConfig("MONDAY", "09:00", "MONDAY", "17:00")},
+			},
+		},
+	})
+
+	if err := setSchedulePreview(d); err != nil {
+		t.Fatalf("unexpected error setting the preview: %v", err)
+	}
+
+	wantShifts := []any{
+		map[string]any{"user": "1", "start": "2026-01-05T09:00", "end": "2026-01-05T17:00"},
+	}
+	if got := d.Get("preview_shifts").([]any); !reflect.DeepEqual(got, wantShifts) {
+		t.Fatalf("expected preview shifts %v, got %v", wantShifts, got)
+	}
+	wantGaps := []any{
+		map[string]any{"start": "2026-01-05T00:00", "end": "2026-01-05T09:00"},
+		map[string]any{"start": "2026-01-05T17:00", "end": "2026-01-06T00:00"},
+	}
+	if got := d.Get("coverage_gaps").([]any); !reflect.DeepEqual(got, wantGaps) {
+		t.Fatalf("expected coverage gaps %v, got %v", wantGaps, got)
+	}
+}
+
+func TestSetSchedulePreview_GapsWithoutHorizon(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceSchedule().Schema, map[string]any{
+		"name":     "test-schedule",
+		"type":     ilert.ScheduleType.Recurring,
+		"timezone": "Europe/Berlin",
+		"schedule_layer": []any{
+			map[string]any{
+				"name":             "mondays",
+				"starts_on":        "2026-01-05T00:00",
+				"rotation":         "P7D",
+				"restriction_type": "TIMES_OF_WEEK",
+				"user":             []any{map[string]any{"id": "1"}},
+				"restriction":      []any{newRestrictionConfig("MONDAY", "00:00", "TUESDAY", "00:00")},
+			},
+		},
+	})
+
+	if err := setSchedulePreview(d); err != nil {
+		t.Fatalf("unexpected error setting the preview: %v", err)
+	}
+	if got := d.Get("preview_shifts").([]any); len(got) != 0 {
+		t.Fatalf("expected no preview shifts without a preview_horizon, got %v", got)
+	}
+	wantGaps := []any{
+		map[string]any{"start": "2026-01-06T00:00", "end": "2026-01-12T00:00"},
+		map[string]any{"start": "2026-01-13T00:00", "end": "2026-01-19T00:00"},
+	}
+	if got := d.Get("coverage_gaps").([]any); !reflect.DeepEqual(got, wantGaps) {
+		t.Fatalf("expected coverage gaps %v over the default horizon, got %v", wantGaps, got)
+	}
+	if got := d.Get("coverage_checked_until").(string); got != "2026-01-19T00:00" {
+		t.Fatalf("expected coverage to be checked until 2026-01-19T00:00, got %q", got)
+	}
+}
+
+func TestSetScheduleShifts_ICSContentClearedOnDrift(t *testing.T) {
+	content := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20260105T090000\r\nDTEND:20260105T170000\r\nX-ILERT-USER-ID:1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	newData := func() *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourceSchedule().Schema, map[string]any{
+			"name":        "test-schedule",
+			"type":        ilert.ScheduleType.Static,
+			"timezone":    "Europe/Berlin",
+			"ics_content": content,
+		})
+	}
+
+	d := newData()
+	matching := &ilert.Schedule{
+		Timezone: "Europe/Berlin",
+		Shifts:   []ilert.Shift{{User: ilert.User{ID: 1}, Start: "2026-01-05T09:00:00+01:00", End: "2026-01-05T17:00:00+01:00"}},
+	}
+	if err := setScheduleShifts(matching, d); err != nil {
+		t.Fatalf("unexpected error setting shifts: %v", err)
+	}
+	if got := d.Get("ics_content").(string); got != content {
+		t.Fatalf("expected ics_content to be kept while the shifts match, got %q", got)
+	}
+	if got := d.Get("shift").([]any); len(got) != 0 {
+		t.Fatalf("expected no shift blocks next to ics_content, got %v", got)
+	}
+
+	d = newData()
+	drifted := &ilert.Schedule{
+		Timezone: "Europe/Berlin",
+		Shifts:   []ilert.Shift{{User: ilert.User{ID: 2}, Start: "2026-01-05T09:00:00+01:00", End: "2026-01-05T17:00:00+01:00"}},
+	}
+	if err := setScheduleShifts(drifted, d); err != nil {
+		t.Fatalf("unexpected error setting shifts: %v", err)
+	}
+	if got := d.Get("ics_content").(string); got != "" {
+		t.Fatalf("expected ics_content to be cleared after the shifts drifted, got %q", got)
+	}
+}
+
 func newLayerRestriction(fromDay, fromTime, toDay, toTime string) ilert.LayerRestriction {
 	from := ilert.TimeOfWeek{
 		DayOfWeek: fromDay,
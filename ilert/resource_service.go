@@ -140,7 +140,7 @@ func buildService(d *schema.ResourceData) (*ilert.Service, error) {
 }
 
 func resourceServiceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	service, err := buildService(d)
 	if err != nil {
@@ -179,7 +179,7 @@ func resourceServiceCreate(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceServiceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	serviceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -225,7 +225,7 @@ func resourceServiceRead(ctx context.Context, d *schema.ResourceData, m any) dia
 }
 
 func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	service, err := buildService(d)
 	if err != nil {
@@ -261,7 +261,7 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceServiceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	serviceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -290,7 +290,7 @@ func resourceServiceDelete(ctx context.Context, d *schema.ResourceData, m any) d
 }
 
 func resourceServiceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	serviceID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
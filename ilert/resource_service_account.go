@@ -0,0 +1,266 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func resourceServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+			},
+			"role": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "USER",
+				ValidateFunc: validation.StringInSlice([]string{
+					"ADMIN",
+					"USER",
+					"RESPONDER",
+					"STAKEHOLDER",
+					"VIEWER",
+				}, false),
+			},
+			"custom_role": customRoleSchema(),
+		},
+		CreateContext: resourceServiceAccountCreate,
+		ReadContext:   resourceServiceAccountRead,
+		UpdateContext: resourceServiceAccountUpdate,
+		DeleteContext: resourceServiceAccountDelete,
+		Exists:        resourceServiceAccountExists,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func buildServiceAccount(d *schema.ResourceData) (*ilert.ServiceAccount, error) {
+	account := &ilert.ServiceAccount{
+		Name: d.Get("name").(string),
+		Role: d.Get("role").(string),
+	}
+
+	if val, ok := d.GetOk("custom_role"); ok {
+		customRole, err := expandCustomRole(val.(string))
+		if err != nil {
+			return nil, err
+		}
+		account.CustomRole = customRole
+	}
+
+	return account, nil
+}
+
+func resourceServiceAccountCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	account, err := buildServiceAccount(d)
+	if err != nil {
+		log.Printf("[ERROR] Building service account error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Creating service account %s", account.Name)
+
+	result := &ilert.CreateServiceAccountOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		r, err := client.CreateServiceAccount(&ilert.CreateServiceAccountInput{ServiceAccount: account})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Creating ilert service account error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for service account to be created, error: %s", err.Error()))
+			}
+			return resource.NonRetryableError(err)
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Creating ilert service account error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if result == nil || result.ServiceAccount == nil {
+		log.Printf("[ERROR] Creating ilert service account error: empty response")
+		return diag.Errorf("service account response is empty")
+	}
+
+	d.SetId(strconv.FormatInt(result.ServiceAccount.ID, 10))
+
+	return resourceServiceAccountRead(ctx, d, m)
+}
+
+func resourceServiceAccountRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	accountID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Reading service account: %s", d.Id())
+
+	result := &ilert.GetServiceAccountOutput{}
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+		r, err := client.GetServiceAccount(&ilert.GetServiceAccountInput{ServiceAccountID: ilert.Int64(accountID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				log.Printf("[WARN] Removing service account %s from state because it no longer exist", d.Id())
+				d.SetId("")
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for service account with id '%s' to be read, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a service account with ID %s, error: %s", d.Id(), err.Error()))
+		}
+		result = r
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert service account error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	if result == nil || result.ServiceAccount == nil {
+		log.Printf("[ERROR] Reading ilert service account error: empty response")
+		return diag.Errorf("service account response is empty")
+	}
+
+	if err := transformServiceAccountResource(result.ServiceAccount, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceServiceAccountUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	account, err := buildServiceAccount(d)
+	if err != nil {
+		log.Printf("[ERROR] Building service account error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	accountID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Updating service account: %s", d.Id())
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err = client.UpdateServiceAccount(&ilert.UpdateServiceAccountInput{ServiceAccount: account, ServiceAccountID: ilert.Int64(accountID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for service account with id '%s' to be updated, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not update a service account with id %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Updating ilert service account error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	return resourceServiceAccountRead(ctx, d, m)
+}
+
+func resourceServiceAccountDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	accountID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Deleting service account: %s", d.Id())
+
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err = client.DeleteServiceAccount(&ilert.DeleteServiceAccountInput{ServiceAccountID: ilert.Int64(accountID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for service account with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not delete a service account with id %s, error: %s", d.Id(), err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Deleting ilert service account error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceServiceAccountExists(d *schema.ResourceData, m any) (bool, error) {
+	client := m.(*providerMeta).client
+
+	accountID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return false, unconvertibleIDErr(d.Id(), err)
+	}
+	log.Printf("[DEBUG] Reading service account: %s", d.Id())
+
+	ctx := context.Background()
+	result := false
+	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err := client.GetServiceAccount(&ilert.GetServiceAccountInput{ServiceAccountID: ilert.Int64(accountID)})
+		if err != nil {
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				result = false
+				return nil
+			}
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				log.Printf("[ERROR] Reading ilert service account error '%s', so retry again", err.Error())
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for service account to be read, error: %s", err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not read a service account with ID %s, error: %s", d.Id(), err.Error()))
+		}
+		result = true
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("[ERROR] Reading ilert service account error: %s", err.Error())
+		return false, err
+	}
+	return result, nil
+}
+
+func transformServiceAccountResource(account *ilert.ServiceAccount, d *schema.ResourceData) error {
+	d.Set("name", account.Name)
+	d.Set("role", account.Role)
+	d.Set("custom_role", flattenCustomRole(account.CustomRole))
+
+	return nil
+}
@@ -40,8 +40,9 @@ func resourceStatusPage() *schema.Resource {
 				Required: true,
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimezone,
 			},
 			"custom_css": {
 				Type:        schema.TypeString,
@@ -517,7 +518,7 @@ func buildStatusPage(d *schema.ResourceData) (*ilert.StatusPage, error) {
 }
 
 func resourceStatusPageCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPage, err := buildStatusPage(d)
 	if err != nil {
@@ -557,7 +558,7 @@ func resourceStatusPageCreate(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceStatusPageRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -603,7 +604,7 @@ func resourceStatusPageRead(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceStatusPageUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPage, err := buildStatusPage(d)
 	if err != nil {
@@ -639,7 +640,7 @@ func resourceStatusPageUpdate(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceStatusPageDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -668,7 +669,7 @@ func resourceStatusPageDelete(ctx context.Context, d *schema.ResourceData, m any
 }
 
 func resourceStatusPageExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
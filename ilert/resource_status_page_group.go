@@ -74,7 +74,7 @@ func buildStatusPageGroup(d *schema.ResourceData) (*ilert.StatusPageGroup, *int6
 }
 
 func resourceStatusPageGroupCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageGroup, statusPageID, err := buildStatusPageGroup(d)
 	if err != nil {
@@ -120,7 +120,7 @@ func resourceStatusPageGroupCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceStatusPageGroupRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageGroupID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -174,7 +174,7 @@ func resourceStatusPageGroupRead(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceStatusPageGroupUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageGroup, statusPageID, err := buildStatusPageGroup(d)
 	if err != nil {
@@ -210,7 +210,7 @@ func resourceStatusPageGroupUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceStatusPageGroupDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageGroupID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -246,7 +246,7 @@ func resourceStatusPageGroupDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceStatusPageGroupExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	statusPageGroupID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
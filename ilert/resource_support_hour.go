@@ -44,8 +44,9 @@ func resourceSupportHour() *schema.Resource {
 				},
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTimezone,
 			},
 			"support_days": {
 				Type:     schema.TypeList,
@@ -117,12 +118,16 @@ func resourceSupportHour() *schema.Resource {
 							ValidateFunc: validation.StringLenBetween(1, 255),
 						},
 						"start": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 						"end": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateScheduleDateTime,
+							DiffSuppressFunc: suppressEquivalentScheduleDateTime,
 						},
 						"support_status": {
 							Type:         schema.TypeString,
@@ -133,6 +138,7 @@ func resourceSupportHour() *schema.Resource {
 					},
 				},
 			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		CreateContext: resourceSupportHourCreate,
 		ReadContext:   resourceSupportHourRead,
@@ -186,103 +192,108 @@ func buildSupportHour(d *schema.ResourceData) (*ilert.SupportHour, error) {
 	}
 
 	if val, ok := d.GetOk("support_days"); ok {
-		vL := val.([]any)
-		days := ilert.SupportDays{}
-		if len(vL) > 0 && vL[0] != nil {
-			v := vL[0].(map[string]any)
-			for d, sd := range v {
-				s := sd.([]any)
-				if len(s) > 0 && s[0] != nil {
-					ds := s[0].(map[string]any)
-					if d == "monday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.MONDAY = &day
+		supportHour.SupportDays = buildSupportDays(val.([]any))
+	}
+
+	if val, ok := d.GetOk("exception"); ok {
+		supportHour.Exceptions = buildSupportHourExceptions(val.([]any))
+	}
+
+	return supportHour, nil
+}
+
+func buildSupportDays(vL []any) *ilert.SupportDays {
+	days := ilert.SupportDays{}
+	if len(vL) > 0 && vL[0] != nil {
+		v := vL[0].(map[string]any)
+		for d, sd := range v {
+			s := sd.([]any)
+			if len(s) > 0 && s[0] != nil {
+				ds := s[0].(map[string]any)
+				if d == "monday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "tuesday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.TUESDAY = &day
+					days.MONDAY = &day
+				}
+				if d == "tuesday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "wednesday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.WEDNESDAY = &day
+					days.TUESDAY = &day
+				}
+				if d == "wednesday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "thursday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.THURSDAY = &day
+					days.WEDNESDAY = &day
+				}
+				if d == "thursday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "friday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.FRIDAY = &day
+					days.THURSDAY = &day
+				}
+				if d == "friday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "saturday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.SATURDAY = &day
+					days.FRIDAY = &day
+				}
+				if d == "saturday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
-					if d == "sunday" {
-						day := ilert.SupportDay{
-							Start: ds["start"].(string),
-							End:   ds["end"].(string),
-						}
-						days.SUNDAY = &day
+					days.SATURDAY = &day
+				}
+				if d == "sunday" {
+					day := ilert.SupportDay{
+						Start: ds["start"].(string),
+						End:   ds["end"].(string),
 					}
+					days.SUNDAY = &day
 				}
 			}
 		}
-		supportHour.SupportDays = &days
 	}
+	return &days
+}
 
-	if val, ok := d.GetOk("exception"); ok {
-		vL := val.([]any)
-		exceptions := make([]ilert.SupportHourException, 0, len(vL))
-		for _, exception := range vL {
-			if exception == nil {
-				continue
-			}
-
-			v := exception.(map[string]any)
-			ex := ilert.SupportHourException{
-				Start:         v["start"].(string),
-				End:           v["end"].(string),
-				SupportStatus: ilert.SupportStatus.During,
-			}
+func buildSupportHourExceptions(vL []any) []ilert.SupportHourException {
+	exceptions := make([]ilert.SupportHourException, 0, len(vL))
+	for _, exception := range vL {
+		if exception == nil {
+			continue
+		}
 
-			if v["name"] != nil && v["name"].(string) != "" {
-				ex.Name = v["name"].(string)
-			}
+		v := exception.(map[string]any)
+		ex := ilert.SupportHourException{
+			Start:         v["start"].(string),
+			End:           v["end"].(string),
+			SupportStatus: ilert.SupportStatus.During,
+		}
 
-			if v["support_status"] != nil && v["support_status"].(string) != "" {
-				ex.SupportStatus = v["support_status"].(string)
-			}
+		if v["name"] != nil && v["name"].(string) != "" {
+			ex.Name = v["name"].(string)
+		}
 
-			exceptions = append(exceptions, ex)
+		if v["support_status"] != nil && v["support_status"].(string) != "" {
+			ex.SupportStatus = v["support_status"].(string)
 		}
 
-		supportHour.Exceptions = exceptions
+		exceptions = append(exceptions, ex)
 	}
-
-	return supportHour, nil
+	return exceptions
 }
 
 func resourceSupportHourCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	supportHour, err := buildSupportHour(d)
 	if err != nil {
@@ -322,7 +333,7 @@ func resourceSupportHourCreate(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceSupportHourRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	supportHourID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -368,7 +379,7 @@ func resourceSupportHourRead(ctx context.Context, d *schema.ResourceData, m any)
 }
 
 func resourceSupportHourUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	supportHour, err := buildSupportHour(d)
 	if err != nil {
@@ -404,14 +415,18 @@ func resourceSupportHourUpdate(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceSupportHourDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	supportHourID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		log.Printf("[ERROR] Could not parse support hour id %s", err.Error())
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
+	if diags := checkDeletion(d, "support_hour"); diags.HasError() {
+		return diags
+	}
 	log.Printf("[DEBUG] Deleting support hour: %s", d.Id())
+	var apiErr error
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err = client.DeleteSupportHour(&ilert.DeleteSupportHourInput{SupportHourID: ilert.Int64(supportHourID)})
 		if err != nil {
@@ -419,13 +434,14 @@ func resourceSupportHourDelete(ctx context.Context, d *schema.ResourceData, m an
 				time.Sleep(2 * time.Second)
 				return resource.RetryableError(fmt.Errorf("waiting for support hour with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
 			}
+			apiErr = err
 			return resource.NonRetryableError(fmt.Errorf("could not delete an support hour with ID %s, error: %s", d.Id(), err.Error()))
 		}
 		return nil
 	})
 	if err != nil {
 		log.Printf("[ERROR] Deleting ilert support hour error %s", err.Error())
-		return diag.FromErr(err)
+		return deletionError(ctx, d, client, "support_hour", supportHourID, apiErr, err)
 	}
 
 	d.SetId("")
@@ -433,7 +449,7 @@ func resourceSupportHourDelete(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceSupportHourExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	supportHourID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -43,9 +43,16 @@ func resourceTeam() *schema.Resource {
 							Default:      ilert.TeamMemberRoles.Responder,
 							ValidateFunc: validation.StringInSlice(ilert.TeamMemberRolesAll, false),
 						},
+						"custom_role": customRoleSchema(),
 					},
 				},
 			},
+			"manage_members": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
 		CreateContext: resourceTeamCreate,
 		ReadContext:   resourceTeamRead,
@@ -91,6 +98,13 @@ func buildTeam(d *schema.ResourceData) (*ilert.Team, error) {
 					ID: userID,
 				}
 			}
+			if customRole, ok := v["custom_role"].(string); ok {
+				role, err := expandCustomRole(customRole)
+				if err != nil {
+					return nil, err
+				}
+				ep.CustomRole = role
+			}
 			members = append(members, ep)
 		}
 	}
@@ -100,7 +114,7 @@ func buildTeam(d *schema.ResourceData) (*ilert.Team, error) {
 }
 
 func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	team, err := buildTeam(d)
 	if err != nil {
@@ -138,7 +152,7 @@ func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m any) diag
 }
 
 func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	teamID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -185,7 +199,7 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m any) diag.D
 }
 
 func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	team, err := buildTeam(d)
 	if err != nil {
@@ -200,6 +214,21 @@ func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m any) diag
 	}
 	log.Printf("[DEBUG] Updating team: %s", d.Id())
 
+	if !d.Get("manage_members").(bool) {
+		// keep the members added outside of this resource, e.g. by
+		// ilert_team_membership, under the same lock those take
+		teamMemberLock.Lock(d.Id())
+		defer teamMemberLock.Unlock(d.Id())
+
+		current, err := getTeamForMembership(ctx, client, teamID, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			log.Printf("[ERROR] Reading ilert team members error %s", err.Error())
+			return diag.FromErr(err)
+		}
+		o, n := d.GetChange("member")
+		team.Members = mergeUnmanagedTeamMembers(team.Members, current.Members, teamMemberUsers(o), teamMemberUsers(n))
+	}
+
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
 		_, err = client.UpdateTeam(&ilert.UpdateTeamInput{Team: team, TeamID: ilert.Int64(teamID)})
 		if err != nil {
@@ -221,14 +250,18 @@ func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m any) diag
 }
 
 func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	teamID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		log.Printf("[ERROR] Could not parse team id %s", err.Error())
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
+	if diags := checkDeletion(d, "team"); diags.HasError() {
+		return diags
+	}
 	log.Printf("[DEBUG] Deleting team: %s", d.Id())
+	var apiErr error
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err = client.DeleteTeam(&ilert.DeleteTeamInput{TeamID: ilert.Int64(teamID)})
 		if err != nil {
@@ -236,13 +269,14 @@ func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m any) diag
 				time.Sleep(2 * time.Second)
 				return resource.RetryableError(fmt.Errorf("waiting for team with id '%s' to be deleted, error: %s", d.Id(), err.Error()))
 			}
+			apiErr = err
 			return resource.NonRetryableError(fmt.Errorf("could not delete an team with ID %s, error: %s", d.Id(), err.Error()))
 		}
 		return nil
 	})
 	if err != nil {
 		log.Printf("[ERROR] Deleting ilert team error %s", err.Error())
-		return diag.FromErr(err)
+		return deletionError(ctx, d, client, "team", teamID, apiErr, err)
 	}
 
 	d.SetId("")
@@ -250,7 +284,7 @@ func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m any) diag
 }
 
 func resourceTeamExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	teamID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -289,8 +323,11 @@ func transformTeamResource(team *ilert.Team, d *schema.ResourceData) error {
 	d.Set("name", team.Name)
 	d.Set("visibility", team.Visibility)
 
-	members := flattenMembersList(team.Members)
-	if err := d.Set("member", members); err != nil {
+	members := team.Members
+	if !d.Get("manage_members").(bool) {
+		members = managedTeamMembers(members, teamMemberUsers(d.Get("member")))
+	}
+	if err := d.Set("member", flattenMembersList(members)); err != nil {
 		return fmt.Errorf("[ERROR] Error setting members: %s", err.Error())
 	}
 
@@ -306,6 +343,7 @@ func flattenMembersList(list []ilert.TeamMember) []any {
 	for _, serverMember := range list {
 		result := make(map[string]any)
 		result["role"] = serverMember.Role
+		result["custom_role"] = flattenCustomRole(serverMember.CustomRole)
 		if serverMember.User.ID > 0 {
 			result["user"] = strconv.FormatInt(serverMember.User.ID, 10)
 		}
@@ -314,3 +352,47 @@ func flattenMembersList(list []ilert.TeamMember) []any {
 
 	return results
 }
+
+// teamMemberUsers returns the user ids of a member set.
+func teamMemberUsers(v any) map[int64]bool {
+	users := make(map[int64]bool)
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return users
+	}
+	for _, it := range set.List() {
+		member, _ := it.(map[string]any)
+		if user, _ := member["user"].(string); user != "" {
+			if userID, err := strconv.ParseInt(user, 10, 64); err == nil {
+				users[userID] = true
+			}
+		}
+	}
+	return users
+}
+
+// managedTeamMembers returns the members of a team that the configuration
+// manages when manage_members is false.
+func managedTeamMembers(members []ilert.TeamMember, managed map[int64]bool) []ilert.TeamMember {
+	result := make([]ilert.TeamMember, 0, len(members))
+	for _, member := range members {
+		if managed[member.User.ID] {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// mergeUnmanagedTeamMembers returns the configured members followed by the
+// current members of the team that the configuration neither declares nor
+// declared before the change, so that only members removed from the
+// configuration are removed from the team.
+func mergeUnmanagedTeamMembers(configured, current []ilert.TeamMember, oldUsers, newUsers map[int64]bool) []ilert.TeamMember {
+	members := append(make([]ilert.TeamMember, 0, len(configured)+len(current)), configured...)
+	for _, member := range current {
+		if !oldUsers[member.User.ID] && !newUsers[member.User.ID] {
+			members = append(members, member)
+		}
+	}
+	return members
+}
@@ -0,0 +1,313 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// The API only updates the members of a team as a whole, so adding or removing
+// a single member is a read-modify-write of the team. Memberships of the same
+// team are applied in parallel, so they are serialized per team id with this
+// in-process keyed mutex. ilert_team takes it as well when it merges the
+// members it does not manage.
+var teamMemberLock = newKeyedMutex()
+
+func resourceTeamMembership() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric team id",
+				),
+			},
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric user id",
+				),
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ilert.TeamMemberRoles.Responder,
+				ValidateFunc: validation.StringInSlice(ilert.TeamMemberRolesAll, false),
+			},
+			"custom_role": customRoleSchema(),
+		},
+		CreateContext: resourceTeamMembershipCreate,
+		ReadContext:   resourceTeamMembershipRead,
+		UpdateContext: resourceTeamMembershipUpdate,
+		DeleteContext: resourceTeamMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTeamMembershipImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceTeamMembershipCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	teamID, userID, err := teamMembershipIDs(d.Get("team_id").(string), d.Get("user").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Adding user %d to team %d", userID, teamID)
+
+	customRole, err := expandCustomRole(d.Get("custom_role").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := applyTeamMembership(ctx, m.(*providerMeta).client, teamID, userID, d.Get("role").(string), customRole, d.Timeout(schema.TimeoutCreate)); err != nil {
+		log.Printf("[ERROR] Adding team member error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId(teamMembershipID(teamID, userID))
+
+	return resourceTeamMembershipRead(ctx, d, m)
+}
+
+func resourceTeamMembershipRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	teamID, userID, err := parseTeamMembershipID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Reading team membership: %s", d.Id())
+
+	team, err := getTeamForMembership(ctx, client, teamID, d.Timeout(schema.TimeoutRead))
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			log.Printf("[WARN] Removing team membership %s from state because the team no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] Reading team membership error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	member := findTeamMember(team.Members, userID)
+	if member == nil {
+		log.Printf("[WARN] Removing team membership %s from state because the user is no longer a member", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("team_id", strconv.FormatInt(teamID, 10))
+	d.Set("user", strconv.FormatInt(userID, 10))
+	d.Set("role", member.Role)
+	d.Set("custom_role", flattenCustomRole(member.CustomRole))
+
+	return nil
+}
+
+func resourceTeamMembershipUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	teamID, userID, err := parseTeamMembershipID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Updating team membership: %s", d.Id())
+
+	customRole, err := expandCustomRole(d.Get("custom_role").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := applyTeamMembership(ctx, m.(*providerMeta).client, teamID, userID, d.Get("role").(string), customRole, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		log.Printf("[ERROR] Updating team member error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	return resourceTeamMembershipRead(ctx, d, m)
+}
+
+func resourceTeamMembershipDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	teamID, userID, err := parseTeamMembershipID(d.Id())
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Removing user %d from team %d", userID, teamID)
+
+	teamKey := strconv.FormatInt(teamID, 10)
+	teamMemberLock.Lock(teamKey)
+	defer teamMemberLock.Unlock(teamKey)
+
+	team, err := getTeamForMembership(ctx, client, teamID, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			log.Printf("[WARN] Team %d not found, treating removal of user %d as success", teamID, userID)
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] Removing team member error %s", err.Error())
+		return diag.FromErr(err)
+	}
+	if findTeamMember(team.Members, userID) == nil {
+		log.Printf("[WARN] User %d is no longer a member of team %d, treating as success", userID, teamID)
+		d.SetId("")
+		return nil
+	}
+
+	members := make([]ilert.TeamMember, 0, len(team.Members))
+	for _, member := range team.Members {
+		if member.User.ID != userID {
+			members = append(members, member)
+		}
+	}
+	if err := updateTeamMembers(ctx, client, teamID, team, members, d.Timeout(schema.TimeoutDelete)); err != nil {
+		log.Printf("[ERROR] Removing team member error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceTeamMembershipImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	teamID, userID, err := parseTeamMembershipID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("team_id", strconv.FormatInt(teamID, 10))
+	d.Set("user", strconv.FormatInt(userID, 10))
+	return []*schema.ResourceData{d}, nil
+}
+
+// applyTeamMembership adds the user to the team with the given roles, or sets the
+// roles of the user when it is a member already, keeping all other members.
+func applyTeamMembership(ctx context.Context, client *ilert.Client, teamID, userID int64, role string, customRole *ilert.CustomRole, timeout time.Duration) error {
+	teamKey := strconv.FormatInt(teamID, 10)
+	teamMemberLock.Lock(teamKey)
+	defer teamMemberLock.Unlock(teamKey)
+
+	team, err := getTeamForMembership(ctx, client, teamID, timeout)
+	if err != nil {
+		return err
+	}
+
+	members := make([]ilert.TeamMember, 0, len(team.Members)+1)
+	found := false
+	for _, member := range team.Members {
+		if member.User.ID == userID {
+			member.Role = role
+			member.CustomRole = customRole
+			found = true
+		}
+		members = append(members, member)
+	}
+	if !found {
+		members = append(members, ilert.TeamMember{User: ilert.User{ID: userID}, Role: role, CustomRole: customRole})
+	}
+	return updateTeamMembers(ctx, client, teamID, team, members, timeout)
+}
+
+func getTeamForMembership(ctx context.Context, client *ilert.Client, teamID int64, timeout time.Duration) (*ilert.Team, error) {
+	result := &ilert.GetTeamOutput{}
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		r, err := client.GetTeam(&ilert.GetTeamInput{TeamID: ilert.Int64(teamID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for team with id '%d' to be read, error: %s", teamID, err.Error()))
+			}
+			return resource.NonRetryableError(err)
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.Team == nil {
+		return nil, fmt.Errorf("team response is empty")
+	}
+	return result.Team, nil
+}
+
+// updateTeamMembers writes the members of a team read with
+// getTeamForMembership, leaving its other attributes as they are.
+func updateTeamMembers(ctx context.Context, client *ilert.Client, teamID int64, team *ilert.Team, members []ilert.TeamMember, timeout time.Duration) error {
+	update := &ilert.Team{
+		Name:       team.Name,
+		Visibility: team.Visibility,
+		Members:    members,
+	}
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		_, err := client.UpdateTeam(&ilert.UpdateTeamInput{Team: update, TeamID: ilert.Int64(teamID)})
+		if err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting for members of team with id '%d' to be updated, error: %s", teamID, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not update the members of team with ID %d, error: %s", teamID, err.Error()))
+		}
+		return nil
+	})
+}
+
+func findTeamMember(members []ilert.TeamMember, userID int64) *ilert.TeamMember {
+	for i := range members {
+		if members[i].User.ID == userID {
+			return &members[i]
+		}
+	}
+	return nil
+}
+
+func teamMembershipIDs(team, user string) (teamID, userID int64, err error) {
+	teamID, err = strconv.ParseInt(team, 10, 64)
+	if err != nil {
+		return 0, 0, unconvertibleIDErr(team, err)
+	}
+	userID, err = strconv.ParseInt(user, 10, 64)
+	if err != nil {
+		return 0, 0, unconvertibleIDErr(user, err)
+	}
+	return teamID, userID, nil
+}
+
+func teamMembershipID(teamID, userID int64) string {
+	return fmt.Sprintf("%d/%d", teamID, userID)
+}
+
+func parseTeamMembershipID(id string) (teamID, userID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return 0, 0, fmt.Errorf("expected ID in the form '<team_id>/<user_id>', got %q", id)
+	}
+	teamID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid team_id %q in ID %q: %s", parts[0], id, err.Error())
+	}
+	userID, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid user_id %q in ID %q: %s", parts[1], id, err.Error())
+	}
+	return teamID, userID, nil
+}
@@ -0,0 +1,32 @@
+package ilert
+
+import (
+	"testing"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestParseTeamMembershipID(t *testing.T) {
+	teamID, userID, err := parseTeamMembershipID(teamMembershipID(12, 345))
+	if err != nil || teamID != 12 || userID != 345 {
+		t.Fatalf("unexpected result %d, %d, %v", teamID, userID, err)
+	}
+	for _, id := range []string{"12", "12/", "/345", "12/345/6", "team/345", "12/user"} {
+		if _, _, err := parseTeamMembershipID(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
+
+func TestFindTeamMember(t *testing.T) {
+	members := []ilert.TeamMember{
+		newTeamMember(100, ilert.TeamMemberRoles.User),
+		newTeamMember(200, ilert.TeamMemberRoles.Admin),
+	}
+	if member := findTeamMember(members, 200); member == nil || member.Role != ilert.TeamMemberRoles.Admin {
+		t.Fatalf("unexpected member %v", member)
+	}
+	if member := findTeamMember(members, 300); member != nil {
+		t.Fatalf("expected no member, got %v", member)
+	}
+}
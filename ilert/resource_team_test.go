@@ -40,6 +40,92 @@ func TestTeamMembersRoundTrip(t *testing.T) {
 	}
 }
 
+func TestTeamUnmanagedMembers(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]any{
+		"name":           "test-team",
+		"manage_members": false,
+		"member": []any{
+			map[string]any{"user": "100", "role": ilert.TeamMemberRoles.User},
+		},
+	})
+	team := &ilert.Team{
+		Name:       "test-team",
+		Visibility: ilert.TeamVisibility.Public,
+		Members: []ilert.TeamMember{
+			newTeamMember(200, ilert.TeamMemberRoles.Responder),
+			newTeamMember(100, ilert.TeamMemberRoles.Admin),
+		},
+	}
+
+	if err := transformTeamResource(team, d); err != nil {
+		t.Fatalf("unexpected error transforming team: %v", err)
+	}
+	members := d.Get("member").(*schema.Set).List()
+	if len(members) != 1 || members[0].(map[string]any)["user"] != "100" || members[0].(map[string]any)["role"] != ilert.TeamMemberRoles.Admin {
+		t.Fatalf("expected only the managed member to be read, got %v", members)
+	}
+
+	// user 300 was removed from the configuration, user 400 is new and user
+	// 200 was added by another module
+	current := []ilert.TeamMember{
+		newTeamMember(200, ilert.TeamMemberRoles.Responder),
+		newTeamMember(100, ilert.TeamMemberRoles.Admin),
+		newTeamMember(300, ilert.TeamMemberRoles.User),
+	}
+	configured := []ilert.TeamMember{
+		newTeamMember(100, ilert.TeamMemberRoles.User),
+		newTeamMember(400, ilert.TeamMemberRoles.User),
+	}
+	got := mergeUnmanagedTeamMembers(configured, current, map[int64]bool{100: true, 300: true}, map[int64]bool{100: true, 400: true})
+	want := []ilert.TeamMember{configured[0], configured[1], current[0]}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d members, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("member %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestTeamMemberCustomRoleRoundTrip(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTeam().Schema, map[string]any{})
+	custom := newTeamMember(100, ilert.TeamMemberRoles.User)
+	custom.CustomRole = &ilert.CustomRole{ID: 7, Name: "Incident Commander"}
+	team := &ilert.Team{
+		Name:       "test-team",
+		Visibility: ilert.TeamVisibility.Public,
+		Members: []ilert.TeamMember{
+			custom,
+			newTeamMember(200, ilert.TeamMemberRoles.Responder),
+		},
+	}
+
+	if err := transformTeamResource(team, d); err != nil {
+		t.Fatalf("unexpected error transforming team: %v", err)
+	}
+	got, err := buildTeam(d)
+	if err != nil {
+		t.Fatalf("unexpected error building team: %v", err)
+	}
+
+	wantRoles := map[int64]int64{100: 7, 200: 0}
+	if len(got.Members) != len(wantRoles) {
+		t.Fatalf("expected %d members, got %d", len(wantRoles), len(got.Members))
+	}
+	for _, member := range got.Members {
+		if wantRoles[member.User.ID] == 0 {
+			if member.CustomRole != nil {
+				t.Fatalf("expected member %d without a custom role, got %+v", member.User.ID, member.CustomRole)
+			}
+			continue
+		}
+		if member.CustomRole == nil || member.CustomRole.ID != wantRoles[member.User.ID] {
+			t.Fatalf("expected member %d with custom role %d, got %+v", member.User.ID, wantRoles[member.User.ID], member.CustomRole)
+		}
+	}
+}
+
 func newTeamMember(userID int64, role string) ilert.TeamMember {
 	return ilert.TeamMember{
 		User: ilert.User{
@@ -233,7 +233,7 @@ func buildUptimeMonitor(d *schema.ResourceData) (*ilert.UptimeMonitor, error) {
 }
 
 func resourceUptimeMonitorCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	uptimeMonitor, err := buildUptimeMonitor(d)
 	if err != nil {
@@ -272,7 +272,7 @@ func resourceUptimeMonitorCreate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceUptimeMonitorRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	uptimeMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -353,7 +353,7 @@ func resourceUptimeMonitorRead(ctx context.Context, d *schema.ResourceData, m an
 }
 
 func resourceUptimeMonitorUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	uptimeMonitor, err := buildUptimeMonitor(d)
 	if err != nil {
@@ -389,7 +389,7 @@ func resourceUptimeMonitorUpdate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceUptimeMonitorDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	uptimeMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -419,7 +419,7 @@ func resourceUptimeMonitorDelete(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceUptimeMonitorExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	uptimeMonitorID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -14,6 +14,10 @@ import (
 	"github.com/iLert/ilert-go/v3"
 )
 
+// userInvitationStatusPending is the invitation_status of users who have not
+// accepted their invitation yet.
+const userInvitationStatusPending = "PENDING"
+
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
@@ -34,9 +38,10 @@ func resourceUser() *schema.Resource {
 				Required: true,
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Europe/Berlin",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Europe/Berlin",
+				ValidateFunc: validateTimezone,
 			},
 			"position": {
 				Type:     schema.TypeString,
@@ -72,6 +77,7 @@ func resourceUser() *schema.Resource {
 					"VIEWER",
 				}, false),
 			},
+			"custom_role": customRoleSchema(),
 			"shift_color": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -80,12 +86,26 @@ func resourceUser() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"resend_invitation_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"invitation_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_login": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"on_destroy": userOnDestroySchema(),
 		},
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
 		DeleteContext: resourceUserDelete,
 		Exists:        resourceUserExists,
+		CustomizeDiff: customizeUserOnDestroy,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -133,6 +153,14 @@ func buildUser(d *schema.ResourceData) (*ilert.User, error) {
 		user.Role = val.(string)
 	}
 
+	if val, ok := d.GetOk("custom_role"); ok {
+		customRole, err := expandCustomRole(val.(string))
+		if err != nil {
+			return nil, err
+		}
+		user.CustomRole = customRole
+	}
+
 	if val, ok := d.GetOk("shift_color"); ok {
 		user.ShiftColor = val.(string)
 	}
@@ -141,7 +169,7 @@ func buildUser(d *schema.ResourceData) (*ilert.User, error) {
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	user, err := buildUser(d)
 	if err != nil {
@@ -185,7 +213,7 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m any) diag
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	userID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -231,7 +259,7 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m any) diag.D
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	user, err := buildUser(d)
 	if err != nil {
@@ -243,35 +271,85 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m any) diag
 	if err != nil {
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
-	log.Printf("[DEBUG] Updating user: %s", d.Id())
+	// on_destroy is only read when the user is destroyed and
+	// resend_invitation_trigger only resends the invitation
+	if d.HasChangesExcept("on_destroy", "resend_invitation_trigger") {
+		log.Printf("[DEBUG] Updating user: %s", d.Id())
+
+		err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+			_, err = client.UpdateUser(&ilert.UpdateUserInput{User: user, UserID: ilert.Int64(userID)})
+			if err != nil {
+				if _, ok := err.(*ilert.RetryableAPIError); ok {
+					time.Sleep(2 * time.Second)
+					return resource.RetryableError(fmt.Errorf("waiting for user with id '%s' to be updated, error: %s", d.Id(), err.Error()))
+				}
+				return resource.NonRetryableError(fmt.Errorf("could not update a user with id %s, error: %s", d.Id(), err.Error()))
+			}
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("[ERROR] Updating ilert user error %s", err.Error())
+			return diag.FromErr(err)
+		}
+	}
+
+	var diags diag.Diagnostics
+	if d.HasChange("resend_invitation_trigger") {
+		diags = resendUserInvitation(ctx, d, client, userID)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return append(diags, resourceUserRead(ctx, d, m)...)
+}
+
+// resendUserInvitation sends the invitation to a user again. Users who
+// accepted their invitation already get a warning instead.
+func resendUserInvitation(ctx context.Context, d *schema.ResourceData, client *ilert.Client, userID int64) diag.Diagnostics {
+	if status := d.Get("invitation_status").(string); status != "" && status != userInvitationStatusPending {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The invitation of user %q was not resent", d.Get("email").(string)),
+				Detail:   fmt.Sprintf("Its invitation_status is %s, so there is no pending invitation to resend.", status),
+			},
+		}
+	}
+	log.Printf("[INFO] Resending the invitation of user %d", userID)
 
-	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-		_, err = client.UpdateUser(&ilert.UpdateUserInput{User: user, UserID: ilert.Int64(userID)})
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		_, err := client.ResendUserInvitation(&ilert.ResendUserInvitationInput{UserID: ilert.Int64(userID)})
 		if err != nil {
 			if _, ok := err.(*ilert.RetryableAPIError); ok {
 				time.Sleep(2 * time.Second)
-				return resource.RetryableError(fmt.Errorf("waiting for user with id '%s' to be updated, error: %s", d.Id(), err.Error()))
+				return resource.RetryableError(fmt.Errorf("waiting for the invitation of user with id '%s' to be resent, error: %s", d.Id(), err.Error()))
 			}
-			return resource.NonRetryableError(fmt.Errorf("could not update a user with id %s, error: %s", d.Id(), err.Error()))
+			return resource.NonRetryableError(fmt.Errorf("could not resend the invitation of user with id %s, error: %s", d.Id(), err.Error()))
 		}
 		return nil
 	})
-
 	if err != nil {
-		log.Printf("[ERROR] Updating ilert user error %s", err.Error())
+		log.Printf("[ERROR] Resending ilert user invitation error %s", err.Error())
 		return diag.FromErr(err)
 	}
-
-	return resourceUserRead(ctx, d, m)
+	return nil
 }
 
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	userID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
 		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
 	}
+	// references left in place with block_if_referenced = false come back
+	// as warnings
+	diags := offboardUser(ctx, d, client, userID)
+	if diags.HasError() {
+		return diags
+	}
 	log.Printf("[DEBUG] Deleting user: %s", d.Id())
 
 	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
@@ -291,11 +369,11 @@ func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m any) diag
 	}
 
 	d.SetId("")
-	return nil
+	return diags
 }
 
 func resourceUserExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	userID, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -341,7 +419,10 @@ func transformUserResource(user *ilert.User, d *schema.ResourceData) error {
 	d.Set("language", user.Language)
 	d.Set("region", user.Region)
 	d.Set("role", user.Role)
+	d.Set("custom_role", flattenCustomRole(user.CustomRole))
 	d.Set("shift_color", user.ShiftColor)
+	d.Set("invitation_status", user.InvitationStatus)
+	d.Set("last_login", user.LastLogin)
 
 	return nil
 }
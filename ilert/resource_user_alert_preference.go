@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"time"
 
@@ -61,11 +62,16 @@ func resourceUserAlertPreference() *schema.Resource {
 					},
 				},
 			},
+			"contact_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 		CreateContext: resourceUserAlertPreferenceCreate,
 		ReadContext:   resourceUserAlertPreferenceRead,
 		UpdateContext: resourceUserAlertPreferenceUpdate,
 		DeleteContext: resourceUserAlertPreferenceDelete,
+		CustomizeDiff: customizeUserAlertPreference,
 		Exists:        resourceUserAlertPreferenceExists,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -119,7 +125,7 @@ func buildUserAlertPreference(d *schema.ResourceData) (*ilert.UserAlertPreferenc
 }
 
 func resourceUserAlertPreferenceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserAlertPreference(d)
 	if err != nil {
@@ -160,11 +166,83 @@ func resourceUserAlertPreferenceCreate(ctx context.Context, d *schema.ResourceDa
 	usr = append(usr, u)
 	d.Set("user", usr)
 
-	return resourceUserAlertPreferenceRead(ctx, d, m)
+	diags := resourceUserAlertPreferenceRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, userAlertPreferenceContactWarnings(d)...)
+}
+
+// customizeUserAlertPreference plans contact_status when a new or changed
+// preference notifies a contact, so a contact that is not verified shows up
+// in the plan.
+func customizeUserAlertPreference(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if diff.Id() != "" && !diff.HasChanges("method", "contact", "user") {
+		return nil
+	}
+	if !diff.NewValueKnown("method") || !diffValuesKnown(diff, "contact") || !diffValuesKnown(diff, "user") {
+		return diff.SetNewComputed("contact_status")
+	}
+	status, ok := userAlertPreferenceContactStatus(ctx, diff.Get, m, contactStatusPlanTimeout)
+	if !ok {
+		return diff.SetNewComputed("contact_status")
+	}
+	return diff.SetNew("contact_status", status)
+}
+
+// userAlertPreferenceContactStatus returns the status of the contact the
+// preference notifies, empty without a contact, for a method whose contacts
+// have no status or when the contact no longer exists. ok is false when the
+// status could not be read.
+func userAlertPreferenceContactStatus(ctx context.Context, get func(string) any, m any, timeout time.Duration) (string, bool) {
+	contact, _ := get("contact").([]any)
+	user, _ := get("user").([]any)
+	if len(contact) == 0 || contact[0] == nil || len(user) == 0 || user[0] == nil {
+		return "", true
+	}
+	client := providerClient(m)
+	if client == nil {
+		return "", false
+	}
+	method, _ := get("method").(string)
+	phone := slices.Contains(notificationPolicyPhoneMethods, method)
+	if !phone && method != "EMAIL" {
+		return "", true
+	}
+	contactId := int64(contact[0].(map[string]any)["id"].(int))
+	userId := int64(user[0].(map[string]any)["id"].(int))
+
+	status, _, err := userContactStatus(ctx, client, userId, contactId, phone, timeout)
+	if err != nil {
+		log.Printf("[WARN] Could not check the status of contact %d of user %d: %s", contactId, userId, err.Error())
+		return "", false
+	}
+	return status, true
+}
+
+// userAlertPreferenceContactWarnings warns when the applied preference
+// notifies a contact that is not verified.
+func userAlertPreferenceContactWarnings(d *schema.ResourceData) diag.Diagnostics {
+	contact, _ := d.Get("contact").([]any)
+	if len(contact) == 0 || contact[0] == nil {
+		return nil
+	}
+	contactId := contact[0].(map[string]any)["id"].(int)
+	warning := unverifiedContactWarning("The alert preference", strconv.Itoa(contactId), d.Get("contact_status").(string))
+	if warning == "" {
+		return nil
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Alert preference notifies an unverified contact",
+			Detail:   warning,
+		},
+	}
 }
 
 func resourceUserAlertPreferenceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -207,30 +285,18 @@ func resourceUserAlertPreferenceRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("user alert preference response is empty")
 	}
 
-	d.Set("method", result.UserAlertPreference.Method)
-
-	contact, err := flattenUserContactShort(result.UserAlertPreference.Contact)
-	if err != nil {
+	if err := transformUserAlertPreferenceResource(result.UserAlertPreference, userId, d); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("contact", contact); err != nil {
-		return diag.Errorf("error setting contact: %s", err)
+	if status, ok := userAlertPreferenceContactStatus(ctx, d.Get, m, d.Timeout(schema.TimeoutRead)); ok {
+		d.Set("contact_status", status)
 	}
 
-	d.Set("delay_min", result.UserAlertPreference.DelayMin)
-	d.Set("type", result.UserAlertPreference.Type)
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
-
 	return nil
 }
 
 func resourceUserAlertPreferenceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserAlertPreference(d)
 	if err != nil {
@@ -261,11 +327,15 @@ func resourceUserAlertPreferenceUpdate(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	return resourceUserAlertPreferenceRead(ctx, d, m)
+	diags := resourceUserAlertPreferenceRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, userAlertPreferenceContactWarnings(d)...)
 }
 
 func resourceUserAlertPreferenceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -300,7 +370,7 @@ func resourceUserAlertPreferenceDelete(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceUserAlertPreferenceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -355,3 +425,26 @@ func flattenUserContactShort(contact *ilert.UserContactShort) ([]any, error) {
 
 	return results, nil
 }
+
+func transformUserAlertPreferenceResource(preference *ilert.UserAlertPreference, userID int64, d *schema.ResourceData) error {
+	d.Set("method", preference.Method)
+
+	contact, err := flattenUserContactShort(preference.Contact)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("contact", contact); err != nil {
+		return fmt.Errorf("error setting contact: %s", err)
+	}
+
+	d.Set("delay_min", preference.DelayMin)
+	d.Set("type", preference.Type)
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
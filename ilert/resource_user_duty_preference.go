@@ -119,7 +119,7 @@ func buildUserDutyPreference(d *schema.ResourceData) (*ilert.UserDutyPreference,
 }
 
 func resourceUserDutyPreferenceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserDutyPreference(d)
 	if err != nil {
@@ -164,7 +164,7 @@ func resourceUserDutyPreferenceCreate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceUserDutyPreferenceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -207,30 +207,15 @@ func resourceUserDutyPreferenceRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("user duty preference response is empty")
 	}
 
-	d.Set("method", result.UserDutyPreference.Method)
-
-	contact, err := flattenUserContactShort(result.UserDutyPreference.Contact)
-	if err != nil {
+	if err := transformUserDutyPreferenceResource(result.UserDutyPreference, userId, d); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("contact", contact); err != nil {
-		return diag.Errorf("error setting contact: %s", err)
-	}
-
-	d.Set("before_min", result.UserDutyPreference.BeforeMin)
-	d.Set("type", result.UserDutyPreference.Type)
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
 
 	return nil
 }
 
 func resourceUserDutyPreferenceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserDutyPreference(d)
 	if err != nil {
@@ -265,7 +250,7 @@ func resourceUserDutyPreferenceUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceUserDutyPreferenceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -300,7 +285,7 @@ func resourceUserDutyPreferenceDelete(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceUserDutyPreferenceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -340,3 +325,26 @@ func resourceUserDutyPreferenceExists(d *schema.ResourceData, m any) (bool, erro
 	}
 	return result, nil
 }
+
+func transformUserDutyPreferenceResource(preference *ilert.UserDutyPreference, userID int64, d *schema.ResourceData) error {
+	d.Set("method", preference.Method)
+
+	contact, err := flattenUserContactShort(preference.Contact)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("contact", contact); err != nil {
+		return fmt.Errorf("error setting contact: %s", err)
+	}
+
+	d.Set("before_min", preference.BeforeMin)
+	d.Set("type", preference.Type)
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
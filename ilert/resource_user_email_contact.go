@@ -26,6 +26,8 @@ func resourceUserEmailContact() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"send_verification":     sendVerificationSchema(),
+			"wait_for_verification": waitForVerificationSchema(),
 			"user": {
 				Type:     schema.TypeList,
 				Required: true,
@@ -77,7 +79,7 @@ func buildUserEmailContact(d *schema.ResourceData) (*ilert.UserEmailContact, *in
 }
 
 func resourceUserEmailContactCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contact, userId, err := buildUserEmailContact(d)
 	if err != nil {
@@ -118,11 +120,17 @@ func resourceUserEmailContactCreate(ctx context.Context, d *schema.ResourceData,
 	usr = append(usr, u)
 	d.Set("user", usr)
 
-	return resourceUserEmailContactRead(ctx, d, m)
+	diags := verifyUserEmailContact(ctx, d, client, *userId, result.UserEmailContact.ID, d.Timeout(schema.TimeoutCreate))
+	if diags.HasError() {
+		log.Printf("[ERROR] Verifying ilert user email contact error %s", diags[0].Summary)
+		return diags
+	}
+
+	return append(diags, resourceUserEmailContactRead(ctx, d, m)...)
 }
 
 func resourceUserEmailContactRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -165,20 +173,15 @@ func resourceUserEmailContactRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("user email contact response is empty")
 	}
 
-	d.Set("target", result.UserEmailContact.Target)
-	d.Set("status", result.UserEmailContact.Status)
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
+	if err := transformUserEmailContactResource(result.UserEmailContact, userId, d); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
 func resourceUserEmailContactUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contact, userId, err := buildUserEmailContact(d)
 	if err != nil {
@@ -209,11 +212,17 @@ func resourceUserEmailContactUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	return resourceUserEmailContactRead(ctx, d, m)
+	diags := verifyUserEmailContact(ctx, d, client, *userId, contactId, d.Timeout(schema.TimeoutUpdate))
+	if diags.HasError() {
+		log.Printf("[ERROR] Verifying ilert user email contact error %s", diags[0].Summary)
+		return diags
+	}
+
+	return append(diags, resourceUserEmailContactRead(ctx, d, m)...)
 }
 
 func resourceUserEmailContactDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -248,7 +257,7 @@ func resourceUserEmailContactDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceUserEmailContactExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -288,3 +297,35 @@ func resourceUserEmailContactExists(d *schema.ResourceData, m any) (bool, error)
 	}
 	return result, nil
 }
+
+func verifyUserEmailContact(ctx context.Context, d *schema.ResourceData, client *ilert.Client, userId, contactId int64, timeout time.Duration) diag.Diagnostics {
+	return verifyContact(ctx, d, fmt.Sprintf("user email contact %d", contactId), timeout,
+		func() error {
+			_, err := client.SendUserEmailContactVerification(&ilert.SendUserEmailContactVerificationInput{UserID: ilert.Int64(userId), UserEmailContactID: ilert.Int64(contactId)})
+			return err
+		},
+		func() (string, error) {
+			r, err := client.GetUserEmailContact(&ilert.GetUserEmailContactInput{UserID: ilert.Int64(userId), UserEmailContactID: ilert.Int64(contactId)})
+			if err != nil {
+				return "", err
+			}
+			if r == nil || r.UserEmailContact == nil {
+				return "", fmt.Errorf("user email contact response is empty")
+			}
+			return r.UserEmailContact.Status, nil
+		},
+	)
+}
+
+func transformUserEmailContactResource(contact *ilert.UserEmailContact, userID int64, d *schema.ResourceData) error {
+	d.Set("target", contact.Target)
+	d.Set("status", contact.Status)
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
@@ -0,0 +1,808 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func resourceUserNotificationProfile() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[0-9]+$`),
+					"must be a numeric user id",
+				),
+			},
+			"email_contact": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"target": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 255),
+						},
+					},
+				},
+			},
+			"phone_contact": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"region_code": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"target": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 255),
+						},
+					},
+				},
+			},
+			"contact_status": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"contact_warnings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"alert_preference":        notificationPreferenceSchema(notificationPreferenceAlert, true),
+			"duty_preference":         notificationPreferenceSchema(notificationPreferenceDuty, true),
+			"update_preference":       notificationPreferenceSchema(notificationPreferenceUpdate, true),
+			"subscription_preference": notificationPreferenceSchema(notificationPreferenceSubscription, true),
+		},
+		CreateContext: resourceUserNotificationProfileCreate,
+		ReadContext:   resourceUserNotificationProfileRead,
+		UpdateContext: resourceUserNotificationProfileUpdate,
+		DeleteContext: resourceUserNotificationProfileDelete,
+		CustomizeDiff: customizeUserNotificationProfile,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserNotificationProfileImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// notificationPreferenceSchema returns the set of preference blocks of the
+// given kind. Without a contact, the blocks only describe the rule, see
+// ilert_notification_policy.
+func notificationPreferenceSchema(kind string, withContact bool) *schema.Schema {
+	elem := map[string]*schema.Schema{}
+	switch kind {
+	case notificationPreferenceAlert:
+		elem["method"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserAlertPreferenceMethodAll, false),
+		}
+		elem["delay_min"] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntBetween(0, 120),
+		}
+		elem["type"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserAlertPreferenceTypeAll, false),
+		}
+	case notificationPreferenceDuty:
+		elem["method"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserDutyPreferenceMethodAll, false),
+		}
+		elem["before_min"] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntInSlice([]int{0, 15, 30, 60, 180, 360, 720, 1440}),
+		}
+		elem["type"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserDutyPreferenceTypeAll, false),
+		}
+	case notificationPreferenceUpdate:
+		elem["method"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserUpdatePreferenceMethodAll, false),
+		}
+		elem["type"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserUpdatePreferenceTypeAll, false),
+		}
+	case notificationPreferenceSubscription:
+		elem["method"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(ilert.UserSubscriptionPreferenceMethodAll, false),
+		}
+	}
+	if withContact {
+		elem["contact"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The target of an email_contact or phone_contact of this profile",
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: elem,
+		},
+	}
+}
+
+func customizeUserNotificationProfile(ctx context.Context, d *schema.ResourceDiff, m any) error {
+	keys := []string{"email_contact", "phone_contact"}
+	for _, kind := range notificationPreferenceKinds {
+		keys = append(keys, kind+"_preference")
+	}
+	for _, key := range keys {
+		if !diffValuesKnown(d, key) {
+			return d.SetNewComputed("contact_warnings")
+		}
+	}
+	profile, err := expandNotificationProfile(d.Get)
+	if err != nil {
+		return err
+	}
+	// contact_status is only known for contacts that exist already, the
+	// warnings about new ones are only known after the apply
+	status, _ := d.Get("contact_status").(map[string]any)
+	if !notificationProfileContactStatusKnown(profile.preferences, status) {
+		return d.SetNewComputed("contact_warnings")
+	}
+	return d.SetNew("contact_warnings", notificationProfileContactWarnings(profile.preferences, status))
+}
+
+func resourceUserNotificationProfileCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	userID, err := strconv.ParseInt(d.Get("user").(string), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Get("user").(string), err))
+	}
+	log.Printf("[INFO] Creating notification profile of user %d", userID)
+
+	if err := applyUserNotificationProfile(ctx, d, m.(*providerMeta).client, userID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		log.Printf("[ERROR] Creating ilert user notification profile error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.FormatInt(userID, 10))
+
+	return notificationProfileWarnings(d, resourceUserNotificationProfileRead(ctx, d, m))
+}
+
+func resourceUserNotificationProfileRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	userID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Reading notification profile of user %d", userID)
+
+	profile, err := readUserNotificationProfile(ctx, client, userID, d.Timeout(schema.TimeoutRead))
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			log.Printf("[WARN] Removing user notification profile %s from state because the user no longer exist", d.Id())
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] Reading ilert user notification profile error: %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.Set("user", strconv.FormatInt(userID, 10))
+	if err := flattenNotificationProfile(d, profile); err != nil {
+		return diag.Errorf("[ERROR] Error setting notification profile: %s", err)
+	}
+
+	return nil
+}
+
+func resourceUserNotificationProfileUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	userID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Updating notification profile of user %d", userID)
+
+	if err := applyUserNotificationProfile(ctx, d, m.(*providerMeta).client, userID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		log.Printf("[ERROR] Updating ilert user notification profile error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	return notificationProfileWarnings(d, resourceUserNotificationProfileRead(ctx, d, m))
+}
+
+// notificationProfileWarnings appends a warning for every entry of
+// contact_warnings to the diagnostics of a read.
+func notificationProfileWarnings(d *schema.ResourceData, diags diag.Diagnostics) diag.Diagnostics {
+	if diags.HasError() || d.Id() == "" {
+		return diags
+	}
+	warnings, _ := d.Get("contact_warnings").([]any)
+	for _, warning := range warnings {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Alert preference notifies an unverified contact",
+			Detail:   warning.(string),
+		})
+	}
+	return diags
+}
+
+// resourceUserNotificationProfileDelete removes the preferences and contacts
+// the profile manages. Whatever was added to the user after the last apply is
+// left alone.
+func resourceUserNotificationProfileDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	userID, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.FromErr(unconvertibleIDErr(d.Id(), err))
+	}
+	log.Printf("[DEBUG] Deleting notification profile of user %d", userID)
+
+	managed, err := expandNotificationProfile(d.Get)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	current, err := readUserNotificationProfile(ctx, client, userID, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			log.Printf("[WARN] User %d not found, treating deletion of its notification profile as success", userID)
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] Deleting ilert user notification profile error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	// planning the removal of everything managed leaves exactly the managed
+	// contacts and preferences that still exist to delete
+	plan := planNotificationProfile(notificationProfile{}, notificationProfile{
+		contacts:    managedNotificationContacts(current.contacts, managed.contacts),
+		preferences: managedNotificationPreferences(current.preferences, managed.preferences),
+	})
+	if _, err := applyNotificationProfilePlan(ctx, client, userID, plan, current.contacts, d.Timeout(schema.TimeoutDelete)); err != nil {
+		log.Printf("[ERROR] Deleting ilert user notification profile error %s", err.Error())
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceUserNotificationProfileImport(ctx context.Context, d *schema.ResourceData, m any) ([]*schema.ResourceData, error) {
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		return nil, unconvertibleIDErr(d.Id(), err)
+	}
+	d.Set("user", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func applyUserNotificationProfile(ctx context.Context, d *schema.ResourceData, client *ilert.Client, userID int64, timeout time.Duration) error {
+	desired, err := expandNotificationProfile(d.Get)
+	if err != nil {
+		return err
+	}
+	current, err := readUserNotificationProfile(ctx, client, userID, timeout)
+	if err != nil {
+		return err
+	}
+
+	plan := planNotificationProfile(desired, current)
+	if plan.empty() {
+		return nil
+	}
+	_, err = applyNotificationProfilePlan(ctx, client, userID, plan, current.contacts, timeout)
+	return err
+}
+
+func expandNotificationProfile(get func(string) any) (notificationProfile, error) {
+	profile := notificationProfile{}
+
+	for _, v := range get("email_contact").(*schema.Set).List() {
+		c := v.(map[string]any)
+		profile.contacts = append(profile.contacts, notificationContact{target: c["target"].(string)})
+	}
+	for _, v := range get("phone_contact").(*schema.Set).List() {
+		c := v.(map[string]any)
+		contact := notificationContact{
+			phone:      true,
+			regionCode: c["region_code"].(string),
+			target:     c["target"].(string),
+		}
+		if _, err := normalizePhoneNumber(contact.regionCode, contact.target); err != nil {
+			return profile, fmt.Errorf("invalid phone_contact: %s", err.Error())
+		}
+		profile.contacts = append(profile.contacts, contact)
+	}
+	seen := make(map[string]bool, len(profile.contacts))
+	for _, contact := range profile.contacts {
+		if seen[contact.key()] {
+			return profile, fmt.Errorf("the contact %s is listed more than once", contact.target)
+		}
+		seen[contact.key()] = true
+	}
+
+	profile.preferences = expandNotificationPreferences(get)
+
+	// a preference may name a phone contact in its national format
+	keys := make(map[string]string, len(profile.contacts))
+	for _, contact := range profile.contacts {
+		keys[notificationContactKey(contact.target)] = contact.key()
+	}
+	for i, preference := range profile.preferences {
+		if key, ok := keys[preference.contact]; ok {
+			profile.preferences[i].contact = key
+		}
+	}
+
+	return profile, validateNotificationPreferences(profile.contacts, profile.preferences)
+}
+
+// expandNotificationPreferences reads the preference blocks of all kinds.
+// Blocks without a contact argument expand to preferences without a contact.
+func expandNotificationPreferences(get func(string) any) []notificationPreference {
+	preferences := make([]notificationPreference, 0)
+	for _, kind := range notificationPreferenceKinds {
+		set, ok := get(kind + "_preference").(*schema.Set)
+		if !ok {
+			continue
+		}
+		for _, v := range set.List() {
+			p := v.(map[string]any)
+			preference := notificationPreference{
+				kind:   kind,
+				method: p["method"].(string),
+			}
+			if contact, ok := p["contact"].(string); ok {
+				preference.contact = notificationContactKey(contact)
+			}
+			if t, ok := p["type"].(string); ok {
+				preference.preferenceType = t
+			}
+			if minutes, ok := p["delay_min"].(int); ok {
+				preference.minutes = minutes
+			}
+			if minutes, ok := p["before_min"].(int); ok {
+				preference.minutes = minutes
+			}
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences
+}
+
+// flattenNotificationProfile sets the contacts and preferences of the profile,
+// spelling targets the way the configuration does when they only differ in
+// case or in the formatting of phone numbers.
+func flattenNotificationProfile(d *schema.ResourceData, profile notificationProfile) error {
+	spelling := make(map[string]string)
+	if configured, err := expandNotificationProfile(d.Get); err == nil {
+		for _, contact := range configured.contacts {
+			spelling[contact.key()] = contact.target
+		}
+	}
+	target := func(key string) string {
+		if s, ok := spelling[key]; ok {
+			return s
+		}
+		return key
+	}
+
+	emails := make([]any, 0)
+	phones := make([]any, 0)
+	status := make(map[string]any, len(profile.contacts))
+	for _, contact := range profile.contacts {
+		status[target(contact.key())] = contact.status
+		if contact.phone {
+			phones = append(phones, map[string]any{"region_code": contact.regionCode, "target": target(contact.key())})
+			continue
+		}
+		emails = append(emails, map[string]any{"target": target(contact.key())})
+	}
+	if err := d.Set("email_contact", emails); err != nil {
+		return err
+	}
+	if err := d.Set("phone_contact", phones); err != nil {
+		return err
+	}
+	if err := d.Set("contact_status", status); err != nil {
+		return err
+	}
+	if err := d.Set("contact_warnings", notificationProfileContactWarnings(profile.preferences, status)); err != nil {
+		return err
+	}
+
+	preferences := make(map[string][]any, len(notificationPreferenceKinds))
+	for _, kind := range notificationPreferenceKinds {
+		preferences[kind] = make([]any, 0)
+	}
+	for _, preference := range profile.preferences {
+		p := map[string]any{
+			"method":  preference.method,
+			"contact": "",
+		}
+		if preference.contact != "" {
+			p["contact"] = target(preference.contact)
+		}
+		switch preference.kind {
+		case notificationPreferenceAlert:
+			p["type"] = preference.preferenceType
+			p["delay_min"] = preference.minutes
+		case notificationPreferenceDuty:
+			p["type"] = preference.preferenceType
+			p["before_min"] = preference.minutes
+		case notificationPreferenceUpdate:
+			p["type"] = preference.preferenceType
+		}
+		preferences[preference.kind] = append(preferences[preference.kind], p)
+	}
+	for _, kind := range notificationPreferenceKinds {
+		if err := d.Set(kind+"_preference", preferences[kind]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// managedNotificationContacts returns the current contacts that are listed in
+// managed.
+func managedNotificationContacts(current, managed []notificationContact) []notificationContact {
+	keys := make(map[string]bool, len(managed))
+	for _, contact := range managed {
+		keys[contact.key()] = true
+	}
+	result := make([]notificationContact, 0)
+	for _, contact := range current {
+		if keys[contact.key()] {
+			result = append(result, contact)
+		}
+	}
+	return result
+}
+
+// managedNotificationPreferences returns the current preferences that are
+// listed in managed, each at most as often as it is listed.
+func managedNotificationPreferences(current, managed []notificationPreference) []notificationPreference {
+	keys := make(map[string]int, len(managed))
+	for _, preference := range managed {
+		keys[preference.key()]++
+	}
+	result := make([]notificationPreference, 0)
+	for _, preference := range current {
+		if keys[preference.key()] > 0 {
+			keys[preference.key()]--
+			result = append(result, preference)
+		}
+	}
+	return result
+}
+
+// readUserNotificationProfile lists the email and phone number contacts and
+// all preferences of a user. Preferences reference contacts by key; those
+// referencing any other kind of contact get a key no configured contact can
+// have, so planNotificationProfile removes them.
+func readUserNotificationProfile(ctx context.Context, client *ilert.Client, userID int64, timeout time.Duration) (notificationProfile, error) {
+	profile := notificationProfile{}
+	what := fmt.Sprintf("read the notification profile of user %d", userID)
+
+	emails := &ilert.GetUserEmailContactsOutput{}
+	phones := &ilert.GetUserPhoneNumberContactsOutput{}
+	alerts := &ilert.GetUserAlertPreferencesOutput{}
+	duties := &ilert.GetUserDutyPreferencesOutput{}
+	updates := &ilert.GetUserUpdatePreferencesOutput{}
+	subscriptions := &ilert.GetUserSubscriptionPreferencesOutput{}
+	err := retryNotificationProfile(ctx, timeout, what, func() error {
+		var err error
+		if emails, err = client.GetUserEmailContacts(&ilert.GetUserEmailContactsInput{UserID: ilert.Int64(userID)}); err != nil {
+			return err
+		}
+		if phones, err = client.GetUserPhoneNumberContacts(&ilert.GetUserPhoneNumberContactsInput{UserID: ilert.Int64(userID)}); err != nil {
+			return err
+		}
+		if alerts, err = client.GetUserAlertPreferences(&ilert.GetUserAlertPreferencesInput{UserID: ilert.Int64(userID)}); err != nil {
+			return err
+		}
+		if duties, err = client.GetUserDutyPreferences(&ilert.GetUserDutyPreferencesInput{UserID: ilert.Int64(userID)}); err != nil {
+			return err
+		}
+		if updates, err = client.GetUserUpdatePreferences(&ilert.GetUserUpdatePreferencesInput{UserID: ilert.Int64(userID)}); err != nil {
+			return err
+		}
+		subscriptions, err = client.GetUserSubscriptionPreferences(&ilert.GetUserSubscriptionPreferencesInput{UserID: ilert.Int64(userID)})
+		return err
+	})
+	if err != nil {
+		return profile, err
+	}
+
+	contactKeys := make(map[int64]string)
+	for _, c := range emails.UserEmailContacts {
+		contact := notificationContact{id: c.ID, target: c.Target, status: c.Status}
+		contactKeys[c.ID] = contact.key()
+		profile.contacts = append(profile.contacts, contact)
+	}
+	for _, c := range phones.UserPhoneNumberContacts {
+		contact := notificationContact{id: c.ID, phone: true, regionCode: c.RegionCode, target: c.Target, status: c.Status}
+		contactKeys[c.ID] = contact.key()
+		profile.contacts = append(profile.contacts, contact)
+	}
+	contactKey := func(contact *ilert.UserContactShort) string {
+		if contact == nil || contact.ID == 0 {
+			return ""
+		}
+		if key, ok := contactKeys[contact.ID]; ok {
+			return key
+		}
+		return fmt.Sprintf("#%d", contact.ID)
+	}
+
+	for _, p := range alerts.UserAlertPreferences {
+		profile.preferences = append(profile.preferences, notificationPreference{
+			kind: notificationPreferenceAlert, id: p.ID, method: p.Method, contact: contactKey(p.Contact), preferenceType: p.Type, minutes: int(p.DelayMin),
+		})
+	}
+	for _, p := range duties.UserDutyPreferences {
+		profile.preferences = append(profile.preferences, notificationPreference{
+			kind: notificationPreferenceDuty, id: p.ID, method: p.Method, contact: contactKey(p.Contact), preferenceType: p.Type, minutes: int(p.BeforeMin),
+		})
+	}
+	for _, p := range updates.UserUpdatePreferences {
+		profile.preferences = append(profile.preferences, notificationPreference{
+			kind: notificationPreferenceUpdate, id: p.ID, method: p.Method, contact: contactKey(p.Contact), preferenceType: p.Type,
+		})
+	}
+	for _, p := range subscriptions.UserSubscriptionPreferences {
+		profile.preferences = append(profile.preferences, notificationPreference{
+			kind: notificationPreferenceSubscription, id: p.ID, method: p.Method, contact: contactKey(p.Contact),
+		})
+	}
+
+	return profile, nil
+}
+
+// applyNotificationProfilePlan makes the changes of the plan in the order
+// described on notificationProfilePlan. contacts are the current contacts of
+// the user, which new preferences may reference. Preferences and contacts that are
+// already gone count as deleted. It returns the preferences it created with
+// their ids, also when a later change fails.
+func applyNotificationProfilePlan(ctx context.Context, client *ilert.Client, userID int64, plan notificationProfilePlan, contacts []notificationContact, timeout time.Duration) ([]notificationPreference, error) {
+	contactIDs := make(map[string]int64, len(contacts))
+	for _, contact := range contacts {
+		contactIDs[contact.key()] = contact.id
+	}
+	for _, contact := range plan.createContacts {
+		id, err := createNotificationContact(ctx, client, userID, contact, timeout)
+		if err != nil {
+			return nil, err
+		}
+		contactIDs[contact.key()] = id
+	}
+	for _, contact := range plan.updateContacts {
+		what := fmt.Sprintf("update the phone number contact %s of user %d", contact.target, userID)
+		err := retryNotificationProfile(ctx, timeout, what, func() error {
+			_, err := client.UpdateUserPhoneNumberContact(&ilert.UpdateUserPhoneNumberContactInput{
+				UserPhoneNumberContact:   &ilert.UserPhoneNumberContact{RegionCode: contact.regionCode, Target: contact.target},
+				UserPhoneNumberContactID: ilert.Int64(contact.id),
+				UserID:                   ilert.Int64(userID),
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created := make([]notificationPreference, 0, len(plan.createPreferences))
+	for _, preference := range plan.createPreferences {
+		id, err := createNotificationPreference(ctx, client, userID, preference, contactIDs, timeout)
+		if err != nil {
+			return created, err
+		}
+		preference.id = id
+		created = append(created, preference)
+	}
+	for _, preference := range plan.deletePreferences {
+		if err := deleteNotificationPreference(ctx, client, userID, preference, timeout); err != nil {
+			return created, err
+		}
+	}
+
+	for _, contact := range plan.deleteContacts {
+		what := fmt.Sprintf("delete the contact %s of user %d", contact.target, userID)
+		err := retryNotificationProfile(ctx, timeout, what, func() error {
+			var err error
+			if contact.phone {
+				_, err = client.DeleteUserPhoneNumberContact(&ilert.DeleteUserPhoneNumberContactInput{UserPhoneNumberContactID: ilert.Int64(contact.id), UserID: ilert.Int64(userID)})
+			} else {
+				_, err = client.DeleteUserEmailContact(&ilert.DeleteUserEmailContactInput{UserEmailContactID: ilert.Int64(contact.id), UserID: ilert.Int64(userID)})
+			}
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+func createNotificationContact(ctx context.Context, client *ilert.Client, userID int64, contact notificationContact, timeout time.Duration) (int64, error) {
+	var id int64
+	what := fmt.Sprintf("create the contact %s of user %d", contact.target, userID)
+	err := retryNotificationProfile(ctx, timeout, what, func() error {
+		if contact.phone {
+			r, err := client.CreateUserPhoneNumberContact(&ilert.CreateUserPhoneNumberContactInput{
+				UserPhoneNumberContact: &ilert.UserPhoneNumberContact{RegionCode: contact.regionCode, Target: contact.target},
+				UserID:                 ilert.Int64(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if r == nil || r.UserPhoneNumberContact == nil {
+				return fmt.Errorf("user phone number contact response is empty")
+			}
+			id = r.UserPhoneNumberContact.ID
+			return nil
+		}
+		r, err := client.CreateUserEmailContact(&ilert.CreateUserEmailContactInput{
+			UserEmailContact: &ilert.UserEmailContact{Target: contact.target},
+			UserID:           ilert.Int64(userID),
+		})
+		if err != nil {
+			return err
+		}
+		if r == nil || r.UserEmailContact == nil {
+			return fmt.Errorf("user email contact response is empty")
+		}
+		id = r.UserEmailContact.ID
+		return nil
+	})
+	return id, err
+}
+
+func createNotificationPreference(ctx context.Context, client *ilert.Client, userID int64, preference notificationPreference, contactIDs map[string]int64, timeout time.Duration) (int64, error) {
+	var contact *ilert.UserContactShort
+	if preference.contact != "" {
+		id, ok := contactIDs[preference.contact]
+		if !ok {
+			return 0, fmt.Errorf("could not create the %s of user %d: contact %s not found", preference, userID, preference.contact)
+		}
+		contact = &ilert.UserContactShort{ID: id}
+	}
+
+	var id int64
+	what := fmt.Sprintf("create the %s of user %d", preference, userID)
+	err := retryNotificationProfile(ctx, timeout, what, func() error {
+		switch preference.kind {
+		case notificationPreferenceAlert:
+			r, err := client.CreateUserAlertPreference(&ilert.CreateUserAlertPreferenceInput{
+				UserAlertPreference: &ilert.UserAlertPreference{Method: preference.method, Contact: contact, DelayMin: int64(preference.minutes), Type: preference.preferenceType},
+				UserID:              ilert.Int64(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if r == nil || r.UserAlertPreference == nil {
+				return fmt.Errorf("user alert preference response is empty")
+			}
+			id = r.UserAlertPreference.ID
+		case notificationPreferenceDuty:
+			r, err := client.CreateUserDutyPreference(&ilert.CreateUserDutyPreferenceInput{
+				UserDutyPreference: &ilert.UserDutyPreference{Method: preference.method, Contact: contact, BeforeMin: int64(preference.minutes), Type: preference.preferenceType},
+				UserID:             ilert.Int64(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if r == nil || r.UserDutyPreference == nil {
+				return fmt.Errorf("user duty preference response is empty")
+			}
+			id = r.UserDutyPreference.ID
+		case notificationPreferenceUpdate:
+			r, err := client.CreateUserUpdatePreference(&ilert.CreateUserUpdatePreferenceInput{
+				UserUpdatePreference: &ilert.UserUpdatePreference{Method: preference.method, Contact: contact, Type: preference.preferenceType},
+				UserID:               ilert.Int64(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if r == nil || r.UserUpdatePreference == nil {
+				return fmt.Errorf("user update preference response is empty")
+			}
+			id = r.UserUpdatePreference.ID
+		case notificationPreferenceSubscription:
+			r, err := client.CreateUserSubscriptionPreference(&ilert.CreateUserSubscriptionPreferenceInput{
+				UserSubscriptionPreference: &ilert.UserSubscriptionPreference{Method: preference.method, Contact: contact},
+				UserID:                     ilert.Int64(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if r == nil || r.UserSubscriptionPreference == nil {
+				return fmt.Errorf("user subscription preference response is empty")
+			}
+			id = r.UserSubscriptionPreference.ID
+		}
+		return nil
+	})
+	return id, err
+}
+
+func deleteNotificationPreference(ctx context.Context, client *ilert.Client, userID int64, preference notificationPreference, timeout time.Duration) error {
+	what := fmt.Sprintf("delete the %s of user %d", preference, userID)
+	return retryNotificationProfile(ctx, timeout, what, func() error {
+		var err error
+		switch preference.kind {
+		case notificationPreferenceAlert:
+			_, err = client.DeleteUserAlertPreference(&ilert.DeleteUserAlertPreferenceInput{UserAlertPreferenceID: ilert.Int64(preference.id), UserID: ilert.Int64(userID)})
+		case notificationPreferenceDuty:
+			_, err = client.DeleteUserDutyPreference(&ilert.DeleteUserDutyPreferenceInput{UserDutyPreferenceID: ilert.Int64(preference.id), UserID: ilert.Int64(userID)})
+		case notificationPreferenceUpdate:
+			_, err = client.DeleteUserUpdatePreference(&ilert.DeleteUserUpdatePreferenceInput{UserUpdatePreferenceID: ilert.Int64(preference.id), UserID: ilert.Int64(userID)})
+		case notificationPreferenceSubscription:
+			_, err = client.DeleteUserSubscriptionPreference(&ilert.DeleteUserSubscriptionPreferenceInput{UserSubscriptionPreferenceID: ilert.Int64(preference.id), UserID: ilert.Int64(userID)})
+		}
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			return nil
+		}
+		return err
+	})
+}
+
+// retryNotificationProfile calls f until it succeeds, retrying while the API
+// returns retryable errors. what describes the call for error messages.
+func retryNotificationProfile(ctx context.Context, timeout time.Duration, what string, f func() error) error {
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		if err := f(); err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting to %s, error: %s", what, err.Error()))
+			}
+			if _, ok := err.(*ilert.NotFoundAPIError); ok {
+				return resource.NonRetryableError(err)
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not %s, error: %s", what, err.Error()))
+		}
+		return nil
+	})
+}
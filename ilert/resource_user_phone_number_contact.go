@@ -22,14 +22,17 @@ func resourceUserPhoneNumberContact() *schema.Resource {
 				Required: true,
 			},
 			"target": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringLenBetween(1, 255),
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringLenBetween(1, 255),
+				DiffSuppressFunc: suppressEquivalentPhoneNumber,
 			},
 			"status": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"send_verification":     sendVerificationSchema(),
+			"wait_for_verification": waitForVerificationSchema(),
 			"user": {
 				Type:     schema.TypeList,
 				Required: true,
@@ -50,6 +53,7 @@ func resourceUserPhoneNumberContact() *schema.Resource {
 		UpdateContext: resourceUserPhoneNumberContactUpdate,
 		DeleteContext: resourceUserPhoneNumberContactDelete,
 		Exists:        resourceUserPhoneNumberContactExists,
+		CustomizeDiff: customizeUserPhoneNumberContact,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -83,7 +87,7 @@ func buildUserPhoneNumberContact(d *schema.ResourceData) (*ilert.UserPhoneNumber
 }
 
 func resourceUserPhoneNumberContactCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contact, userId, err := buildUserPhoneNumberContact(d)
 	if err != nil {
@@ -124,11 +128,17 @@ func resourceUserPhoneNumberContactCreate(ctx context.Context, d *schema.Resourc
 	usr = append(usr, u)
 	d.Set("user", usr)
 
-	return resourceUserPhoneNumberContactRead(ctx, d, m)
+	diags := verifyUserPhoneNumberContact(ctx, d, client, *userId, result.UserPhoneNumberContact.ID, d.Timeout(schema.TimeoutCreate))
+	if diags.HasError() {
+		log.Printf("[ERROR] Verifying ilert user phone number contact error %s", diags[0].Summary)
+		return diags
+	}
+
+	return append(diags, resourceUserPhoneNumberContactRead(ctx, d, m)...)
 }
 
 func resourceUserPhoneNumberContactRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -171,21 +181,15 @@ func resourceUserPhoneNumberContactRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("user phone number contact response is empty")
 	}
 
-	d.Set("region_code", result.UserPhoneNumberContact.RegionCode)
-	d.Set("target", result.UserPhoneNumberContact.Target)
-	d.Set("status", result.UserPhoneNumberContact.Status)
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
+	if err := transformUserPhoneNumberContactResource(result.UserPhoneNumberContact, userId, d); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
 func resourceUserPhoneNumberContactUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contact, userId, err := buildUserPhoneNumberContact(d)
 	if err != nil {
@@ -216,11 +220,17 @@ func resourceUserPhoneNumberContactUpdate(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	return resourceUserPhoneNumberContactRead(ctx, d, m)
+	diags := verifyUserPhoneNumberContact(ctx, d, client, *userId, contactId, d.Timeout(schema.TimeoutUpdate))
+	if diags.HasError() {
+		log.Printf("[ERROR] Verifying ilert user phone number contact error %s", diags[0].Summary)
+		return diags
+	}
+
+	return append(diags, resourceUserPhoneNumberContactRead(ctx, d, m)...)
 }
 
 func resourceUserPhoneNumberContactDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -255,7 +265,7 @@ func resourceUserPhoneNumberContactDelete(ctx context.Context, d *schema.Resourc
 }
 
 func resourceUserPhoneNumberContactExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	contactId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -295,3 +305,48 @@ func resourceUserPhoneNumberContactExists(d *schema.ResourceData, m any) (bool,
 	}
 	return result, nil
 }
+
+// customizeUserPhoneNumberContact checks the target against the region at plan
+// time, once both are known.
+func customizeUserPhoneNumberContact(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diffValuesKnown(diff, "region_code") || !diffValuesKnown(diff, "target") {
+		return nil
+	}
+	if _, err := normalizePhoneNumber(diff.Get("region_code").(string), diff.Get("target").(string)); err != nil {
+		return fmt.Errorf("invalid target: %s", err.Error())
+	}
+	return nil
+}
+
+func verifyUserPhoneNumberContact(ctx context.Context, d *schema.ResourceData, client *ilert.Client, userId, contactId int64, timeout time.Duration) diag.Diagnostics {
+	return verifyContact(ctx, d, fmt.Sprintf("user phone number contact %d", contactId), timeout,
+		func() error {
+			_, err := client.SendUserPhoneNumberContactVerification(&ilert.SendUserPhoneNumberContactVerificationInput{UserID: ilert.Int64(userId), UserPhoneNumberContactID: ilert.Int64(contactId)})
+			return err
+		},
+		func() (string, error) {
+			r, err := client.GetUserPhoneNumberContact(&ilert.GetUserPhoneNumberContactInput{UserID: ilert.Int64(userId), UserPhoneNumberContactID: ilert.Int64(contactId)})
+			if err != nil {
+				return "", err
+			}
+			if r == nil || r.UserPhoneNumberContact == nil {
+				return "", fmt.Errorf("user phone number contact response is empty")
+			}
+			return r.UserPhoneNumberContact.Status, nil
+		},
+	)
+}
+
+func transformUserPhoneNumberContactResource(contact *ilert.UserPhoneNumberContact, userID int64, d *schema.ResourceData) error {
+	d.Set("region_code", contact.RegionCode)
+	d.Set("target", contact.Target)
+	d.Set("status", contact.Status)
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
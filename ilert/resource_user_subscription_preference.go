@@ -105,7 +105,7 @@ func buildUserSubscriptionPreference(d *schema.ResourceData) (*ilert.UserSubscri
 }
 
 func resourceUserSubscriptionPreferenceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserSubscriptionPreference(d)
 	if err != nil {
@@ -150,7 +150,7 @@ func resourceUserSubscriptionPreferenceCreate(ctx context.Context, d *schema.Res
 }
 
 func resourceUserSubscriptionPreferenceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -193,27 +193,15 @@ func resourceUserSubscriptionPreferenceRead(ctx context.Context, d *schema.Resou
 		return diag.Errorf("user subscription preference response is empty")
 	}
 
-	d.Set("method", result.UserSubscriptionPreference.Method)
-
-	contact, err := flattenUserContactShort(result.UserSubscriptionPreference.Contact)
-	if err != nil {
+	if err := transformUserSubscriptionPreferenceResource(result.UserSubscriptionPreference, userId, d); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("contact", contact); err != nil {
-		return diag.Errorf("error setting contact: %s", err)
-	}
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
 
 	return nil
 }
 
 func resourceUserSubscriptionPreferenceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserSubscriptionPreference(d)
 	if err != nil {
@@ -248,7 +236,7 @@ func resourceUserSubscriptionPreferenceUpdate(ctx context.Context, d *schema.Res
 }
 
 func resourceUserSubscriptionPreferenceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -283,7 +271,7 @@ func resourceUserSubscriptionPreferenceDelete(ctx context.Context, d *schema.Res
 }
 
 func resourceUserSubscriptionPreferenceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -323,3 +311,23 @@ func resourceUserSubscriptionPreferenceExists(d *schema.ResourceData, m any) (bo
 	}
 	return result, nil
 }
+
+func transformUserSubscriptionPreferenceResource(preference *ilert.UserSubscriptionPreference, userID int64, d *schema.ResourceData) error {
+	d.Set("method", preference.Method)
+
+	contact, err := flattenUserContactShort(preference.Contact)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("contact", contact); err != nil {
+		return fmt.Errorf("error setting contact: %s", err)
+	}
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
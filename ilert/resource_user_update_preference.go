@@ -112,7 +112,7 @@ func buildUserUpdatePreference(d *schema.ResourceData) (*ilert.UserUpdatePrefere
 }
 
 func resourceUserUpdatePreferenceCreate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserUpdatePreference(d)
 	if err != nil {
@@ -157,7 +157,7 @@ func resourceUserUpdatePreferenceCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceUserUpdatePreferenceRead(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -200,28 +200,15 @@ func resourceUserUpdatePreferenceRead(ctx context.Context, d *schema.ResourceDat
 		return diag.Errorf("user update preference response is empty")
 	}
 
-	d.Set("method", result.UserUpdatePreference.Method)
-	d.Set("type", result.UserUpdatePreference.Type)
-
-	contact, err := flattenUserContactShort(result.UserUpdatePreference.Contact)
-	if err != nil {
+	if err := transformUserUpdatePreferenceResource(result.UserUpdatePreference, userId, d); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("contact", contact); err != nil {
-		return diag.Errorf("error setting contact: %s", err)
-	}
-
-	usr := make([]any, 0)
-	u := make(map[string]any, 0)
-	u["id"] = int(userId)
-	usr = append(usr, u)
-	d.Set("user", usr)
 
 	return nil
 }
 
 func resourceUserUpdatePreferenceUpdate(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preference, userId, err := buildUserUpdatePreference(d)
 	if err != nil {
@@ -256,7 +243,7 @@ func resourceUserUpdatePreferenceUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceUserUpdatePreferenceDelete(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -291,7 +278,7 @@ func resourceUserUpdatePreferenceDelete(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceUserUpdatePreferenceExists(d *schema.ResourceData, m any) (bool, error) {
-	client := m.(*ilert.Client)
+	client := m.(*providerMeta).client
 
 	preferenceId, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -331,3 +318,24 @@ func resourceUserUpdatePreferenceExists(d *schema.ResourceData, m any) (bool, er
 	}
 	return result, nil
 }
+
+func transformUserUpdatePreferenceResource(preference *ilert.UserUpdatePreference, userID int64, d *schema.ResourceData) error {
+	d.Set("method", preference.Method)
+	d.Set("type", preference.Type)
+
+	contact, err := flattenUserContactShort(preference.Contact)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("contact", contact); err != nil {
+		return fmt.Errorf("error setting contact: %s", err)
+	}
+
+	usr := make([]any, 0)
+	u := make(map[string]any, 0)
+	u["id"] = int(userID)
+	usr = append(usr, u)
+	d.Set("user", usr)
+
+	return nil
+}
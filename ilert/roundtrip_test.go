@@ -0,0 +1,566 @@
+package ilert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// roundTripSamples is how many random configurations each resource is checked
+// with. The generator is seeded, so a failure reproduces on every run.
+const roundTripSamples = 25
+
+// roundTripMaxRejected is how many of the samples build may reject before the
+// generator is considered to miss the valid configurations of the resource.
+const roundTripMaxRejected = roundTripSamples / 5
+
+// roundTripInputs are the attributes the transformers read to decide how to
+// store the teams of an object, so they are given to every transformer.
+var roundTripInputs = []string{"team", "teams"}
+
+// roundTripCase describes how to check a resource of the provider. config
+// pins attributes the generator would pick inconsistently, such as the type
+// that decides which block of parameters applies, and omit lists the
+// attributes the generator leaves out, such as the blocks of other types.
+// inputs lists the attributes the transformer reads from the configuration.
+// configOnly lists the attributes that never reach the API, such as a
+// sample_event only rendered locally. Any other attribute that does not survive
+// build, JSON and transform is a drift bug: it is either not sent or not read
+// back. factory and transformer stand in for the entry of resourceRegistry for
+// resources the exporter does not list.
+type roundTripCase struct {
+	config      map[string]any
+	omit        []string
+	inputs      []string
+	configOnly  []string
+	build       func(d *schema.ResourceData) (any, error)
+	factory     func() any
+	transformer Transformer
+}
+
+// roundTripOwnedObject is the payload of an object that belongs to a user or a
+// service account, next to the id of its owner, which the API takes from the
+// path rather than from the payload.
+type roundTripOwnedObject[T any] struct {
+	Object  *T
+	OwnerID int64
+}
+
+// roundTripUserObjectCase checks an object of a user, whose build returns the
+// id of the user next to the payload.
+func roundTripUserObjectCase[T any](config map[string]any, configOnly []string, build func(*schema.ResourceData) (*T, *int64, error), transform func(*T, int64, *schema.ResourceData) error) roundTripCase {
+	return roundTripCase{
+		config:     config,
+		configOnly: configOnly,
+		build: func(d *schema.ResourceData) (any, error) {
+			object, userID, err := build(d)
+			if err != nil {
+				return nil, err
+			}
+			return &roundTripOwnedObject[T]{Object: object, OwnerID: *userID}, nil
+		},
+		factory: func() any { return &roundTripOwnedObject[T]{} },
+		transformer: func(e any, d *schema.ResourceData) error {
+			o := e.(*roundTripOwnedObject[T])
+			return transform(o.Object, o.OwnerID, d)
+		},
+	}
+}
+
+// roundTripSkipped lists the resources of the provider that are not checked,
+// with the reason. Every other resource needs a roundTripCase.
+var roundTripSkipped = map[string]string{
+	"ilert_alert_action_source_attachment": "attaches an alert source to an alert action, it has no payload of its own",
+	"ilert_automation_rule":                "deprecated",
+	"ilert_connection":                     "deprecated",
+	"ilert_directory_sync":                 "plans changes to many users instead of sending a payload",
+	"ilert_notification_policy":            "plans changes to the preferences of many users instead of sending a payload",
+	"ilert_schedule_override":              "replaces the override list of a schedule, covered by its own tests",
+	"ilert_team_membership":                "changes the member list of a team, covered by its own tests",
+	"ilert_uptime_monitor":                 "deprecated",
+	"ilert_user_notification_profile":      "sends a request per contact and preference, covered by its own tests",
+}
+
+var roundTripCases = map[string]roundTripCase{
+	"ilert_alert_action": {
+		config: map[string]any{
+			"name":                       "test-alert-action",
+			"connector":                  []any{map[string]any{"id": "1", "type": "slack"}},
+			"delay_sec":                  60,
+			"escalation_ended_delay_sec": 60,
+			"not_resolved_delay_sec":     300,
+		},
+		omit: []string{
+			"jira", "servicenow", "webhook", "zendesk", "github", "topdesk", "email", "autotask", "zammad",
+			"dingtalk", "dingtalk_action", "automation_rule", "telegram", "microsoft_teams_bot",
+			"microsoft_teams_webhook", "slack_webhook", "reroute",
+		},
+		inputs: []string{"alert_source"},
+		// compiled into conditions, which is what the API returns
+		configOnly: []string{"condition"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildAlertAction(d) },
+	},
+	"ilert_alert_source": {
+		config: map[string]any{
+			"name":              "test-alert-source",
+			"integration_type":  "API",
+			"escalation_policy": "1",
+		},
+		inputs:     []string{"filter_operator", "resolve_filter_operator", "services", "support_hours"},
+		configOnly: []string{"sample_event"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildAlertSource(d) },
+	},
+	"ilert_call_flow": {
+		config: map[string]any{"name": "test-call-flow"},
+		// a random string is not a flow definition
+		omit:  []string{"definition"},
+		build: func(d *schema.ResourceData) (any, error) { return buildCallFlow(d) },
+	},
+	"ilert_connector": {
+		config: map[string]any{"name": "test-connector", "type": "jira"},
+		omit: []string{
+			"servicenow", "microsoft_teams", "zendesk", "discord", "github", "topdesk", "autotask",
+			"mattermost", "zammad", "dingtalk",
+		},
+		build: func(d *schema.ResourceData) (any, error) { return buildConnector(d) },
+	},
+	"ilert_deployment_pipeline": {
+		config: map[string]any{"name": "test-deployment-pipeline"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildDeploymentPipeline(d) },
+	},
+	"ilert_escalation_policy": {
+		config:     map[string]any{"name": "test-escalation-policy"},
+		configOnly: []string{"deletion_protection"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildEscalationPolicy(d) },
+	},
+	"ilert_event_flow": {
+		config: map[string]any{"name": "test-event-flow"},
+		omit:   []string{"definition"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildEventFlow(d) },
+	},
+	"ilert_event_flow_integration": {
+		config: map[string]any{"event_flow_id": 1},
+		build:  func(d *schema.ResourceData) (any, error) { return buildEventFlowIntegration(d), nil },
+	},
+	"ilert_heartbeat_monitor": {
+		config: map[string]any{"name": "test-heartbeat-monitor", "interval_sec": 3600},
+		inputs: []string{"alert_summary", "alert_source"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildHeartbeatMonitor(d) },
+	},
+	"ilert_incident_template": {
+		config: map[string]any{
+			"name":    "test-incident-template",
+			"summary": "summary",
+			"message": "message",
+			"status":  "INVESTIGATING",
+		},
+		build: func(d *schema.ResourceData) (any, error) { return buildIncidentTemplate(d) },
+	},
+	"ilert_metric": {
+		config: map[string]any{"name": "test-metric"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildMetric(d) },
+	},
+	"ilert_metric_data_source": {
+		config: map[string]any{"name": "test-metric-data-source"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildMetricDataSource(d) },
+	},
+	"ilert_schedule": {
+		config: map[string]any{"name": "test-schedule", "type": "RECURRING", "timezone": "Europe/Berlin"},
+		// the shifts of a static schedule
+		omit:       []string{"shift", "ics_content"},
+		inputs:     []string{"schedule_layer", "preview_from", "preview_horizon"},
+		configOnly: []string{"preview_from", "preview_horizon", "deletion_protection"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildSchedule(d) },
+	},
+	"ilert_service": {
+		config: map[string]any{"name": "test-service"},
+		build:  func(d *schema.ResourceData) (any, error) { return buildService(d) },
+	},
+	"ilert_status_page": {
+		// whitelists are only allowed on private status pages
+		config: map[string]any{"name": "test-status-page", "subdomain": "test", "visibility": "PRIVATE"},
+		inputs: []string{
+			"timezone", "hidden_from_search", "ip_whitelist", "theme_mode", "appearance", "email_whitelist",
+			"announcement", "announcement_on_page", "announcement_in_widget",
+		},
+		build: func(d *schema.ResourceData) (any, error) { return buildStatusPage(d) },
+	},
+	"ilert_status_page_group": {
+		config: map[string]any{"name": "test-status-page-group"},
+		build: func(d *schema.ResourceData) (any, error) {
+			group, statusPageID, err := buildStatusPageGroup(d)
+			if err != nil {
+				return nil, err
+			}
+			if statusPageID == nil {
+				return nil, fmt.Errorf("no status page")
+			}
+			return &StatusPageGroupWithContext{StatusPageGroup: group, StatusPageID: *statusPageID}, nil
+		},
+	},
+	"ilert_support_hour": {
+		config:     map[string]any{"name": "test-support-hour", "timezone": "Europe/Berlin"},
+		configOnly: []string{"deletion_protection"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildSupportHour(d) },
+	},
+	"ilert_team": {
+		config:     map[string]any{"name": "test-team"},
+		inputs:     []string{"manage_members", "member"},
+		configOnly: []string{"deletion_protection"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildTeam(d) },
+	},
+	"ilert_api_key": {
+		config: map[string]any{"name": "test-api-key"},
+		// only decides when the key is replaced
+		configOnly: []string{"rotation_trigger"},
+		build: func(d *schema.ResourceData) (any, error) {
+			accountID, err := strconv.ParseInt(d.Get("service_account").(string), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return &roundTripOwnedObject[ilert.APIKey]{Object: buildAPIKey(d), OwnerID: accountID}, nil
+		},
+		factory: func() any { return &roundTripOwnedObject[ilert.APIKey]{} },
+		transformer: func(e any, d *schema.ResourceData) error {
+			o := e.(*roundTripOwnedObject[ilert.APIKey])
+			return transformAPIKeyResource(o.Object, o.OwnerID, d)
+		},
+	},
+	"ilert_role": {
+		config:      map[string]any{"name": "test-role"},
+		build:       func(d *schema.ResourceData) (any, error) { return buildRole(d) },
+		factory:     func() any { return &ilert.CustomRole{} },
+		transformer: func(e any, d *schema.ResourceData) error { return transformRoleResource(e.(*ilert.CustomRole), d) },
+	},
+	"ilert_service_account": {
+		config:  map[string]any{"name": "test-service-account"},
+		build:   func(d *schema.ResourceData) (any, error) { return buildServiceAccount(d) },
+		factory: func() any { return &ilert.ServiceAccount{} },
+		transformer: func(e any, d *schema.ResourceData) error {
+			return transformServiceAccountResource(e.(*ilert.ServiceAccount), d)
+		},
+	},
+	"ilert_user": {
+		config: map[string]any{"first_name": "test", "last_name": "user", "email": "test@example.com"},
+		// sent only with the creation or handled on destroy
+		configOnly: []string{"send_no_invitation", "resend_invitation_trigger", "on_destroy"},
+		build:      func(d *schema.ResourceData) (any, error) { return buildUser(d) },
+	},
+	// the method decides whether a contact is required
+	"ilert_user_alert_preference": roundTripUserObjectCase(
+		map[string]any{"method": "EMAIL", "contact": []any{map[string]any{"id": 1}}}, nil,
+		buildUserAlertPreference, transformUserAlertPreferenceResource,
+	),
+	"ilert_user_duty_preference": roundTripUserObjectCase(
+		map[string]any{"method": "EMAIL", "contact": []any{map[string]any{"id": 1}}}, nil,
+		buildUserDutyPreference, transformUserDutyPreferenceResource,
+	),
+	"ilert_user_email_contact": roundTripUserObjectCase(
+		map[string]any{"target": "test@example.com"}, []string{"send_verification", "wait_for_verification"},
+		buildUserEmailContact, transformUserEmailContactResource,
+	),
+	"ilert_user_phone_number_contact": roundTripUserObjectCase(
+		map[string]any{"region_code": "DE", "target": "+491701234567"}, []string{"send_verification", "wait_for_verification"},
+		buildUserPhoneNumberContact, transformUserPhoneNumberContactResource,
+	),
+	"ilert_user_subscription_preference": roundTripUserObjectCase(
+		map[string]any{"method": "EMAIL", "contact": []any{map[string]any{"id": 1}}}, nil,
+		buildUserSubscriptionPreference, transformUserSubscriptionPreferenceResource,
+	),
+	"ilert_user_update_preference": roundTripUserObjectCase(
+		map[string]any{"method": "EMAIL", "contact": []any{map[string]any{"id": 1}}}, nil,
+		buildUserUpdatePreference, transformUserUpdatePreferenceResource,
+	),
+}
+
+// TestBuildTransformRoundTrip generates random valid configurations for each
+// resource of the provider, builds the API payload, passes it through JSON
+// the way the client does and transforms it into a ResourceData that only holds
+// the attributes the transformer reads, and asserts that every configured
+// attribute reads back unchanged. It fails when a field is added to only one of
+// the build or transform functions.
+func TestBuildTransformRoundTrip(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for resourceType := range roundTripCases {
+		if _, ok := resources[resourceType]; !ok {
+			t.Errorf("%s has a round-trip case but is not a resource of the provider", resourceType)
+		}
+	}
+	types := make([]string, 0, len(resources))
+	for resourceType := range resources {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+
+	for _, resourceType := range types {
+		t.Run(resourceType, func(t *testing.T) {
+			if reason, ok := roundTripSkipped[resourceType]; ok {
+				t.Skip(reason)
+			}
+			tc, ok := roundTripCases[resourceType]
+			if !ok {
+				t.Fatalf("%s has no round-trip case, add one to roundTripCases or a reason to roundTripSkipped", resourceType)
+			}
+			factory, transformer := tc.factory, tc.transformer
+			if reg, ok := resourceRegistry[resourceType]; ok {
+				factory, transformer = reg.factory, reg.transformer
+			}
+			if factory == nil || transformer == nil {
+				t.Fatalf("%s is not in resourceRegistry and its round-trip case has no factory and transformer", resourceType)
+			}
+			res := resources[resourceType]
+			g := &roundTripGenerator{rng: rand.New(rand.NewPCG(1, uint64(len(resourceType)))), omit: tc.omit}
+
+			rejected := 0
+			for i := 0; i < roundTripSamples; i++ {
+				config := g.config(res.Schema, tc.config, 0)
+				built := schema.TestResourceDataRaw(t, res.Schema, config)
+				built.SetId("1")
+				entity, err := tc.build(built)
+				if err != nil {
+					// a combination build rejects, e.g. a field that is only
+					// valid for another integration type
+					rejected++
+					if rejected > roundTripMaxRejected {
+						c, _ := json.Marshal(config)
+						t.Fatalf("build rejected %d of %d generated configurations, the last with %v\nconfig: %s", rejected, i+1, err, c)
+					}
+					continue
+				}
+
+				payload, err := json.Marshal(entity)
+				if err != nil {
+					t.Fatalf("sample %d: could not marshal the payload: %v", i, err)
+				}
+				read := factory()
+				if err := json.Unmarshal(payload, read); err != nil {
+					t.Fatalf("sample %d: could not unmarshal the payload: %v", i, err)
+				}
+				transformed := schema.TestResourceDataRaw(t, res.Schema, roundTripTransformInputs(config, tc.inputs))
+				transformed.SetId("1")
+				if err := transformer(read, transformed); err != nil {
+					t.Fatalf("sample %d: could not transform the payload: %v", i, err)
+				}
+
+				for _, key := range sortedRoundTripKeys(config) {
+					if slices.Contains(tc.configOnly, key) {
+						continue
+					}
+					want := normalizeRoundTripValue(built.Get(key))
+					got := normalizeRoundTripValue(transformed.Get(key))
+					if !reflect.DeepEqual(want, got) {
+						c, _ := json.Marshal(config)
+						t.Errorf("sample %d: %s does not round-trip\nconfig:  %s\npayload: %s\nwant: %#v\n got: %#v", i, key, c, payload, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+// roundTripTransformInputs returns the part of config the transformer may read.
+func roundTripTransformInputs(config map[string]any, inputs []string) map[string]any {
+	result := make(map[string]any)
+	for _, key := range append(append([]string{}, roundTripInputs...), inputs...) {
+		if v, ok := config[key]; ok {
+			result[key] = v
+		}
+	}
+	return result
+}
+
+// roundTripGenerator fills a schema with random values its validators accept.
+type roundTripGenerator struct {
+	rng  *rand.Rand
+	omit []string
+}
+
+// roundTripMaxDepth bounds the nesting of generated blocks, for recursive
+// schemas such as the nodes of a flow.
+const roundTripMaxDepth = 3
+
+var (
+	roundTripStrings = []string{"test", "1", "2", "08:00", "17:00", "Europe/Berlin", "PT1H", "2026-01-01T00:00", "https://example.com", "test@example.com", "{}"}
+	roundTripInts    = []int{0, 1, 2, 5, 10, 30, 60, 300, 3600}
+	// roundTripOneOfRegexp reads the allowed values off the error of a
+	// validation.StringInSlice or validation.IntInSlice.
+	roundTripOneOfRegexp = regexp.MustCompile(`to be one of \[([^\]]*)\]`)
+	roundTripIDRegexp    = regexp.MustCompile(`^(id|user|.*_id)$`)
+)
+
+func (g *roundTripGenerator) config(s map[string]*schema.Schema, base map[string]any, depth int) map[string]any {
+	config := make(map[string]any)
+	for k, v := range base {
+		config[k] = v
+	}
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		sch := s[k]
+		if _, ok := config[k]; ok || sch.Deprecated != "" || (!sch.Required && !sch.Optional) {
+			continue
+		}
+		if depth == 0 && slices.Contains(g.omit, k) {
+			continue
+		}
+		if !sch.Required && (depth >= roundTripMaxDepth || g.rng.IntN(2) == 0) {
+			continue
+		}
+		if roundTripConflicts(config, sch) {
+			continue
+		}
+		if v, ok := g.value(k, sch, depth); ok {
+			config[k] = v
+		}
+	}
+	return config
+}
+
+func roundTripConflicts(config map[string]any, sch *schema.Schema) bool {
+	for _, other := range append(append([]string{}, sch.ConflictsWith...), sch.ExactlyOneOf...) {
+		if _, ok := config[other[strings.LastIndex(other, ".")+1:]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *roundTripGenerator) value(k string, sch *schema.Schema, depth int) (any, bool) {
+	switch sch.Type {
+	case schema.TypeBool:
+		return g.rng.IntN(2) == 0, true
+	case schema.TypeFloat:
+		return 0.5, true
+	case schema.TypeString:
+		if roundTripIDRegexp.MatchString(k) {
+			// ids of other objects, which build parses as numbers
+			return g.pick(k, sch, []any{"1", "2"})
+		}
+		candidates := make([]any, 0, len(roundTripStrings))
+		for _, c := range roundTripStrings {
+			candidates = append(candidates, c)
+		}
+		return g.pick(k, sch, candidates)
+	case schema.TypeInt:
+		candidates := make([]any, 0, len(roundTripInts))
+		for _, c := range roundTripInts {
+			candidates = append(candidates, c)
+		}
+		return g.pick(k, sch, candidates)
+	case schema.TypeMap:
+		return map[string]any{"key": "value"}, true
+	case schema.TypeList, schema.TypeSet:
+		n := max(sch.MinItems, 1)
+		if limit := max(sch.MaxItems, 2); n < limit && g.rng.IntN(2) == 0 {
+			n++
+		}
+		items := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			switch elem := sch.Elem.(type) {
+			case *schema.Resource:
+				items = append(items, g.config(elem.Schema, nil, depth+1))
+			case *schema.Schema:
+				if v, ok := g.value(k, elem, depth+1); ok {
+					items = append(items, v)
+				}
+			}
+		}
+		if len(items) == 0 {
+			return nil, false
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// pick chooses a random candidate the validators of the attribute accept,
+// preferring the allowed values of an enum.
+func (g *roundTripGenerator) pick(k string, sch *schema.Schema, candidates []any) (any, bool) {
+	if sch.ValidateFunc != nil {
+		_, errs := sch.ValidateFunc(candidates[0], k)
+		for _, err := range errs {
+			if m := roundTripOneOfRegexp.FindStringSubmatch(err.Error()); m != nil && strings.TrimSpace(m[1]) != "" {
+				values := make([]any, 0)
+				for _, v := range strings.Fields(m[1]) {
+					if sch.Type == schema.TypeInt {
+						var n int
+						if _, err := fmt.Sscan(v, &n); err == nil {
+							values = append(values, n)
+						}
+						continue
+					}
+					values = append(values, v)
+				}
+				candidates = values
+			}
+		}
+	}
+
+	valid := make([]any, 0, len(candidates))
+	for _, c := range candidates {
+		if sch.ValidateFunc != nil {
+			if _, errs := sch.ValidateFunc(c, k); len(errs) > 0 {
+				continue
+			}
+		}
+		if sch.ValidateDiagFunc != nil && sch.ValidateDiagFunc(c, cty.GetAttrPath(k)).HasError() {
+			continue
+		}
+		valid = append(valid, c)
+	}
+	if len(valid) == 0 {
+		return nil, false
+	}
+	return valid[g.rng.IntN(len(valid))], true
+}
+
+func sortedRoundTripKeys(config map[string]any) []string {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeRoundTripValue turns sets into lists in a stable order, so values
+// read from two ResourceData compare with reflect.DeepEqual.
+func normalizeRoundTripValue(v any) any {
+	switch t := v.(type) {
+	case *schema.Set:
+		items := make([]any, 0, t.Len())
+		for _, it := range t.List() {
+			items = append(items, normalizeRoundTripValue(it))
+		}
+		sort.Slice(items, func(i, j int) bool { return fmt.Sprint(items[i]) < fmt.Sprint(items[j]) })
+		return items
+	case []any:
+		items := make([]any, 0, len(t))
+		for _, it := range t {
+			items = append(items, normalizeRoundTripValue(it))
+		}
+		return items
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, it := range t {
+			m[k] = normalizeRoundTripValue(it)
+		}
+		return m
+	}
+	return v
+}
@@ -0,0 +1,329 @@
+package ilert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+// icsUserIDProperty carries the ilert user id of a shift in the rendered
+// calendar, so an exported schedule can be fed back into ics_content.
+const icsUserIDProperty = "X-ILERT-USER-ID"
+
+const icsUTCLayout = "20060102T150405Z"
+
+// icsMaxLineOctets is the line length after which RFC 5545 requires content
+// lines to be folded.
+const icsMaxLineOctets = 75
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+var icsTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// icsProperty is a single unfolded content line, e.g.
+// DTSTART;TZID=Europe/Berlin:20220830T090000.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// renderScheduleICS renders the shifts of a schedule as an RFC 5545 calendar
+// with one event per shift. Gaps are left out. Times are written in UTC, which
+// spares the VTIMEZONE definitions, and stamp is used as DTSTAMP so the output
+// only changes when the shifts do.
+func renderScheduleICS(scheduleID, name string, shifts []scheduleShift, userNames map[string]string, stamp time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//ilert//terraform-provider-ilert//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	if name != "" {
+		writeICSLine(&b, "X-WR-CALNAME:"+icsTextEscaper.Replace(name))
+	}
+	for _, s := range shifts {
+		if s.UserID == "" {
+			continue
+		}
+		summary := userNames[s.UserID]
+		if summary == "" {
+			summary = "User " + s.UserID
+		}
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:schedule-%s-%s-%d@ilert.com", scheduleID, s.UserID, s.Start.Unix()))
+		writeICSLine(&b, "DTSTAMP:"+stamp.UTC().Format(icsUTCLayout))
+		writeICSLine(&b, "DTSTART:"+s.Start.UTC().Format(icsUTCLayout))
+		writeICSLine(&b, "DTEND:"+s.End.UTC().Format(icsUTCLayout))
+		writeICSLine(&b, "SUMMARY:"+icsTextEscaper.Replace(summary))
+		writeICSLine(&b, icsUserIDProperty+":"+s.UserID)
+		writeICSLine(&b, "TRANSP:TRANSPARENT")
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it into
+// continuation lines that start with a space once it gets too long. Lines are
+// only split between runes so multi-byte characters survive the folding.
+func writeICSLine(b *strings.Builder, line string) {
+	octets := 0
+	for _, r := range line {
+		size := len(string(r))
+		if octets+size > icsMaxLineOctets {
+			b.WriteString("\r\n ")
+			octets = 1
+		}
+		b.WriteRune(r)
+		octets += size
+	}
+	b.WriteString("\r\n")
+}
+
+// parseScheduleICS reads the events of an RFC 5545 calendar into the shifts of
+// a STATIC schedule, with their times converted into the schedule's timezone.
+// The user of an event is taken from its X-ILERT-USER-ID property, or else from
+// a SUMMARY that consists of a user id. Cancelled events are skipped; recurring
+// events are rejected, as the occurrences of an RRULE are not expanded.
+func parseScheduleICS(content string, loc *time.Location) ([]ilert.Shift, error) {
+	properties, err := parseICSProperties(content)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := make([]ilert.Shift, 0)
+	var event []icsProperty
+	inCalendar, inEvent := false, false
+	for _, p := range properties {
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VCALENDAR"):
+			inCalendar = true
+		case p.Name == "END" && strings.EqualFold(p.Value, "VCALENDAR"):
+			inCalendar = false
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VEVENT"):
+			if !inCalendar {
+				return nil, fmt.Errorf("VEVENT outside of a VCALENDAR")
+			}
+			inEvent, event = true, nil
+		case p.Name == "END" && strings.EqualFold(p.Value, "VEVENT"):
+			if !inEvent {
+				return nil, fmt.Errorf("END:VEVENT without a matching BEGIN:VEVENT")
+			}
+			inEvent = false
+			shift, ok, err := icsEventShift(event, loc)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				shifts = append(shifts, shift)
+			}
+		case inEvent:
+			event = append(event, p)
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("VEVENT is not closed by END:VEVENT")
+	}
+	if len(shifts) == 0 {
+		return nil, fmt.Errorf("calendar does not contain any events")
+	}
+
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return shifts[i].Start < shifts[j].Start
+	})
+	return shifts, nil
+}
+
+func icsEventShift(event []icsProperty, loc *time.Location) (ilert.Shift, bool, error) {
+	var start, end time.Time
+	var duration string
+	var userID, summary, uid string
+	var err error
+	for _, p := range event {
+		switch p.Name {
+		case "UID":
+			uid = p.Value
+		case "STATUS":
+			if strings.EqualFold(p.Value, "CANCELLED") {
+				return ilert.Shift{}, false, nil
+			}
+		case "RRULE", "RDATE":
+			return ilert.Shift{}, false, fmt.Errorf("event %q is recurring (%s), which is not supported: export the single occurrences instead", uid, p.Name)
+		case "DTSTART":
+			if start, err = parseICSDateTime(p, loc); err != nil {
+				return ilert.Shift{}, false, err
+			}
+		case "DTEND":
+			if end, err = parseICSDateTime(p, loc); err != nil {
+				return ilert.Shift{}, false, err
+			}
+		case "DURATION":
+			duration = p.Value
+		case icsUserIDProperty:
+			userID = strings.TrimSpace(p.Value)
+		case "SUMMARY":
+			summary = strings.TrimSpace(icsTextUnescaper.Replace(p.Value))
+		}
+	}
+
+	if start.IsZero() {
+		return ilert.Shift{}, false, fmt.Errorf("event %q has no DTSTART", uid)
+	}
+	if end.IsZero() && duration != "" {
+		d, err := parseISODuration(duration)
+		if err != nil {
+			return ilert.Shift{}, false, fmt.Errorf("event %q has an invalid DURATION: %s", uid, err.Error())
+		}
+		end = d.addTo(start, 1)
+	}
+	if end.IsZero() {
+		return ilert.Shift{}, false, fmt.Errorf("event %q has neither DTEND nor DURATION", uid)
+	}
+	if !start.Before(end) {
+		return ilert.Shift{}, false, fmt.Errorf("event %q ends before it starts", uid)
+	}
+
+	if userID == "" {
+		userID = summary
+	}
+	id, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return ilert.Shift{}, false, fmt.Errorf("event %q does not name an ilert user: set %s to the user id, or use the user id as the SUMMARY", uid, icsUserIDProperty)
+	}
+
+	layout := scheduleDateTimeLayouts[1]
+	return ilert.Shift{
+		User:  ilert.User{ID: id},
+		Start: start.In(loc).Format(layout),
+		End:   end.In(loc).Format(layout),
+	}, true, nil
+}
+
+// parseICSProperties unfolds the content lines of a calendar and splits them
+// into their name, parameters and value.
+func parseICSProperties(content string) ([]icsProperty, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	properties := make([]icsProperty, 0, len(lines))
+	for i, line := range lines {
+		p, err := parseICSProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err.Error())
+		}
+		properties = append(properties, p)
+	}
+	return properties, nil
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	// The value starts at the first colon outside of a quoted parameter value.
+	quoted := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return icsProperty{}, fmt.Errorf("%q is not a content line of the form NAME:VALUE", line)
+	}
+
+	parts := strings.Split(line[:sep], ";")
+	p := icsProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string),
+		Value:  line[sep+1:],
+	}
+	if p.Name == "" {
+		return icsProperty{}, fmt.Errorf("%q has no property name", line)
+	}
+	for _, param := range parts[1:] {
+		k, v, ok := strings.Cut(param, "=")
+		if !ok {
+			return icsProperty{}, fmt.Errorf("parameter %q of %s has no value", param, p.Name)
+		}
+		p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+// parseICSDateTime reads a DATE or DATE-TIME value: in UTC when it ends in Z,
+// in the zone named by a TZID parameter, and otherwise as floating time in the
+// schedule's timezone.
+func parseICSDateTime(p icsProperty, loc *time.Location) (time.Time, error) {
+	if tzid, ok := p.Params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s has an unknown TZID %q, expected an IANA time zone such as Europe/Berlin", p.Name, tzid)
+		}
+		loc = l
+	}
+
+	var t time.Time
+	var err error
+	switch {
+	case p.Params["VALUE"] == "DATE" || len(p.Value) == len("20060102"):
+		t, err = time.ParseInLocation("20060102", p.Value, loc)
+	case strings.HasSuffix(p.Value, "Z"):
+		t, err = time.Parse(icsUTCLayout, p.Value)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", p.Value, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s %q is not an iCalendar date or date time", p.Name, p.Value)
+	}
+	return t, nil
+}
+
+// sameScheduleShifts reports whether the shifts read from the API are the ones
+// the ics_content of a schedule resolves to.
+func sameScheduleShifts(actual, expected []ilert.Shift, loc *time.Location) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	sorted := append([]ilert.Shift(nil), actual...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, errA := parseScheduleDateTime(sorted[i].Start, loc)
+		b, errB := parseScheduleDateTime(sorted[j].Start, loc)
+		if errA != nil || errB != nil {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return a.Before(b)
+	})
+	for i := range sorted {
+		if sorted[i].User.ID != expected[i].User.ID ||
+			!sameScheduleDateTime(sorted[i].Start, expected[i].Start, loc) ||
+			!sameScheduleDateTime(sorted[i].End, expected[i].End, loc) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateScheduleICS checks that ics_content parses. Floating times are only
+// resolved against the schedule's timezone at apply time.
+func validateScheduleICS(v any, k string) ([]string, []error) {
+	if _, err := parseScheduleICS(v.(string), time.UTC); err != nil {
+		return nil, []error{fmt.Errorf("%q is not a usable iCalendar document: %s", k, err.Error())}
+	}
+	return nil, nil
+}
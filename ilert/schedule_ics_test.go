@@ -0,0 +1,195 @@
+package ilert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestParseScheduleICS_ConvertsTimesIntoScheduleTimezone(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	content := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20260105T080000Z",
+		"DTEND:20260105T160000Z",
+		"X-ILERT-USER-ID:1",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:tzid",
+		`DTSTART;TZID="America/New_York":20260106T090000`,
+		"DURATION:PT8H",
+		"SUMMARY:2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20260104T090000",
+		"DTEND:20260104T170000",
+		"X-ILERT-USER-ID:3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"DTSTART;VALUE=DATE:20260110",
+		"DTEND;VALUE=DATE:20260112",
+		"X-ILERT-USER-ID:4",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"STATUS:CANCELLED",
+		"DTSTART:20260107T090000",
+		"DTEND:20260107T170000",
+		"X-ILERT-USER-ID:5",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := parseScheduleICS(content, loc)
+	if err != nil {
+		t.Fatalf("unexpected error parsing calendar: %v", err)
+	}
+
+	want := []ilert.Shift{
+		{User: ilert.User{ID: 3}, Start: "2026-01-04T09:00", End: "2026-01-04T17:00"},
+		{User: ilert.User{ID: 1}, Start: "2026-01-05T09:00", End: "2026-01-05T17:00"},
+		{User: ilert.User{ID: 2}, Start: "2026-01-06T15:00", End: "2026-01-06T23:00"},
+		{User: ilert.User{ID: 4}, Start: "2026-01-10T00:00", End: "2026-01-12T00:00"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestParseScheduleICS_Errors(t *testing.T) {
+	event := func(lines ...string) string {
+		return strings.Join(append(append([]string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:e1"}, lines...), "END:VEVENT", "END:VCALENDAR"), "\n")
+	}
+	cases := map[string]string{
+		"recurring":     event("DTSTART:20260105T090000", "DTEND:20260105T170000", "RRULE:FREQ=WEEKLY", "X-ILERT-USER-ID:1"),
+		"no user":       event("DTSTART:20260105T090000", "DTEND:20260105T170000", "SUMMARY:Jane Doe"),
+		"no end":        event("DTSTART:20260105T090000", "X-ILERT-USER-ID:1"),
+		"ends early":    event("DTSTART:20260105T170000", "DTEND:20260105T090000", "X-ILERT-USER-ID:1"),
+		"unknown tzid":  event("DTSTART;TZID=W. Europe Standard Time:20260105T090000", "DTEND:20260105T170000", "X-ILERT-USER-ID:1"),
+		"bad date":      event("DTSTART:2026-01-05 09:00", "DTEND:20260105T170000", "X-ILERT-USER-ID:1"),
+		"unclosed":      "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260105T090000\nEND:VCALENDAR",
+		"no events":     "BEGIN:VCALENDAR\nVERSION:2.0\nEND:VCALENDAR",
+		"not a content": "BEGIN:VCALENDAR\nthis is not ics\nEND:VCALENDAR",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseScheduleICS(content, time.UTC); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestRenderScheduleICS_RoundTripsThroughParse(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	shifts := []scheduleShift{
+		{UserID: "1", Start: time.Date(2026, 3, 28, 9, 0, 0, 0, loc), End: time.Date(2026, 3, 29, 9, 0, 0, 0, loc)},
+		{Start: time.Date(2026, 3, 29, 9, 0, 0, 0, loc), End: time.Date(2026, 3, 29, 12, 0, 0, 0, loc)},
+		{UserID: "2", Start: time.Date(2026, 3, 29, 12, 0, 0, 0, loc), End: time.Date(2026, 3, 30, 9, 0, 0, 0, loc)},
+	}
+	names := map[string]string{"1": "Jane Doe, Platform; On-call"}
+
+	content := renderScheduleICS("42", "Platform", shifts, names, shifts[0].Start)
+
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\r\n"), "\r\n") {
+		if len(line) > icsMaxLineOctets {
+			t.Fatalf("expected lines to be folded at %d octets, got %d: %q", icsMaxLineOctets, len(line), line)
+		}
+	}
+	if !strings.Contains(content, `SUMMARY:Jane Doe\, Platform\; On-call`) {
+		t.Fatalf("expected the summary to be escaped, got:\n%s", content)
+	}
+	if !strings.Contains(content, "SUMMARY:User 2") {
+		t.Fatalf("expected users without a name to be summarized by id, got:\n%s", content)
+	}
+
+	got, err := parseScheduleICS(content, loc)
+	if err != nil {
+		t.Fatalf("unexpected error parsing rendered calendar: %v", err)
+	}
+	want := []ilert.Shift{
+		{User: ilert.User{ID: 1}, Start: "2026-03-28T09:00", End: "2026-03-29T09:00"},
+		{User: ilert.User{ID: 2}, Start: "2026-03-29T12:00", End: "2026-03-30T09:00"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestWriteICSLine_FoldsBetweenRunes(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+strings.Repeat("ü", 80))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected the line to be folded into 3 lines, got %d: %q", len(lines), lines)
+	}
+	properties, err := parseICSProperties(b.String())
+	if err != nil {
+		t.Fatalf("unexpected error unfolding the line: %v", err)
+	}
+	if want := strings.Repeat("ü", 80); properties[0].Value != want {
+		t.Fatalf("expected the unfolded value %q, got %q", want, properties[0].Value)
+	}
+}
+
+func TestSameScheduleShifts(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	expected := []ilert.Shift{
+		{User: ilert.User{ID: 1}, Start: "2026-01-05T09:00", End: "2026-01-05T17:00"},
+		{User: ilert.User{ID: 2}, Start: "2026-01-06T09:00", End: "2026-01-06T17:00"},
+	}
+
+	actual := []ilert.Shift{
+		{User: ilert.User{ID: 2}, Start: "2026-01-06T09:00:00+01:00", End: "2026-01-06T17:00:00+01:00"},
+		{User: ilert.User{ID: 1}, Start: "2026-01-05T08:00:00Z", End: "2026-01-05T16:00:00Z"},
+	}
+	if !sameScheduleShifts(actual, expected, loc) {
+		t.Fatalf("expected shifts in a different order and notation to match")
+	}
+
+	actual[0].User.ID = 3
+	if sameScheduleShifts(actual, expected, loc) {
+		t.Fatalf("expected shifts with a different user not to match")
+	}
+	if sameScheduleShifts(actual[:1], expected, loc) {
+		t.Fatalf("expected a missing shift not to match")
+	}
+}
+
+func TestScheduleICSShifts_StaticScheduleKeepsOverlappingShifts(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	schedule := &ilert.Schedule{
+		Type:     ilert.ScheduleType.Static,
+		Timezone: "Europe/Berlin",
+		Shifts: []ilert.Shift{
+			{User: ilert.User{ID: 1, FirstName: "Jane", LastName: "Doe"}, Start: "2026-01-04T09:00", End: "2026-01-05T09:00"},
+			{User: ilert.User{ID: 2}, Start: "2026-01-05T09:00", End: "2026-01-06T09:00"},
+			{User: ilert.User{ID: 3}, Start: "2026-01-10T09:00", End: "2026-01-11T09:00"},
+		},
+	}
+
+	got, names, err := scheduleICSShifts(schedule, loc, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 7, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []scheduleShift{
+		{UserID: "1", Start: time.Date(2026, 1, 4, 9, 0, 0, 0, loc), End: time.Date(2026, 1, 5, 9, 0, 0, 0, loc)},
+		{UserID: "2", Start: time.Date(2026, 1, 5, 9, 0, 0, 0, loc), End: time.Date(2026, 1, 6, 9, 0, 0, 0, loc)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+	if want := map[string]string{"1": "Jane Doe"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected user names %v, got %v", want, names)
+	}
+}
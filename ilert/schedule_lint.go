@@ -0,0 +1,82 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// scheduleLintRules are the rules of the schedule linter, set in the schedule
+// block of the provider's lint block.
+var scheduleLintRules = []string{
+	"stakeholder_in_layer",
+}
+
+// lintSchedule checks the layers of a schedule for users who cannot respond to
+// the alerts they would be paged for. get reads the planned or applied
+// attributes.
+func lintSchedule(get func(string) any, stakeholder stakeholderLookup) []lintFinding {
+	name := get("name").(string)
+	layers, _ := get("schedule_layer").([]any)
+
+	findings := make([]lintFinding, 0)
+	for i, it := range layers {
+		layer, ok := it.(map[string]any)
+		if !ok {
+			continue
+		}
+		label := fmt.Sprintf("schedule layer %d", i+1)
+		if layerName, _ := layer["name"].(string); layerName != "" {
+			label = fmt.Sprintf("schedule layer %q", layerName)
+		}
+		uL, _ := layer["user"].([]any)
+		for _, u := range uL {
+			v, ok := u.(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := v["id"].(string)
+			user := stakeholder(id)
+			if user == nil {
+				continue
+			}
+			findings = append(findings, lintFinding{
+				rule:    "schedule.stakeholder_in_layer",
+				summary: fmt.Sprintf("Schedule %q puts the stakeholder %s on call", name, user.Email),
+				detail:  fmt.Sprintf("The %s rotates through user %s, who has the STAKEHOLDER role and cannot respond to alerts. Nobody responds to the alerts paged during their shifts.", label, id),
+			})
+		}
+	}
+	return findings
+}
+
+// customizeScheduleLint lints the planned schedule once its layers are known,
+// and only when they change.
+func customizeScheduleLint(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if diff.Id() != "" && !diff.HasChange("schedule_layer") {
+		return nil
+	}
+	client := providerClient(m)
+	if client == nil {
+		return nil
+	}
+	if !diffValuesKnown(diff, "schedule_layer") {
+		return diff.SetNewComputed("lint_findings")
+	}
+	ctx, cancel := context.WithTimeout(ctx, lintTimeout)
+	defer cancel()
+	return customizeLintFindings(diff, lintConfigFor(m), lintSchedule(diff.Get, newStakeholderLookup(ctx, client)))
+}
+
+// scheduleLintWarnings lints the applied schedule.
+func scheduleLintWarnings(ctx context.Context, d *schema.ResourceData, m any) diag.Diagnostics {
+	client := providerClient(m)
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, lintTimeout)
+	defer cancel()
+	return lintWarnings(d, lintConfigFor(m), lintSchedule(d.Get, newStakeholderLookup(ctx, client)))
+}
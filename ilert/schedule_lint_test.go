@@ -0,0 +1,37 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestLintSchedule(t *testing.T) {
+	stakeholder := func(id string) *ilert.User {
+		if id == "2" {
+			return &ilert.User{ID: 2, Email: "viewer@example.com", Role: userRoleStakeholder}
+		}
+		return nil
+	}
+	config := map[string]any{
+		"name": "Primary",
+		"schedule_layer": []any{
+			map[string]any{"name": "Weekdays", "user": []any{map[string]any{"id": "1"}, map[string]any{"id": "2"}}},
+			map[string]any{"name": "", "user": []any{map[string]any{"id": "1"}}},
+			map[string]any{"name": "", "user": []any{map[string]any{"id": "2"}}},
+		},
+	}
+	findings := lintSchedule(func(k string) any { return config[k] }, stakeholder)
+	got := make([]string, 0)
+	for _, finding := range findings {
+		if finding.rule != "schedule.stakeholder_in_layer" || finding.summary != `Schedule "Primary" puts the stakeholder viewer@example.com on call` {
+			t.Errorf("unexpected finding %+v", finding)
+		}
+		got = append(got, finding.detail[:strings.Index(finding.detail, " rotates")])
+	}
+	want := []string{`The schedule layer "Weekdays"`, "The schedule layer 3"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got findings for %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,449 @@
+package ilert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+// defaultSchedulePreviewHorizon is how far ahead coverage is checked for gaps
+// when the configuration does not ask for a preview of its own.
+const defaultSchedulePreviewHorizon = "P14D"
+
+// maxScheduleRotations bounds the expansion of a single layer within the
+// expanded window, so a layer with a rotation of a few seconds cannot stall a
+// plan. The rotations before the window are skipped rather than expanded.
+const maxScheduleRotations = 100000
+
+var isoDurationRegexp = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// isoDuration is an ISO 8601 duration such as P7D or PT8H. Years, months and days
+// are kept apart from the clock time, so adding P1D across a daylight saving
+// transition keeps the wall clock time the way the schedule endpoints do.
+type isoDuration struct {
+	Years, Months, Weeks, Days int
+	Clock                      time.Duration
+}
+
+func parseISODuration(value string) (isoDuration, error) {
+	match := isoDurationRegexp.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return isoDuration{}, fmt.Errorf("%q is not an ISO 8601 duration such as P7D or PT8H", value)
+	}
+	parts := make([]int, len(match)-1)
+	for i, m := range match[1:] {
+		if m == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m)
+		if err != nil {
+			return isoDuration{}, fmt.Errorf("%q is not an ISO 8601 duration such as P7D or PT8H: %s", value, err.Error())
+		}
+		parts[i] = n
+	}
+	return isoDuration{
+		Years:  parts[0],
+		Months: parts[1],
+		Weeks:  parts[2],
+		Days:   parts[3],
+		Clock:  time.Duration(parts[4])*time.Hour + time.Duration(parts[5])*time.Minute + time.Duration(parts[6])*time.Second,
+	}, nil
+}
+
+func (d isoDuration) isZero() bool {
+	return d.Years == 0 && d.Months == 0 && d.Weeks == 0 && d.Days == 0 && d.Clock == 0
+}
+
+// equivalent reports whether two durations always span the same time, such as
+// PT60M and PT1H or P1W and P7D. Days and hours are not mixed: across a daylight
+// saving transition P1D lasts 23 or 25 hours.
+func (d isoDuration) equivalent(o isoDuration) bool {
+	return d.Years*12+d.Months == o.Years*12+o.Months &&
+		d.Weeks*7+d.Days == o.Weeks*7+o.Days &&
+		d.Clock == o.Clock
+}
+
+// longest returns an upper bound of the time the duration spans, taking every
+// year, month and day at its longest.
+func (d isoDuration) longest() time.Duration {
+	day := 25 * time.Hour
+	return time.Duration(d.Years)*366*day + time.Duration(d.Months)*31*day + time.Duration(d.Weeks*7+d.Days)*day + d.Clock
+}
+
+// addTo adds the duration n times to t.
+func (d isoDuration) addTo(t time.Time, n int) time.Time {
+	return t.AddDate(d.Years*n, d.Months*n, (d.Weeks*7+d.Days)*n).Add(d.Clock * time.Duration(n))
+}
+
+// scheduleShift is a span of time in which a user is on call, or a gap when
+// UserID is empty.
+type scheduleShift struct {
+	UserID string
+	Start  time.Time
+	End    time.Time
+}
+
+// layerSegment is a shift produced by a single layer, before the layers are
+// stacked.
+type layerSegment struct {
+	layer int
+	scheduleShift
+}
+
+var weekdays = map[string]time.Weekday{
+	"MONDAY":    time.Monday,
+	"TUESDAY":   time.Tuesday,
+	"WEDNESDAY": time.Wednesday,
+	"THURSDAY":  time.Thursday,
+	"FRIDAY":    time.Friday,
+	"SATURDAY":  time.Saturday,
+	"SUNDAY":    time.Sunday,
+}
+
+// expandScheduleLayers resolves the layers of a recurring schedule into the
+// shifts between from and until, in the schedule's timezone. Each layer rotates
+// through its users from starts_on on, one rotation at a time, and only covers
+// the times of week its restrictions allow. Where layers overlap the one declared
+// last wins, and times no layer covers are returned as gaps.
+//
+// A layer that needs more than maxScheduleRotations rotations within the window
+// cuts the expansion short: the timeline then ends where that layer stopped,
+// which is returned as the end of the expanded window, so the rest of the window
+// is not reported as a gap.
+func expandScheduleLayers(layers []ilert.ScheduleLayer, timezone string, from, until time.Time) ([]scheduleShift, time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("timezone %q is not a known IANA time zone: %s", timezone, err.Error())
+	}
+	from = from.In(loc)
+	until = until.In(loc)
+
+	segments := make([]layerSegment, 0)
+	expandedUntil := until
+	for i, layer := range layers {
+		layerSegments, layerUntil, err := expandScheduleLayer(i, layer, loc, from, until)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("schedule layer %q: %s", layer.Name, err.Error())
+		}
+		if layerUntil.Before(expandedUntil) {
+			expandedUntil = layerUntil
+		}
+		segments = append(segments, layerSegments...)
+	}
+	if expandedUntil.Before(from) {
+		expandedUntil = from
+	}
+
+	return stackLayerSegments(segments, from, expandedUntil), expandedUntil, nil
+}
+
+// expandScheduleLayer returns the segments of a single layer between from and
+// until, and the time up to which it was expanded: until, or earlier when the
+// layer needs more than maxScheduleRotations rotations to get there.
+func expandScheduleLayer(index int, layer ilert.ScheduleLayer, loc *time.Location, from, until time.Time) ([]layerSegment, time.Time, error) {
+	if len(layer.Users) == 0 {
+		return nil, until, nil
+	}
+	start, err := parseScheduleDateTime(layer.StartsOn, loc)
+	if err != nil {
+		return nil, until, fmt.Errorf("starts_on %q is not a date time such as 2022-08-30T00:00", layer.StartsOn)
+	}
+	rotation, err := parseISODuration(layer.Rotation)
+	if err != nil {
+		return nil, until, fmt.Errorf("rotation %s", err.Error())
+	}
+	if rotation.isZero() {
+		return nil, until, fmt.Errorf("rotation %q must be longer than zero", layer.Rotation)
+	}
+	end := until
+	if layer.EndsOn != "" {
+		layerEnd, err := parseScheduleDateTime(layer.EndsOn, loc)
+		if err != nil {
+			return nil, until, fmt.Errorf("ends_on %q is not a date time such as 2022-08-30T00:00", layer.EndsOn)
+		}
+		if layerEnd.Before(end) {
+			end = layerEnd
+		}
+	}
+
+	// skip the rotations that end before the window. first is a lower bound of
+	// the first rotation that does not, and rotation k still goes to user k
+	// modulo the number of users.
+	first := 0
+	if from.After(start) {
+		first = int(from.Sub(start)/rotation.longest()) - 1
+		if first < 0 {
+			first = 0
+		}
+	}
+
+	segments := make([]layerSegment, 0)
+	for k := first; ; k++ {
+		shiftStart := rotation.addTo(start, k)
+		if !shiftStart.Before(end) {
+			break
+		}
+		if k-first >= maxScheduleRotations {
+			return segments, shiftStart, nil
+		}
+		shiftEnd := rotation.addTo(start, k+1)
+		if shiftEnd.After(end) {
+			shiftEnd = end
+		}
+		if !shiftEnd.After(from) {
+			continue
+		}
+		userID := strconv.FormatInt(layer.Users[k%len(layer.Users)].ID, 10)
+		for _, window := range restrictLayerShift(layer, shiftStart, shiftEnd, loc) {
+			segments = append(segments, layerSegment{
+				layer:         index,
+				scheduleShift: scheduleShift{UserID: userID, Start: window[0], End: window[1]},
+			})
+		}
+	}
+	return segments, until, nil
+}
+
+// restrictLayerShift cuts a rotation down to the times its layer's restrictions
+// allow. A restriction wraps around the end of the week (FRIDAY 18:00 to MONDAY
+// 08:00) when its end is not after its start; TIMES_OF_DAY restrictions repeat
+// the from and to times every day and ignore the day of week.
+func restrictLayerShift(layer ilert.ScheduleLayer, start, end time.Time, loc *time.Location) [][2]time.Time {
+	if len(layer.Restrictions) == 0 {
+		return [][2]time.Time{{start, end}}
+	}
+	daily := layer.RestrictionType == "TIMES_OF_DAY"
+
+	windows := make([][2]time.Time, 0)
+	for _, restriction := range layer.Restrictions {
+		if restriction.From == nil || restriction.To == nil {
+			continue
+		}
+		fromClock, err := time.Parse("15:04", restriction.From.Time)
+		if err != nil {
+			continue
+		}
+		toClock, err := time.Parse("15:04", restriction.To.Time)
+		if err != nil {
+			continue
+		}
+		fromDay, toDay := 0, 0
+		if !daily {
+			fromDay = weekdayOffset(weekdays[restriction.From.DayOfWeek])
+			toDay = weekdayOffset(weekdays[restriction.To.DayOfWeek])
+		}
+
+		step := 7
+		anchor := startOfWeek(start.AddDate(0, 0, -7), loc)
+		if daily {
+			step = 1
+			anchor = time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, loc)
+		}
+		for ; anchor.Before(end); anchor = anchor.AddDate(0, 0, step) {
+			windowStart := time.Date(anchor.Year(), anchor.Month(), anchor.Day()+fromDay, fromClock.Hour(), fromClock.Minute(), 0, 0, loc)
+			windowEnd := time.Date(anchor.Year(), anchor.Month(), anchor.Day()+toDay, toClock.Hour(), toClock.Minute(), 0, 0, loc)
+			if !windowEnd.After(windowStart) {
+				windowEnd = windowEnd.AddDate(0, 0, step)
+			}
+			s, e := windowStart, windowEnd
+			if s.Before(start) {
+				s = start
+			}
+			if e.After(end) {
+				e = end
+			}
+			if e.After(s) {
+				windows = append(windows, [2]time.Time{s, e})
+			}
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0].Before(windows[j][0]) })
+	merged := make([][2]time.Time, 0, len(windows))
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w[0].After(merged[n-1][1]) {
+			if w[1].After(merged[n-1][1]) {
+				merged[n-1][1] = w[1]
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// weekdayOffset counts days from Monday, the first day of a schedule week.
+func weekdayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func startOfWeek(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day()-weekdayOffset(t.Weekday()), 0, 0, 0, 0, loc)
+}
+
+// stackLayerSegments flattens overlapping layer shifts into a single timeline
+// between from and until, in which the highest layer on call at any time wins.
+// Consecutive pieces of the same user, and consecutive gaps, are merged.
+func stackLayerSegments(segments []layerSegment, from, until time.Time) []scheduleShift {
+	if !until.After(from) {
+		return []scheduleShift{}
+	}
+
+	boundaries := []time.Time{from, until}
+	for _, s := range segments {
+		if s.Start.After(from) && s.Start.Before(until) {
+			boundaries = append(boundaries, s.Start)
+		}
+		if s.End.After(from) && s.End.Before(until) {
+			boundaries = append(boundaries, s.End)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	// sweep the segments in order of their start, keeping the one of every layer
+	// that is on call; the segments of a single layer do not overlap
+	sorted := make([]layerSegment, len(segments))
+	copy(sorted, segments)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	active := make(map[int]layerSegment)
+	next := 0
+
+	results := make([]scheduleShift, 0)
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		if !end.After(start) {
+			continue
+		}
+		for ; next < len(sorted) && !sorted[next].Start.After(start); next++ {
+			active[sorted[next].layer] = sorted[next]
+		}
+		layer, userID := -1, ""
+		for l, s := range active {
+			if !s.End.After(start) {
+				delete(active, l)
+				continue
+			}
+			if l > layer && !s.End.Before(end) {
+				layer, userID = l, s.UserID
+			}
+		}
+		if n := len(results); n > 0 && results[n-1].UserID == userID && results[n-1].End.Equal(start) {
+			results[n-1].End = end
+			continue
+		}
+		results = append(results, scheduleShift{UserID: userID, Start: start, End: end})
+	}
+	return results
+}
+
+// scheduleCoverageGaps returns the gaps of an expanded timeline.
+func scheduleCoverageGaps(shifts []scheduleShift) []scheduleShift {
+	gaps := make([]scheduleShift, 0)
+	for _, s := range shifts {
+		if s.UserID == "" {
+			gaps = append(gaps, s)
+		}
+	}
+	return gaps
+}
+
+// scheduleLayersStart returns the earliest starts_on of the layers, or the zero
+// time if none can be parsed.
+func scheduleLayersStart(layers []ilert.ScheduleLayer, loc *time.Location) time.Time {
+	var earliest time.Time
+	for _, layer := range layers {
+		start, err := parseScheduleDateTime(layer.StartsOn, loc)
+		if err != nil {
+			continue
+		}
+		if earliest.IsZero() || start.Before(earliest) {
+			earliest = start
+		}
+	}
+	return earliest
+}
+
+// schedulePreviewWindow returns the span the layers are expanded over: from the
+// given start, or else from the first layer's starts_on, for the given horizon.
+// It is anchored to the configuration rather than to the current time, so the
+// preview stays the same from one plan to the next.
+func schedulePreviewWindow(layers []ilert.ScheduleLayer, loc *time.Location, from, horizon string) (time.Time, time.Time, error) {
+	var start time.Time
+	if from != "" {
+		t, err := parseScheduleDateTime(from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("preview_from %q is not a date time such as 2022-08-30T00:00", from)
+		}
+		start = t
+	} else {
+		start = scheduleLayersStart(layers, loc)
+	}
+	if start.IsZero() {
+		return time.Time{}, time.Time{}, nil
+	}
+	if horizon == "" {
+		horizon = defaultSchedulePreviewHorizon
+	}
+	duration, err := parseISODuration(horizon)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("preview_horizon %s", err.Error())
+	}
+	return start, duration.addTo(start, 1), nil
+}
+
+// flattenSchedulePreview formats an expanded timeline for the preview_shifts and
+// coverage_gaps attributes, as wall clock times in the schedule's timezone.
+func flattenSchedulePreview(shifts []scheduleShift, loc *time.Location) ([]any, []any) {
+	layout := scheduleDateTimeLayouts[1]
+	previewShifts := make([]any, 0)
+	gaps := make([]any, 0)
+	for _, s := range shifts {
+		if s.UserID == "" {
+			gaps = append(gaps, map[string]any{
+				"start": s.Start.In(loc).Format(layout),
+				"end":   s.End.In(loc).Format(layout),
+			})
+			continue
+		}
+		previewShifts = append(previewShifts, map[string]any{
+			"user":  s.UserID,
+			"start": s.Start.In(loc).Format(layout),
+			"end":   s.End.In(loc).Format(layout),
+		})
+	}
+	return previewShifts, gaps
+}
+
+// schedulePreview expands the configured layers of a recurring schedule over
+// its preview window and flattens the result. It also returns the end of the
+// window that was checked for gaps, which is earlier than the end of the preview
+// window when the expansion was cut short, or empty without any layer to expand.
+func schedulePreview(layerConfig []any, timezone, from, horizon string) ([]any, []any, string, error) {
+	layers, err := buildScheduleLayers(layerConfig)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("timezone %q is not a known IANA time zone: %s", timezone, err.Error())
+	}
+	start, end, err := schedulePreviewWindow(layers, loc, from, horizon)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if start.IsZero() {
+		return make([]any, 0), make([]any, 0), "", nil
+	}
+	shifts, expandedUntil, err := expandScheduleLayers(layers, timezone, start, end)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	previewShifts, gaps := flattenSchedulePreview(shifts, loc)
+	return previewShifts, gaps, expandedUntil.In(loc).Format(scheduleDateTimeLayouts[1]), nil
+}
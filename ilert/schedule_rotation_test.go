@@ -0,0 +1,305 @@
+package ilert
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestParseISODuration(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    isoDuration
+		wantErr bool
+	}{
+		{"P1D", isoDuration{Days: 1}, false},
+		{"P7D", isoDuration{Days: 7}, false},
+		{"P2W", isoDuration{Weeks: 2}, false},
+		{"PT8H", isoDuration{Clock: 8 * time.Hour}, false},
+		{"PT90M", isoDuration{Clock: 90 * time.Minute}, false},
+		{"P1DT12H", isoDuration{Days: 1, Clock: 12 * time.Hour}, false},
+		{"P1M", isoDuration{Months: 1}, false},
+		{"", isoDuration{}, true},
+		{"P", isoDuration{}, true},
+		{"PT", isoDuration{}, true},
+		{"P1DT", isoDuration{}, true},
+		{"1D", isoDuration{}, true},
+		{"P1H", isoDuration{}, true},
+		{"p1d", isoDuration{}, true},
+	}
+	for _, tc := range cases {
+		got, err := parseISODuration(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseISODuration(%q) expected error, got nil", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseISODuration(%q) unexpected error: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseISODuration(%q) = %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestISODurationAddTo_KeepsWallClockAcrossDaylightSavingTime(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	start := time.Date(2026, 3, 28, 9, 0, 0, 0, loc)
+
+	got := isoDuration{Days: 1}.addTo(start, 1)
+	if want := time.Date(2026, 3, 29, 9, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got.Sub(start) != 23*time.Hour {
+		t.Fatalf("expected the day of the switch to last 23 hours, got %s", got.Sub(start))
+	}
+}
+
+func TestExpandScheduleLayers_RotatesUsers(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2026-01-05T09:00", "P1D", 1, 2),
+	}
+
+	got := expandScheduleLayersForTest(t, layers, time.Date(2026, 1, 5, 9, 0, 0, 0, loc), time.Date(2026, 1, 8, 9, 0, 0, 0, loc))
+
+	want := []string{
+		"1 2026-01-05T09:00 2026-01-06T09:00",
+		"2 2026-01-06T09:00 2026-01-07T09:00",
+		"1 2026-01-07T09:00 2026-01-08T09:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_StartsMidRotation(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2026-01-01T00:00", "P7D", 1, 2, 3),
+	}
+
+	got := expandScheduleLayersForTest(t, layers, time.Date(2026, 1, 16, 0, 0, 0, 0, loc), time.Date(2026, 1, 23, 0, 0, 0, 0, loc))
+
+	want := []string{
+		"3 2026-01-16T00:00 2026-01-22T00:00",
+		"1 2026-01-22T00:00 2026-01-23T00:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_RestrictionsLeaveGaps(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layer := newScheduleLayer("2026-01-05T00:00", "P7D", 1)
+	layer.RestrictionType = "TIMES_OF_WEEK"
+	layer.Restrictions = []ilert.LayerRestriction{
+		newLayerRestriction("MONDAY", "09:00", "FRIDAY", "17:00"),
+	}
+
+	got := expandScheduleLayersForTest(t, []ilert.ScheduleLayer{layer}, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 12, 0, 0, 0, 0, loc))
+
+	want := []string{
+		"gap 2026-01-05T00:00 2026-01-05T09:00",
+		"1 2026-01-05T09:00 2026-01-09T17:00",
+		"gap 2026-01-09T17:00 2026-01-12T00:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_RestrictionWrapsAroundTheWeekend(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layer := newScheduleLayer("2026-01-05T00:00", "P7D", 1)
+	layer.RestrictionType = "TIMES_OF_WEEK"
+	layer.Restrictions = []ilert.LayerRestriction{
+		newLayerRestriction("FRIDAY", "18:00", "MONDAY", "08:00"),
+	}
+
+	got := expandScheduleLayersForTest(t, []ilert.ScheduleLayer{layer}, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 12, 0, 0, 0, 0, loc))
+
+	want := []string{
+		"1 2026-01-05T00:00 2026-01-05T08:00",
+		"gap 2026-01-05T08:00 2026-01-09T18:00",
+		"1 2026-01-09T18:00 2026-01-12T00:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_LaterLayersWin(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	business := newScheduleLayer("2026-01-05T00:00", "P7D", 2)
+	business.RestrictionType = "TIMES_OF_WEEK"
+	business.Restrictions = []ilert.LayerRestriction{
+		newLayerRestriction("MONDAY", "09:00", "MONDAY", "17:00"),
+	}
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2026-01-05T00:00", "P7D", 1),
+		business,
+	}
+
+	got := expandScheduleLayersForTest(t, layers, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 6, 0, 0, 0, 0, loc))
+
+	want := []string{
+		"1 2026-01-05T00:00 2026-01-05T09:00",
+		"2 2026-01-05T09:00 2026-01-05T17:00",
+		"1 2026-01-05T17:00 2026-01-06T00:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_EndsOnLeavesGap(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layer := newScheduleLayer("2026-01-05T00:00", "P1D", 1)
+	layer.EndsOn = "2026-01-06T12:00"
+
+	got := expandScheduleLayersForTest(t, []ilert.ScheduleLayer{layer}, time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 7, 0, 0, 0, 0, loc))
+
+	want := []string{
+		"1 2026-01-05T00:00 2026-01-06T12:00",
+		"gap 2026-01-06T12:00 2026-01-07T00:00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected shifts %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_SkipsRotationsBeforeTheWindow(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2016-01-04T09:00", "P1D", 1, 2, 3),
+	}
+	from := time.Date(2026, 1, 5, 9, 0, 0, 0, loc)
+
+	days := 0
+	for day := time.Date(2016, 1, 4, 9, 0, 0, 0, loc); day.Before(from); day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	users := []string{"1", "2", "3"}
+	want := []string{
+		users[days%3] + " 2026-01-05T09:00 2026-01-06T09:00",
+		users[(days+1)%3] + " 2026-01-06T09:00 2026-01-07T09:00",
+	}
+
+	got := expandScheduleLayersForTest(t, layers, from, time.Date(2026, 1, 7, 9, 0, 0, 0, loc))
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestExpandScheduleLayers_ReportsTruncation(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2026-01-05T00:00", "PT1S", 1),
+	}
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, loc)
+	until := time.Date(2026, 1, 7, 0, 0, 0, 0, loc)
+
+	shifts, expandedUntil, err := expandScheduleLayers(layers, "Europe/Berlin", from, until)
+	if err != nil {
+		t.Fatalf("unexpected error expanding layers: %v", err)
+	}
+	if want := from.Add(maxScheduleRotations * time.Second); !expandedUntil.Equal(want) {
+		t.Fatalf("expected the expansion to stop at %s, got %s", want, expandedUntil)
+	}
+	if gaps := scheduleCoverageGaps(shifts); len(gaps) != 0 {
+		t.Fatalf("expected no gaps after the expansion stopped, got %v", gaps)
+	}
+}
+
+func TestExpandScheduleLayers_InvalidRotation(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	layers := []ilert.ScheduleLayer{
+		newScheduleLayer("2026-01-05T00:00", "1 week", 1),
+	}
+	if _, _, err := expandScheduleLayers(layers, "Europe/Berlin", time.Date(2026, 1, 5, 0, 0, 0, 0, loc), time.Date(2026, 1, 6, 0, 0, 0, 0, loc)); err == nil {
+		t.Fatalf("expected an error for an invalid rotation")
+	}
+}
+
+func TestSchedulePreview_FromLayerConfig(t *testing.T) {
+	layers := []any{
+		map[string]any{
+			"name":             "layer1",
+			"starts_on":        "2026-01-05T09:00",
+			"rotation":         "P1D",
+			"restriction_type": "",
+			"user": []any{
+				map[string]any{"id": "1"},
+				map[string]any{"id": "2"},
+			},
+		},
+	}
+
+	previewShifts, gaps, checkedUntil, err := schedulePreview(layers, "Europe/Berlin", "", "P2D")
+	if err != nil {
+		t.Fatalf("unexpected error previewing schedule: %v", err)
+	}
+
+	want := []any{
+		map[string]any{"user": "1", "start": "2026-01-05T09:00", "end": "2026-01-06T09:00"},
+		map[string]any{"user": "2", "start": "2026-01-06T09:00", "end": "2026-01-07T09:00"},
+	}
+	if !reflect.DeepEqual(previewShifts, want) {
+		t.Fatalf("expected preview shifts %v, got %v", want, previewShifts)
+	}
+	if len(gaps) != 0 {
+		t.Fatalf("expected no gaps, got %v", gaps)
+	}
+	if checkedUntil != "2026-01-07T09:00" {
+		t.Fatalf("expected the preview window to be checked until 2026-01-07T09:00, got %q", checkedUntil)
+	}
+}
+
+func expandScheduleLayersForTest(t *testing.T, layers []ilert.ScheduleLayer, from, until time.Time) []string {
+	t.Helper()
+	shifts, expandedUntil, err := expandScheduleLayers(layers, "Europe/Berlin", from, until)
+	if err != nil {
+		t.Fatalf("unexpected error expanding layers: %v", err)
+	}
+	if !expandedUntil.Equal(until) {
+		t.Fatalf("expected the layers to be expanded until %s, got %s", until, expandedUntil)
+	}
+	got := make([]string, 0, len(shifts))
+	for _, s := range shifts {
+		user := s.UserID
+		if user == "" {
+			user = "gap"
+		}
+		got = append(got, user+" "+s.Start.Format("2006-01-02T15:04")+" "+s.End.Format("2006-01-02T15:04"))
+	}
+	return got
+}
+
+func newScheduleLayer(startsOn, rotation string, userIDs ...int64) ilert.ScheduleLayer {
+	users := make([]ilert.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, ilert.User{ID: id})
+	}
+	return ilert.ScheduleLayer{
+		Name:     "layer",
+		StartsOn: startsOn,
+		Rotation: rotation,
+		Users:    users,
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("unexpected error loading location %s: %v", name, err)
+	}
+	return loc
+}
@@ -0,0 +1,296 @@
+package ilert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Alert source templates are text with {{ ... }} placeholders. A placeholder
+// holds a path into the inbound event, such as {{ subject }} or
+// {{ body.labels["env"] }}, optionally followed by function calls, such as
+// {{ subject.splitTakeAt("my server", 0).trim() }}. Paths that do not exist
+// in the event render as an empty string.
+
+// templateFunction describes a function a placeholder may call, with the
+// number of arguments it takes. The provider only knows a subset of the
+// functions of ilert's template language: a template calling any other function
+// is accepted, but cannot be rendered into rendered_preview.
+type templateFunction struct {
+	minArgs, maxArgs int
+	apply            func(value string, args []any) string
+}
+
+var templateFunctions = map[string]templateFunction{
+	"splitTakeAt": {2, 2, func(value string, args []any) string {
+		parts := strings.Split(value, templateValueString(args[0]))
+		i := templateArgInt(args[1])
+		if i < 0 {
+			i += len(parts)
+		}
+		if i < 0 || i >= len(parts) {
+			return ""
+		}
+		return parts[i]
+	}},
+	"substringBefore": {1, 1, func(value string, args []any) string {
+		before, _, _ := strings.Cut(value, templateValueString(args[0]))
+		return before
+	}},
+	"substringAfter": {1, 1, func(value string, args []any) string {
+		_, after, found := strings.Cut(value, templateValueString(args[0]))
+		if !found {
+			return ""
+		}
+		return after
+	}},
+	"substring": {1, 2, func(value string, args []any) string {
+		r := []rune(value)
+		start, end := min(max(templateArgInt(args[0]), 0), len(r)), len(r)
+		if len(args) > 1 {
+			end = min(max(templateArgInt(args[1]), start), len(r))
+		}
+		return string(r[start:end])
+	}},
+	"replace": {2, 2, func(value string, args []any) string {
+		return strings.ReplaceAll(value, templateValueString(args[0]), templateValueString(args[1]))
+	}},
+	"toUpperCase": {0, 0, func(value string, args []any) string {
+		return strings.ToUpper(value)
+	}},
+	"toLowerCase": {0, 0, func(value string, args []any) string {
+		return strings.ToLower(value)
+	}},
+	"trim": {0, 0, func(value string, args []any) string {
+		return strings.TrimSpace(value)
+	}},
+}
+
+type templateCall struct {
+	name string
+	args []any
+}
+
+type templatePart struct {
+	text     string
+	path     []any
+	calls    []templateCall
+	variable bool
+}
+
+// parseTemplate splits a template into its text and placeholders, returning
+// an *iclSyntaxError pointing into the template when a placeholder is not
+// closed or is malformed, or calls a known function with the wrong number of
+// arguments. A "}}" outside of a placeholder is text, such as in the JSON of a
+// details template.
+func parseTemplate(template string) ([]templatePart, error) {
+	parts := make([]templatePart, 0)
+	rest, offset := template, 0
+	for rest != "" {
+		open := strings.Index(rest, "{{")
+		if open < 0 {
+			parts = append(parts, templatePart{text: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, templatePart{text: rest[:open]})
+		}
+		end := strings.Index(rest[open+2:], "}}")
+		if end < 0 {
+			return nil, &iclSyntaxError{Pos: offset + open, Msg: `unclosed "{{"`}
+		}
+		part, err := parseTemplatePlaceholder(rest[open+2:open+2+end], offset+open+2)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		consumed := open + 2 + end + 2
+		rest, offset = rest[consumed:], offset+consumed
+	}
+	return parts, nil
+}
+
+func parseTemplatePlaceholder(src string, offset int) (templatePart, error) {
+	part := templatePart{variable: true}
+	tokens, err := lexICL(src)
+	if err != nil {
+		if syntaxErr, ok := err.(*iclSyntaxError); ok {
+			syntaxErr.Pos += offset
+		}
+		return part, err
+	}
+	for i := range tokens {
+		tokens[i].pos += offset
+	}
+	p := &iclParser{tokens: tokens}
+
+	first := p.next()
+	if first.kind == iclTokenEOF {
+		return part, &iclSyntaxError{Pos: offset - 2, Msg: "empty placeholder"}
+	}
+	if first.kind != iclTokenIdent {
+		return part, p.unexpected(first, "a field name")
+	}
+	part.path = []any{first.text}
+
+	for p.peek().kind != iclTokenEOF {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			t := p.next()
+			if t.kind != iclTokenIdent {
+				return part, p.unexpected(t, "a field or function name")
+			}
+			if !p.isOperator("(") {
+				if len(part.calls) > 0 {
+					return part, &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected a function call, got the field %q after a function call", t.text)}
+				}
+				part.path = append(part.path, t.text)
+				continue
+			}
+			call, err := parseTemplateCall(p, t)
+			if err != nil {
+				return part, err
+			}
+			part.calls = append(part.calls, call)
+		case p.isOperator("[") && len(part.calls) == 0:
+			p.next()
+			t := p.next()
+			switch t.kind {
+			case iclTokenString:
+				part.path = append(part.path, t.value.(string))
+			case iclTokenNumber:
+				n := t.value.(float64)
+				if n < 0 || n != math.Trunc(n) {
+					return part, &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected a list index, got %q", t.text)}
+				}
+				part.path = append(part.path, int(n))
+			default:
+				return part, p.unexpected(t, "a key or index")
+			}
+			if _, err := p.expect("]"); err != nil {
+				return part, err
+			}
+		default:
+			t := p.peek()
+			return part, &iclSyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+		}
+	}
+	return part, nil
+}
+
+func parseTemplateCall(p *iclParser, name iclToken) (templateCall, error) {
+	call := templateCall{name: name.text, args: make([]any, 0)}
+	p.next()
+	if !p.isOperator(")") {
+		for {
+			t := p.next()
+			if t.kind != iclTokenString && t.kind != iclTokenNumber {
+				return call, p.unexpected(t, "a string or number argument")
+			}
+			call.args = append(call.args, t.value)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if _, err := p.expect(")"); err != nil {
+		return call, err
+	}
+	fn, ok := templateFunctions[name.text]
+	if !ok {
+		return call, nil
+	}
+	if len(call.args) < fn.minArgs || len(call.args) > fn.maxArgs {
+		want := strconv.Itoa(fn.minArgs)
+		if fn.maxArgs != fn.minArgs {
+			want = fmt.Sprintf("%d to %d", fn.minArgs, fn.maxArgs)
+		}
+		return call, &iclSyntaxError{Pos: name.pos, Msg: fmt.Sprintf("function %q takes %s arguments, got %d", name.text, want, len(call.args))}
+	}
+	return call, nil
+}
+
+// templateUnknownFunctionError is returned when a template calls a function
+// the provider cannot render.
+type templateUnknownFunctionError struct {
+	Name string
+}
+
+func (e *templateUnknownFunctionError) Error() string {
+	return fmt.Sprintf("the provider cannot render the function %q", e.Name)
+}
+
+// renderTemplate renders a template against an event, given as decoded JSON.
+func renderTemplate(template string, event map[string]any) (string, error) {
+	parts, err := parseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	for _, part := range parts {
+		for _, call := range part.calls {
+			if _, ok := templateFunctions[call.name]; !ok {
+				return "", &templateUnknownFunctionError{Name: call.name}
+			}
+		}
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		if !part.variable {
+			b.WriteString(part.text)
+			continue
+		}
+		value := templateValueString(lookupICLPath(event, part.path))
+		for _, call := range part.calls {
+			value = templateFunctions[call.name].apply(value, call.args)
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// templateValueString writes a value of the event the way it is placed into
+// the rendered text: strings as they are, numbers without a trailing .0 and
+// objects and lists as JSON.
+func templateValueString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func templateArgInt(v any) int {
+	switch t := v.(type) {
+	case float64:
+		return int(t)
+	case string:
+		n, _ := strconv.Atoi(t)
+		return n
+	}
+	return 0
+}
+
+func validateTemplateText(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseTemplate(value); err != nil {
+		return nil, []error{fmt.Errorf("invalid template in %s: %s", k, err.Error())}
+	}
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	event := map[string]any{
+		"subject": "ALARM my server db-1 is down",
+		"body": map[string]any{
+			"labels": map[string]any{"env": "prod", "team name": "platform"},
+			"hosts":  []any{"db-1", "db-2"},
+			"count":  float64(3),
+		},
+	}
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{"plain text", "plain text"},
+		{"{{ subject }}", "ALARM my server db-1 is down"},
+		{"[{{body.labels.env}}] {{ body.count }} hosts", "[prod] 3 hosts"},
+		{`{{ body.labels["team name"] }}/{{ body.hosts[1] }}`, "platform/db-2"},
+		{`{{ subject.splitTakeAt("my server", 1).trim() }}`, "db-1 is down"},
+		{`{{ subject.substringAfter("ALARM ").substringBefore(" is").toUpperCase() }}`, "MY SERVER DB-1"},
+		{`{{ subject.substring(0, 5).toLowerCase() }}`, "alarm"},
+		{`{{ subject.replace("down", "up") }}`, "ALARM my server db-1 is up"},
+		{"{{ body.hosts }}", `["db-1","db-2"]`},
+		{"missing: '{{ body.missing.field }}'", "missing: ''"},
+	}
+	for _, tc := range cases {
+		got, err := renderTemplate(tc.template, event)
+		if err != nil {
+			t.Errorf("renderTemplate(%q) returned an error: %v", tc.template, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("renderTemplate(%q) = %q, want %q", tc.template, got, tc.want)
+		}
+	}
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{"Alert {{ subject", `unclosed "{{" at position 7`},
+		{"Alert {{  }}", "empty placeholder at position 7"},
+		{`{{ subject.splitTakeAt(",") }}`, `function "splitTakeAt" takes 2 arguments, got 1 at position 12`},
+		{"{{ subject.trim().length }}", `expected a function call, got the field "length" after a function call at position 19`},
+		{"{{ body..labels }}", `expected a field or function name, got "." at position 9`},
+		{"{{ body.labels[-1] }}", `expected a list index, got "-1" at position 16`},
+		{"{{ subject == 1 }}", `unexpected "==" at position 12`},
+		{`{{ subject.replace("a, "b") }}`, "unterminated string at position 26"},
+	}
+	for _, tc := range cases {
+		_, err := parseTemplate(tc.template)
+		if err == nil {
+			t.Errorf("parseTemplate(%q) expected an error", tc.template)
+			continue
+		}
+		if err.Error() != tc.want {
+			t.Errorf("parseTemplate(%q) = %q, want %q", tc.template, err.Error(), tc.want)
+		}
+	}
+}
+
+func TestParseTemplate_ClosingBracesOutsideOfPlaceholders(t *testing.T) {
+	event := map[string]any{"subject": "db-1 is down"}
+	got, err := renderTemplate(`{"a":{"b":1}} {{ subject }} }}`, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"a":{"b":1}} db-1 is down }}`; got != want {
+		t.Fatalf("renderTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTemplate_UnknownFunction(t *testing.T) {
+	if _, err := parseTemplate(`{{ subject.base64Encode() }}`); err != nil {
+		t.Fatalf("expected a template calling an unknown function to parse, got %v", err)
+	}
+	_, err := renderTemplate(`{{ subject.base64Encode() }}`, map[string]any{"subject": "x"})
+	if _, ok := err.(*templateUnknownFunctionError); !ok {
+		t.Fatalf("expected an unknown function error, got %v", err)
+	}
+}
+
+func TestValidateTemplateText(t *testing.T) {
+	if _, errs := validateTemplateText(`{{ subject.splitTakeAt("my server", 0) }}`, "text_template"); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	_, errs := validateTemplateText("{{ subject", "summary_template.0.text_template")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "invalid template in summary_template.0.text_template") {
+		t.Fatalf("expected an error naming the attribute, got %v", errs)
+	}
+}
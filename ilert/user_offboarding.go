@@ -0,0 +1,486 @@
+package ilert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iLert/ilert-go/v3"
+)
+
+// userReferencesPageSize is the page size used to list the escalation
+// policies, schedules and teams that may reference a user about to be deleted.
+const userReferencesPageSize = 100
+
+func userOnDestroySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"replacement_user": {
+					Type:     schema.TypeString,
+					Optional: true,
+					ValidateFunc: validation.StringMatch(
+						regexp.MustCompile(`^[0-9]+$`),
+						"must be a numeric user id",
+					),
+				},
+				"reassign_schedule_layers": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"reassign_escalation_rules": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"remove_from_teams": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+				"block_if_referenced": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+// userOffboarding is the on_destroy block of a user: what to do with the
+// references to the user before it is deleted.
+type userOffboarding struct {
+	replacementUser         int64
+	reassignScheduleLayers  bool
+	reassignEscalationRules bool
+	removeFromTeams         bool
+	blockIfReferenced       bool
+}
+
+// expandUserOffboarding reads the on_destroy block, or returns nil when there
+// is none.
+func expandUserOffboarding(v any) (*userOffboarding, error) {
+	vL, ok := v.([]any)
+	if !ok || len(vL) == 0 || vL[0] == nil {
+		return nil, nil
+	}
+	m := vL[0].(map[string]any)
+	options := &userOffboarding{
+		reassignScheduleLayers:  m["reassign_schedule_layers"].(bool),
+		reassignEscalationRules: m["reassign_escalation_rules"].(bool),
+		removeFromTeams:         m["remove_from_teams"].(bool),
+		blockIfReferenced:       m["block_if_referenced"].(bool),
+	}
+	if s, _ := m["replacement_user"].(string); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, unconvertibleIDErr(s, err)
+		}
+		options.replacementUser = id
+	}
+	if options.replacementUser == 0 && (options.reassignScheduleLayers || options.reassignEscalationRules) {
+		return nil, fmt.Errorf("on_destroy: replacement_user must be set to reassign schedule layers or escalation rules")
+	}
+	return options, nil
+}
+
+// customizeUserOnDestroy checks the on_destroy block at plan time, as it is
+// only read once the user is destroyed.
+func customizeUserOnDestroy(ctx context.Context, diff *schema.ResourceDiff, m any) error {
+	if !diffValuesKnown(diff, "on_destroy") {
+		return nil
+	}
+	options, err := expandUserOffboarding(diff.Get("on_destroy"))
+	if err != nil || options == nil {
+		return err
+	}
+	if diff.Id() != "" && strconv.FormatInt(options.replacementUser, 10) == diff.Id() {
+		return fmt.Errorf("on_destroy: replacement_user must not be the user itself")
+	}
+	return nil
+}
+
+// userReference is a place that names a user: an escalation rule, a schedule
+// layer or a team membership. handled is set when the on_destroy block takes
+// care of it.
+type userReference struct {
+	kind    string
+	id      int64
+	name    string
+	where   string
+	handled bool
+}
+
+func (r userReference) String() string {
+	return fmt.Sprintf("%s %q (id %d): %s", r.kind, r.name, r.id, r.where)
+}
+
+// userOffboardingPlan lists the references to a user and the escalation
+// policies and schedules with the user replaced, and the teams to remove the
+// user from, as far as the on_destroy block asks for it.
+type userOffboardingPlan struct {
+	references         []userReference
+	escalationPolicies []*ilert.EscalationPolicy
+	schedules          []*ilert.Schedule
+	teams              []int64
+}
+
+func (p userOffboardingPlan) unhandled() []userReference {
+	result := make([]userReference, 0)
+	for _, reference := range p.references {
+		if !reference.handled {
+			result = append(result, reference)
+		}
+	}
+	return result
+}
+
+// planUserOffboarding finds the references to the user in the given
+// escalation policies, schedules and teams, and plans the changes the options
+// ask for. The given objects are not modified.
+func planUserOffboarding(userID int64, options userOffboarding, policies []*ilert.EscalationPolicy, schedules []*ilert.Schedule, teams []*ilert.Team) userOffboardingPlan {
+	plan := userOffboardingPlan{}
+
+	for _, policy := range policies {
+		if policy == nil {
+			continue
+		}
+		rules := make([]ilert.EscalationRule, 0, len(policy.EscalationRules))
+		changed := false
+		for i, rule := range policy.EscalationRules {
+			referenced := rule.User != nil && rule.User.ID == userID
+			users, inUsers := replaceUser(rule.Users, userID, options.replacementUser)
+			referenced = referenced || inUsers
+			if referenced {
+				plan.references = append(plan.references, userReference{
+					kind:    "escalation policy",
+					id:      policy.ID,
+					name:    policy.Name,
+					where:   fmt.Sprintf("escalation rule %d", i+1),
+					handled: options.reassignEscalationRules,
+				})
+			}
+			if referenced && options.reassignEscalationRules {
+				if rule.User != nil && rule.User.ID == userID {
+					rule.User = &ilert.User{ID: options.replacementUser}
+				}
+				rule.Users = users
+				changed = true
+			}
+			rules = append(rules, rule)
+		}
+		if changed {
+			updated := *policy
+			updated.EscalationRules = rules
+			plan.escalationPolicies = append(plan.escalationPolicies, &updated)
+		}
+	}
+
+	for _, schedule := range schedules {
+		if schedule == nil {
+			continue
+		}
+		layers := make([]ilert.ScheduleLayer, 0, len(schedule.ScheduleLayers))
+		changed := false
+		for i, layer := range schedule.ScheduleLayers {
+			users, referenced := replaceUser(layer.Users, userID, options.replacementUser)
+			if referenced {
+				where := fmt.Sprintf("schedule layer %d", i+1)
+				if layer.Name != "" {
+					where = fmt.Sprintf("schedule layer %q", layer.Name)
+				}
+				plan.references = append(plan.references, userReference{
+					kind:    "schedule",
+					id:      schedule.ID,
+					name:    schedule.Name,
+					where:   where,
+					handled: options.reassignScheduleLayers,
+				})
+			}
+			if referenced && options.reassignScheduleLayers {
+				layer.Users = users
+				changed = true
+			}
+			layers = append(layers, layer)
+		}
+		if changed {
+			updated := *schedule
+			updated.ScheduleLayers = layers
+			plan.schedules = append(plan.schedules, &updated)
+		}
+	}
+
+	for _, team := range teams {
+		if team == nil || findTeamMember(team.Members, userID) == nil {
+			continue
+		}
+		plan.references = append(plan.references, userReference{
+			kind:    "team",
+			id:      team.ID,
+			name:    team.Name,
+			where:   "member",
+			handled: options.removeFromTeams,
+		})
+		if options.removeFromTeams {
+			plan.teams = append(plan.teams, team.ID)
+		}
+	}
+
+	return plan
+}
+
+// replaceUser returns the users with the user replaced by the replacement, or
+// without the user when there is no replacement or it is listed already, and
+// whether the user was listed at all.
+func replaceUser(users []ilert.User, userID, replacement int64) ([]ilert.User, bool) {
+	result := make([]ilert.User, 0, len(users))
+	found := false
+	listed := false
+	for _, user := range users {
+		if user.ID == replacement {
+			listed = true
+		}
+	}
+	for _, user := range users {
+		if user.ID != userID {
+			result = append(result, user)
+			continue
+		}
+		found = true
+		if replacement != 0 && !listed {
+			result = append(result, ilert.User{ID: replacement})
+			listed = true
+		}
+	}
+	return result, found
+}
+
+// offboardUser runs the on_destroy block of a user before it is deleted. It
+// fails without changing anything when block_if_referenced is set and some
+// references are not taken care of, otherwise it warns about them.
+func offboardUser(ctx context.Context, d *schema.ResourceData, client *ilert.Client, userID int64) diag.Diagnostics {
+	options, err := expandUserOffboarding(d.Get("on_destroy"))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if options == nil {
+		return nil
+	}
+	timeout := d.Timeout(schema.TimeoutDelete)
+
+	policies, err := listUserReferencePolicies(ctx, client, timeout)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	schedules, err := listUserReferenceSchedules(ctx, client, timeout)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	teams, err := listUserReferenceTeams(ctx, client, timeout)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	plan := planUserOffboarding(userID, *options, policies, schedules, teams)
+	unhandled := plan.unhandled()
+	if options.blockIfReferenced && len(unhandled) > 0 {
+		return diag.Diagnostics{userReferencesDiagnostic(d.Get("email").(string), unhandled)}
+	}
+	var diags diag.Diagnostics
+	if len(unhandled) > 0 {
+		diags = append(diags, userReferencesWarning(d.Get("email").(string), unhandled))
+	}
+
+	for _, policy := range plan.escalationPolicies {
+		log.Printf("[INFO] Reassigning the escalation rules of escalation policy %d from user %d to user %d", policy.ID, userID, options.replacementUser)
+		if err := retryUserReference(ctx, timeout, fmt.Sprintf("update escalation policy %d", policy.ID), func() error {
+			_, err := client.UpdateEscalationPolicy(&ilert.UpdateEscalationPolicyInput{EscalationPolicy: policy, EscalationPolicyID: ilert.Int64(policy.ID)})
+			return err
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	for _, schedule := range plan.schedules {
+		log.Printf("[INFO] Reassigning the layers of schedule %d from user %d to user %d", schedule.ID, userID, options.replacementUser)
+		if err := retryUserReference(ctx, timeout, fmt.Sprintf("update schedule %d", schedule.ID), func() error {
+			_, err := client.UpdateSchedule(&ilert.UpdateScheduleInput{Schedule: schedule, ScheduleID: ilert.Int64(schedule.ID)})
+			return err
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	for _, teamID := range plan.teams {
+		log.Printf("[INFO] Removing user %d from team %d", userID, teamID)
+		if err := removeTeamMember(ctx, client, teamID, userID, timeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diags
+}
+
+func userReferencesDiagnostic(email string, references []userReference) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Cannot delete user %q: it is still referenced in %d place(s)", email, len(references)),
+		Detail: fmt.Sprintf("Remove the user from the following places, or let on_destroy reassign or remove them, before destroying the user:\n%s",
+			userReferenceLines(references)),
+	}
+}
+
+// userReferencesWarning lists the references a user is deleted with when
+// block_if_referenced is not set.
+func userReferencesWarning(email string, references []userReference) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("User %q is deleted while still referenced in %d place(s)", email, len(references)),
+		Detail: fmt.Sprintf("The user is not reassigned or removed in the following places, check them for gaps in the coverage:\n%s",
+			userReferenceLines(references)),
+	}
+}
+
+func userReferenceLines(references []userReference) string {
+	lines := make([]string, 0, len(references))
+	for _, reference := range references {
+		lines = append(lines, "- "+reference.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// removeTeamMember removes the user from the team, keeping all other members.
+func removeTeamMember(ctx context.Context, client *ilert.Client, teamID, userID int64, timeout time.Duration) error {
+	teamKey := strconv.FormatInt(teamID, 10)
+	teamMemberLock.Lock(teamKey)
+	defer teamMemberLock.Unlock(teamKey)
+
+	team, err := getTeamForMembership(ctx, client, teamID, timeout)
+	if err != nil {
+		if _, ok := err.(*ilert.NotFoundAPIError); ok {
+			return nil
+		}
+		return err
+	}
+	if findTeamMember(team.Members, userID) == nil {
+		return nil
+	}
+	members := make([]ilert.TeamMember, 0, len(team.Members))
+	for _, member := range team.Members {
+		if member.User.ID != userID {
+			members = append(members, member)
+		}
+	}
+	return updateTeamMembers(ctx, client, teamID, team, members, timeout)
+}
+
+func retryUserReference(ctx context.Context, timeout time.Duration, what string, f func() error) error {
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		if err := f(); err != nil {
+			if _, ok := err.(*ilert.RetryableAPIError); ok {
+				time.Sleep(2 * time.Second)
+				return resource.RetryableError(fmt.Errorf("waiting to %s, error: %s", what, err.Error()))
+			}
+			return resource.NonRetryableError(fmt.Errorf("could not %s, error: %s", what, err.Error()))
+		}
+		return nil
+	})
+}
+
+// listUserReferences lists all pages of a kind of object. list returns the
+// number of objects on the page.
+func listUserReferences(ctx context.Context, timeout time.Duration, kind string, list func(start int) (int, error)) error {
+	for start := 0; ; start += userReferencesPageSize {
+		n := 0
+		err := retryUserReference(ctx, timeout, "list "+kind, func() error {
+			r, err := list(start)
+			n = r
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if n < userReferencesPageSize {
+			return nil
+		}
+	}
+}
+
+func listUserReferencePolicies(ctx context.Context, client *ilert.Client, timeout time.Duration) ([]*ilert.EscalationPolicy, error) {
+	policies := make([]*ilert.EscalationPolicy, 0)
+	err := listUserReferences(ctx, timeout, "escalation policies", func(start int) (int, error) {
+		r, err := client.GetEscalationPolicies(&ilert.GetEscalationPoliciesInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(userReferencesPageSize)})
+		if err != nil || r == nil {
+			return 0, err
+		}
+		policies = append(policies, r.EscalationPolicies...)
+		return len(r.EscalationPolicies), nil
+	})
+	return policies, err
+}
+
+// listUserReferenceSchedules lists the recurring schedules with their layers.
+// Listing schedules does not include the layers, so each recurring schedule is
+// read on its own.
+func listUserReferenceSchedules(ctx context.Context, client *ilert.Client, timeout time.Duration) ([]*ilert.Schedule, error) {
+	listed := make([]*ilert.Schedule, 0)
+	err := listUserReferences(ctx, timeout, "schedules", func(start int) (int, error) {
+		r, err := client.GetSchedules(&ilert.GetSchedulesInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(userReferencesPageSize)})
+		if err != nil || r == nil {
+			return 0, err
+		}
+		listed = append(listed, r.Schedules...)
+		return len(r.Schedules), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*ilert.Schedule, 0, len(listed))
+	for _, schedule := range listed {
+		if schedule == nil || schedule.Type != ilert.ScheduleType.Recurring {
+			continue
+		}
+		var result *ilert.Schedule
+		err := retryUserReference(ctx, timeout, fmt.Sprintf("read schedule %d", schedule.ID), func() error {
+			r, err := client.GetSchedule(&ilert.GetScheduleInput{ScheduleID: ilert.Int64(schedule.ID), Include: []*string{ilert.String("scheduleLayers")}})
+			if err != nil {
+				return err
+			}
+			if r != nil {
+				result = r.Schedule
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			schedules = append(schedules, result)
+		}
+	}
+	return schedules, nil
+}
+
+func listUserReferenceTeams(ctx context.Context, client *ilert.Client, timeout time.Duration) ([]*ilert.Team, error) {
+	teams := make([]*ilert.Team, 0)
+	err := listUserReferences(ctx, timeout, "teams", func(start int) (int, error) {
+		r, err := client.GetTeams(&ilert.GetTeamsInput{StartIndex: ilert.Int(start), MaxResults: ilert.Int(userReferencesPageSize)})
+		if err != nil || r == nil {
+			return 0, err
+		}
+		teams = append(teams, r.Teams...)
+		return len(r.Teams), nil
+	})
+	return teams, err
+}
@@ -0,0 +1,140 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/iLert/ilert-go/v3"
+)
+
+func TestExpandUserOffboarding(t *testing.T) {
+	options, err := expandUserOffboarding([]any{})
+	if err != nil || options != nil {
+		t.Fatalf("expected no options without a block, got %+v, %v", options, err)
+	}
+
+	block := map[string]any{
+		"replacement_user":          "",
+		"reassign_schedule_layers":  true,
+		"reassign_escalation_rules": false,
+		"remove_from_teams":         false,
+		"block_if_referenced":       false,
+	}
+	if _, err := expandUserOffboarding([]any{block}); err == nil || !strings.Contains(err.Error(), "replacement_user must be set") {
+		t.Fatalf("expected a missing replacement error, got %v", err)
+	}
+
+	block["replacement_user"] = "42"
+	options, err = expandUserOffboarding([]any{block})
+	if err != nil || options.replacementUser != 42 || !options.reassignScheduleLayers {
+		t.Fatalf("unexpected options %+v, %v", options, err)
+	}
+}
+
+func TestReplaceUser(t *testing.T) {
+	users := []ilert.User{{ID: 1}, {ID: 2}, {ID: 3}}
+	cases := []struct {
+		userID, replacement int64
+		want                []int64
+		found               bool
+	}{
+		{2, 9, []int64{1, 9, 3}, true},
+		{2, 3, []int64{1, 3}, true},
+		{2, 0, []int64{1, 3}, true},
+		{7, 9, []int64{1, 2, 3}, false},
+	}
+	for _, tc := range cases {
+		got, found := replaceUser(users, tc.userID, tc.replacement)
+		ids := make([]int64, 0, len(got))
+		for _, user := range got {
+			ids = append(ids, user.ID)
+		}
+		if found != tc.found || len(ids) != len(tc.want) {
+			t.Errorf("replace %d by %d: got %v, %v, want %v, %v", tc.userID, tc.replacement, ids, found, tc.want, tc.found)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tc.want[i] {
+				t.Errorf("replace %d by %d: got %v, want %v", tc.userID, tc.replacement, ids, tc.want)
+				break
+			}
+		}
+	}
+	if users[1].ID != 2 {
+		t.Errorf("expected the given users not to be modified")
+	}
+}
+
+func TestPlanUserOffboarding(t *testing.T) {
+	policies := []*ilert.EscalationPolicy{
+		{
+			ID:   10,
+			Name: "Default",
+			EscalationRules: []ilert.EscalationRule{
+				{EscalationTimeout: 0, User: &ilert.User{ID: 1}},
+				{EscalationTimeout: 10, Users: []ilert.User{{ID: 2}, {ID: 1}}},
+			},
+		},
+		{ID: 11, Name: "Other", EscalationRules: []ilert.EscalationRule{{User: &ilert.User{ID: 2}}}},
+	}
+	schedules := []*ilert.Schedule{
+		{ID: 20, Name: "Primary", ScheduleLayers: []ilert.ScheduleLayer{
+			{Name: "Weekdays", Users: []ilert.User{{ID: 1}, {ID: 3}}},
+			{Users: []ilert.User{{ID: 3}}},
+			{Users: []ilert.User{{ID: 1}}},
+		}},
+	}
+	teams := []*ilert.Team{
+		{ID: 30, Name: "Platform", Members: []ilert.TeamMember{{User: ilert.User{ID: 1}, Role: "RESPONDER"}}},
+		{ID: 31, Name: "Support", Members: []ilert.TeamMember{{User: ilert.User{ID: 2}, Role: "RESPONDER"}}},
+	}
+
+	plan := planUserOffboarding(1, userOffboarding{}, policies, schedules, teams)
+	got := make([]string, 0)
+	for _, reference := range plan.unhandled() {
+		got = append(got, reference.String())
+	}
+	want := []string{
+		`escalation policy "Default" (id 10): escalation rule 1`,
+		`escalation policy "Default" (id 10): escalation rule 2`,
+		`schedule "Primary" (id 20): schedule layer "Weekdays"`,
+		`schedule "Primary" (id 20): schedule layer 3`,
+		`team "Platform" (id 30): member`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected references\n got: %s\nwant: %s", strings.Join(got, "\n      "), strings.Join(want, "\n      "))
+	}
+	if len(plan.escalationPolicies) != 0 || len(plan.schedules) != 0 || len(plan.teams) != 0 {
+		t.Fatalf("expected no changes without options, got %+v", plan)
+	}
+	warning := userReferencesWarning("jane@example.com", plan.unhandled())
+	if warning.Severity != diag.Warning || !strings.Contains(warning.Summary, "5 place(s)") || !strings.Contains(warning.Detail, `- team "Platform" (id 30): member`) {
+		t.Fatalf("unexpected warning %+v", warning)
+	}
+
+	plan = planUserOffboarding(1, userOffboarding{replacementUser: 2, reassignEscalationRules: true, reassignScheduleLayers: true, removeFromTeams: true}, policies, schedules, teams)
+	if len(plan.unhandled()) != 0 {
+		t.Fatalf("expected every reference to be handled, got %v", plan.unhandled())
+	}
+	if len(plan.escalationPolicies) != 1 || plan.escalationPolicies[0].ID != 10 {
+		t.Fatalf("expected escalation policy 10 to be updated, got %+v", plan.escalationPolicies)
+	}
+	rules := plan.escalationPolicies[0].EscalationRules
+	if rules[0].User.ID != 2 || len(rules[1].Users) != 1 || rules[1].Users[0].ID != 2 || rules[1].EscalationTimeout != 10 {
+		t.Fatalf("unexpected escalation rules %+v", rules)
+	}
+	if policies[0].EscalationRules[0].User.ID != 1 {
+		t.Fatalf("expected the listed escalation policy not to be modified")
+	}
+	if len(plan.schedules) != 1 {
+		t.Fatalf("expected schedule 20 to be updated, got %+v", plan.schedules)
+	}
+	layers := plan.schedules[0].ScheduleLayers
+	if len(layers) != 3 || layers[0].Users[0].ID != 2 || layers[0].Users[1].ID != 3 || layers[2].Users[0].ID != 2 {
+		t.Fatalf("unexpected schedule layers %+v", layers)
+	}
+	if len(plan.teams) != 1 || plan.teams[0] != 30 {
+		t.Fatalf("expected to leave team 30, got %v", plan.teams)
+	}
+}
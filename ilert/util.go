@@ -2,6 +2,9 @@ package ilert
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func unconvertibleIDErr(id string, err error) *unconvertibleIDError {
@@ -22,3 +25,34 @@ func (e *unconvertibleIDError) Error() string {
 func Bool(v bool) *bool {
 	return &v
 }
+
+// diffValuesKnown reports whether every value below key is known at plan time.
+// NewValueKnown on a list or a block only covers its length, so a value computed
+// from another resource deeper in the block has to be checked on its own. The
+// elements of a set have no stable address, so a top-level set is checked
+// against the raw configuration instead.
+func diffValuesKnown(diff *schema.ResourceDiff, key string) bool {
+	if !diff.NewValueKnown(key) {
+		return false
+	}
+	switch v := diff.Get(key).(type) {
+	case []any:
+		for i := range v {
+			if !diffValuesKnown(diff, fmt.Sprintf("%s.%d", key, i)) {
+				return false
+			}
+		}
+	case map[string]any:
+		for k := range v {
+			if !diffValuesKnown(diff, key+"."+k) {
+				return false
+			}
+		}
+	case *schema.Set:
+		config := diff.GetRawConfig()
+		if !strings.Contains(key, ".") && !config.IsNull() && config.IsKnown() {
+			return config.GetAttr(key).IsWhollyKnown()
+		}
+	}
+	return true
+}
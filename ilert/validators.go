@@ -0,0 +1,155 @@
+package ilert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	// Embeds the IANA time zone database, so timezones validate the same way on
+	// hosts without zoneinfo files, such as Windows or distroless images.
+	_ "time/tzdata"
+)
+
+var timeOfDayRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// validateTimezone checks that a timezone is an IANA time zone name such as
+// Europe/Berlin, instead of leaving typos for the API to reject mid-apply.
+func validateTimezone(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if value == "" || value == "Local" {
+		return nil, []error{fmt.Errorf("expected %s to be an IANA time zone such as Europe/Berlin, got %q", k, value)}
+	}
+	if _, err := time.LoadLocation(value); err != nil {
+		if suggestion := suggestTimezone(value); suggestion != "" {
+			return nil, []error{fmt.Errorf("expected %s to be an IANA time zone such as Europe/Berlin, got %q (did you mean %q?)", k, value, suggestion)}
+		}
+		return nil, []error{fmt.Errorf("expected %s to be an IANA time zone such as Europe/Berlin, got %q", k, value)}
+	}
+	return nil, nil
+}
+
+// suggestTimezone fixes the capitalization of a zone name, which the database
+// is strict about: europe/berlin becomes Europe/Berlin.
+func suggestTimezone(value string) string {
+	parts := strings.Split(value, "/")
+	for i, part := range parts {
+		words := strings.Split(strings.ToLower(part), "_")
+		for j, word := range words {
+			if word != "" {
+				words[j] = strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	suggestion := strings.Join(parts, "/")
+	if suggestion == value {
+		return ""
+	}
+	if _, err := time.LoadLocation(suggestion); err != nil {
+		return ""
+	}
+	return suggestion
+}
+
+func validateISODuration(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseISODuration(value); err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be an ISO 8601 duration such as P7D or PT8H, got %q", k, value)}
+	}
+	return nil, nil
+}
+
+func validateScheduleDateTime(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseScheduleDateTime(value, time.UTC); err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a date time such as 2022-08-30T00:00, got %q", k, value)}
+	}
+	return nil, nil
+}
+
+func validateTimeOfDay(v any, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if !timeOfDayRegexp.MatchString(value) {
+		return nil, []error{fmt.Errorf("expected %s to be a time of day in the format HH:mm such as 08:00, got %q", k, value)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentISODuration hides the diff between durations that span the
+// same time, e.g. a configured PT60M and the PT1H the API answers with.
+func suppressEquivalentISODuration(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	o, err := parseISODuration(old)
+	if err != nil {
+		return false
+	}
+	n, err := parseISODuration(new)
+	if err != nil {
+		return false
+	}
+	return o.equivalent(n)
+}
+
+// suppressEquivalentScheduleDateTime hides the diff between notations of the
+// same instant in the resource's timezone, e.g. a configured 2022-08-30T00:00
+// and the 2022-08-30T00:00:00+02:00 the API answers with.
+func suppressEquivalentScheduleDateTime(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return old == new
+	}
+	loc := time.UTC
+	if timezone, ok := d.Get("timezone").(string); ok && timezone != "" {
+		if l, err := time.LoadLocation(timezone); err == nil {
+			loc = l
+		}
+	}
+	return sameScheduleDateTime(old, new, loc)
+}
+
+// suppressEquivalentTimeOfDay hides the diff between 08:00 and the 08:00:00
+// some endpoints answer with.
+func suppressEquivalentTimeOfDay(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeTimeOfDay(old) == normalizeTimeOfDay(new)
+}
+
+// suppressEquivalentTimestamp hides the diff between two RFC 3339 timestamps
+// of the same instant, such as a configured offset and the UTC the API answers
+// with.
+func suppressEquivalentTimestamp(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	o, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	n, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return o.Equal(n)
+}
+
+func normalizeTimeOfDay(value string) string {
+	if len(value) == len("15:04:05") && strings.HasSuffix(value, ":00") {
+		return value[:len("15:04")]
+	}
+	return value
+}
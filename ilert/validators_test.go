@@ -0,0 +1,142 @@
+package ilert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestValidateTimezone(t *testing.T) {
+	cases := []struct {
+		value   string
+		wantErr string
+	}{
+		{"Europe/Berlin", ""},
+		{"America/Argentina/Buenos_Aires", ""},
+		{"UTC", ""},
+		{"", "IANA time zone"},
+		{"Local", "IANA time zone"},
+		{"Europe/Berlinn", "IANA time zone"},
+		{"CEST", "IANA time zone"},
+		{"europe/berlin", `did you mean "Europe/Berlin"?`},
+		{"america/new_york", `did you mean "America/New_York"?`},
+	}
+	for _, tc := range cases {
+		_, errs := validateTimezone(tc.value, "timezone")
+		if tc.wantErr == "" {
+			if len(errs) > 0 {
+				t.Errorf("validateTimezone(%q) unexpected error: %v", tc.value, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantErr) {
+			t.Errorf("validateTimezone(%q) expected an error containing %q, got %v", tc.value, tc.wantErr, errs)
+		}
+	}
+}
+
+func TestValidateTimeOfDay(t *testing.T) {
+	for _, value := range []string{"00:00", "08:00", "17:30", "23:59"} {
+		if _, errs := validateTimeOfDay(value, "start"); len(errs) > 0 {
+			t.Errorf("validateTimeOfDay(%q) unexpected error: %v", value, errs)
+		}
+	}
+	for _, value := range []string{"", "8:00", "24:00", "12:60", "08:00:00", "0800", "08:00 "} {
+		if _, errs := validateTimeOfDay(value, "start"); len(errs) == 0 {
+			t.Errorf("validateTimeOfDay(%q) expected an error", value)
+		}
+	}
+}
+
+func TestValidateScheduleDateTime(t *testing.T) {
+	for _, value := range []string{"2022-08-30T00:00", "2022-08-30T00:00:00", "2022-08-30T00:00:00+02:00", "2022-08-30T00:00:00Z"} {
+		if _, errs := validateScheduleDateTime(value, "starts_on"); len(errs) > 0 {
+			t.Errorf("validateScheduleDateTime(%q) unexpected error: %v", value, errs)
+		}
+	}
+	for _, value := range []string{"", "2022-08-30", "30.08.2022 00:00", "2022-08-30T25:00", "2022-02-30T00:00"} {
+		if _, errs := validateScheduleDateTime(value, "starts_on"); len(errs) == 0 {
+			t.Errorf("validateScheduleDateTime(%q) expected an error", value)
+		}
+	}
+}
+
+func TestSuppressEquivalentISODuration(t *testing.T) {
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{"PT1H", "PT60M", true},
+		{"PT1H30M", "PT90M", true},
+		{"P1W", "P7D", true},
+		{"P1Y", "P12M", true},
+		{"P1D", "PT24H", false},
+		{"P7D", "P1D", false},
+		{"P7D", "not a duration", false},
+		{"", "P7D", false},
+	}
+	for _, tc := range cases {
+		if got := suppressEquivalentISODuration("rotation", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("suppressEquivalentISODuration(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}
+
+func TestSuppressEquivalentScheduleDateTime_UsesResourceTimezone(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceSchedule().Schema, map[string]any{
+		"name":     "test-schedule",
+		"type":     "STATIC",
+		"timezone": "Europe/Berlin",
+	})
+
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{"2022-08-30T00:00:00+02:00", "2022-08-30T00:00", true},
+		{"2022-08-29T22:00:00Z", "2022-08-30T00:00", true},
+		{"2022-08-30T00:00:00", "2022-08-30T00:00", true},
+		{"2022-08-30T00:00:00Z", "2022-08-30T00:00", false},
+		{"", "2022-08-30T00:00", false},
+	}
+	for _, tc := range cases {
+		if got := suppressEquivalentScheduleDateTime("starts_on", tc.old, tc.new, d); got != tc.want {
+			t.Errorf("suppressEquivalentScheduleDateTime(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}
+
+func TestSuppressEquivalentTimeOfDay(t *testing.T) {
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{"08:00:00", "08:00", true},
+		{"08:00", "08:00", true},
+		{"08:30:00", "08:00", false},
+		{"08:00:30", "08:00", false},
+	}
+	for _, tc := range cases {
+		if got := suppressEquivalentTimeOfDay("start", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("suppressEquivalentTimeOfDay(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}
+
+func TestSuppressEquivalentTimestamp(t *testing.T) {
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{"2026-12-31T00:00:00Z", "2026-12-31T01:00:00+01:00", true},
+		{"2026-12-31T00:00:00.000Z", "2026-12-31T00:00:00Z", true},
+		{"2026-12-31T00:00:00Z", "2026-12-31T00:00:01Z", false},
+		{"", "2026-12-31T00:00:00Z", false},
+	}
+	for _, tc := range cases {
+		if got := suppressEquivalentTimestamp("expires_at", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("suppressEquivalentTimestamp(%q, %q) = %t, want %t", tc.old, tc.new, got, tc.want)
+		}
+	}
+}